@@ -0,0 +1,117 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/mbvlabs/plyo-hackathon/providers"
+	"github.com/mbvlabs/plyo-hackathon/tools"
+	"github.com/openai/openai-go/v2"
+)
+
+const ChangeDetectionJobName = "change_detection_job"
+
+type ChangeDetectionJobParams struct {
+	WatchlistChangeID uuid.UUID `json:"watchlist_change_id"`
+}
+
+type ChangeSummary struct {
+	HasMaterialChanges bool             `json:"has_material_changes" jsonschema:"required" jsonschema_description:"Whether anything materially changed between the two reports"`
+	Headline           string           `json:"headline"             jsonschema:"required" jsonschema_description:"One sentence summarising what changed"`
+	Changes            []MaterialChange `json:"changes"              jsonschema:"required" jsonschema_description:"The material changes found, most important first"`
+}
+
+type MaterialChange struct {
+	Category    string `json:"category"    jsonschema:"required,enum=funding,enum=leadership,enum=competitors,enum=product,enum=market,enum=legal,enum=other" jsonschema_description:"The kind of change"`
+	Description string `json:"description" jsonschema:"required" jsonschema_description:"What changed, including the previous and current state when known"`
+}
+
+// Markdown renders the summary in the format stored on watchlist changes.
+func (c ChangeSummary) Markdown() string {
+	var b strings.Builder
+	b.WriteString(c.Headline)
+
+	for _, change := range c.Changes {
+		fmt.Fprintf(&b, "\n- **%s**: %s", change.Category, change.Description)
+	}
+
+	return b.String()
+}
+
+const changeDetectionSystemPrompt = `
+You are a Change Detection Agent monitoring companies on an investment watchlist. You compare two versions of a research report about the same company and report what materially changed between them.
+
+Material changes include:
+- New funding rounds, acquisitions, or changes in ownership
+- Leadership changes such as new or departed executives and board members
+- New competitors or significant moves by existing competitors
+- Major product launches, pivots, or discontinued offerings
+- Market, regulatory, or legal developments that affect the company
+
+Ignore differences in wording, formatting, section order, or confidence scores when the underlying facts are the same. Do not report information that merely appears in one report because the other one missed it unless the new report clearly dates it after the previous report.
+`
+
+var changeSummarySchema = openai.ResponseFormatJSONSchemaJSONSchemaParam{
+	Name:        "change_summary",
+	Description: openai.String("Material changes between two research reports on the same company"),
+	Schema:      GenerateSchema[ChangeSummary](),
+	Strict:      openai.Bool(true),
+}
+
+type ChangeDetection struct {
+	client providers.Client
+	tools  tools.Toolset
+}
+
+func NewChangeDetection(
+	client providers.Client,
+	tools tools.Toolset,
+) ChangeDetection {
+	return ChangeDetection{
+		client: client,
+		tools:  tools,
+	}
+}
+
+func (r ChangeDetection) Compare(
+	ctx context.Context,
+	companyName string,
+	previousReport string,
+	currentReport string,
+) (ChangeSummary, error) {
+	userPrompt := fmt.Sprintf(`
+Compare the two research reports for %s and list what materially changed.
+
+PREVIOUS REPORT:
+%s
+
+CURRENT REPORT:
+%s
+`,
+		companyName,
+		previousReport,
+		currentReport,
+	)
+
+	response, err := r.client.Prompt(
+		ctx,
+		providers.GPT41Mini,
+		changeDetectionSystemPrompt,
+		userPrompt,
+		r.tools,
+		&changeSummarySchema,
+	)
+	if err != nil {
+		return ChangeSummary{}, fmt.Errorf("failed to compare reports: %w", err)
+	}
+
+	var summary ChangeSummary
+	if err := json.Unmarshal([]byte(response), &summary); err != nil {
+		return ChangeSummary{}, fmt.Errorf("failed to unmarshal change summary: %w", err)
+	}
+
+	return summary, nil
+}
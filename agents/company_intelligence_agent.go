@@ -27,19 +27,27 @@ You are a Company Intelligence Agent specialized in gathering comprehensive comp
 - Flag any compliance issues, controversies, or risk factors
 - Output structured company profiles with confidence scores for each data point
 
-You must always use the scrapingbee_scraper to get a complete idea of the company's website.
+You must always crawl the company's website with site_crawl to get a complete idea of it, and read single pages in full with scrape_page. For companies outside the US, also search with their country and language.
 
 Always verify information from multiple sources and note data freshness. Focus on factual, business-relevant intelligence.
 	`
 
+// CompanyIntelligenceTools are the tools the company intelligence agent may
+// use. It reads the company's own website more than any other agent.
+var CompanyIntelligenceTools = []tools.Allowance{
+	{Tool: tools.SearchToolName},
+	{Tool: tools.ScrapeChainToolName, Quota: 8},
+	{Tool: tools.SiteCrawlToolName, Quota: 2},
+}
+
 type CompanyIntelligence struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewCompanyIntelligence(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) CompanyIntelligence {
 	return CompanyIntelligence{
 		client: client,
@@ -51,6 +59,7 @@ func (r CompanyIntelligence) Research(
 	ctx context.Context,
 	companyName string,
 	companyURL string,
+	prior PriorFindings,
 ) (string, error) {
 	userPrompt := fmt.Sprintf(`
 		Conduct comprehensive company intelligence research for %s. 
@@ -71,6 +80,7 @@ func (r CompanyIntelligence) Research(
 		companyName,
 		companyURL,
 	)
+	userPrompt += prior.prompt()
 
 	response, err := r.client.Prompt(
 		ctx,
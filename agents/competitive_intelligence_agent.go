@@ -31,7 +31,7 @@ Use the tools available to your disposal to search and gather competitive intell
 1. First search for "[company name] competitors"
 2. Search for "[company name] vs [competitor]" comparisons
 3. Search for "[industry] market landscape [current year]"
-4. Search for recent funding/partnership news
+4. Search the news for recent funding/partnership news, limited to the past year
 
 Make sure to scrape the competitors website and their about page to have full context.
 
@@ -39,14 +39,21 @@ Always provide sources and explain your search methodology.
 
 Prioritize current, publicly available information. Classify competitors by threat level and market overlap. Provide actionable competitive insights.`
 
+// CompetitiveIntelligenceTools are the tools the competitive intelligence
+// agent may use.
+var CompetitiveIntelligenceTools = []tools.Allowance{
+	{Tool: tools.SearchToolName},
+	{Tool: tools.ScrapeChainToolName, Quota: 6},
+}
+
 type CompetitiveIntelligence struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewCompetitiveIntelligence(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) CompetitiveIntelligence {
 	return CompetitiveIntelligence{
 		client: client,
@@ -58,6 +65,7 @@ func (r CompetitiveIntelligence) Research(
 	ctx context.Context,
 	companyName string,
 	companyURL string,
+	prior PriorFindings,
 ) (string, error) {
 	userPrompt := fmt.Sprintf(
 		`
@@ -77,6 +85,7 @@ Use %s as your starting point to understand their positioning, then create compe
 		companyURL,
 		companyURL,
 	)
+	userPrompt += prior.prompt()
 
 	response, err := r.client.Prompt(
 		ctx,
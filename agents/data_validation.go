@@ -21,14 +21,21 @@ You are a Data Validation Agent responsible for ensuring research quality and ac
 Maintain strict quality standards. Clearly distinguish between verified facts, estimates, and assumptions.
 `
 
+// DataValidationTools are the tools the validator may use. It checks
+// claims against a few sources rather than researching anew.
+var DataValidationTools = []tools.Allowance{
+	{Tool: tools.SearchToolName, Quota: 8},
+	{Tool: tools.ScrapeChainToolName, Quota: 3},
+}
+
 type DataValidation struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewDataValidation(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) DataValidation {
 	return DataValidation{
 		client: client,
@@ -30,14 +30,20 @@ You are a Market Dynamics Agent specializing in market analysis and sizing. Your
 Use multiple data sources and methodologies. Provide quantified insights with clear assumptions and limitations.
 `
 
+// MarketDynamicsTools are the tools the market dynamics agent may use.
+var MarketDynamicsTools = []tools.Allowance{
+	{Tool: tools.SearchToolName},
+	{Tool: tools.ScrapeChainToolName, Quota: 6},
+}
+
 type MarketDynamics struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewMarketDynamics(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) MarketDynamics {
 	return MarketDynamics{
 		client: client,
@@ -49,6 +55,7 @@ func (r MarketDynamics) Research(
 	ctx context.Context,
 	companyName string,
 	companyURL string,
+	prior PriorFindings,
 ) (string, error) {
 	userPrompt := fmt.Sprintf(
 		`
@@ -69,6 +76,7 @@ Reference %s to understand their market positioning and provide quantified marke
 		companyURL,
 		companyURL,
 	)
+	userPrompt += prior.prompt()
 
 	response, err := r.client.Prompt(
 		ctx,
@@ -5,11 +5,22 @@ import (
 	"encoding/json"
 	"fmt"
 
+	"github.com/google/uuid"
 	"github.com/mbvlabs/plyo-hackathon/providers"
 	"github.com/mbvlabs/plyo-hackathon/tools"
 	"github.com/openai/openai-go/v2"
 )
 
+const PreliminaryResearchJobName = "preliminary_research_job"
+
+// PreliminaryResearchJobParams identifies what to research: a batch row, or
+// a research brief requested through the API. Briefs researched from the UI
+// run synchronously instead.
+type PreliminaryResearchJobParams struct {
+	BatchRowID      uuid.UUID `json:"batch_row_id"`
+	ResearchBriefID uuid.UUID `json:"research_brief_id"`
+}
+
 // RESEARCH BRIEF - [Company Name]
 // ================================
 //
@@ -91,14 +102,21 @@ var researchBriefSchema = openai.ResponseFormatJSONSchemaJSONSchemaParam{
 	Strict: openai.Bool(true),
 }
 
+// PreliminaryResearchTools are the tools the preliminary researcher may
+// use. It only has to tell the company apart from others of the same name.
+var PreliminaryResearchTools = []tools.Allowance{
+	{Tool: tools.SearchToolName, Quota: 6},
+	{Tool: tools.ScrapeChainToolName, Quota: 3},
+}
+
 type PreliminaryResearch struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewPreliminaryResearch(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) PreliminaryResearch {
 	return PreliminaryResearch{
 		client: client,
@@ -0,0 +1,37 @@
+package agents
+
+import (
+	"fmt"
+	"time"
+)
+
+// PriorFindings are the validated findings of an earlier report on the same
+// company. A domain agent offered them only researches what they lack or
+// what is likely to have changed since, which keeps repeat reports cheap.
+type PriorFindings struct {
+	Findings string
+	AsOf     time.Time
+}
+
+// prompt returns the instructions appended to a domain agent's user prompt,
+// or nothing when there are no prior findings.
+func (p PriorFindings) prompt() string {
+	if p.Findings == "" {
+		return ""
+	}
+
+	return fmt.Sprintf(`
+
+PRIOR FINDINGS (validated, researched as of %s):
+%s
+
+These findings were researched recently. Build on them instead of starting over:
+- Keep the facts that are still current without researching them again, and note them as "(as of %s)"
+- Only search and scrape for what the prior findings lack or flag as unverified, and for what is likely to have changed since, such as news, funding, leadership and competitor moves
+- Correct anything you find to be outdated and state what changed
+- Your response must still be complete on its own, covering every point above`,
+		p.AsOf.Format(time.DateOnly),
+		p.Findings,
+		p.AsOf.Format(time.DateOnly),
+	)
+}
@@ -3,6 +3,7 @@ package agents
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/mbvlabs/plyo-hackathon/providers"
@@ -41,12 +42,12 @@ Always include a section at the end that has all the sources used to generate th
 
 type ReportGenerator struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewReportGenerator(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) ReportGenerator {
 	return ReportGenerator{
 		client: client,
@@ -62,6 +63,30 @@ func (r ReportGenerator) Generate(
 	competitiveLandscapeAnalysis string,
 	marketDynamicsAssessment string,
 	industryTrendAnalysis string,
+) (string, error) {
+	return r.GenerateWithTemplate(
+		ctx,
+		companyName,
+		companyURL,
+		companyIntelligenceFindings,
+		competitiveLandscapeAnalysis,
+		marketDynamicsAssessment,
+		industryTrendAnalysis,
+		ReportTemplates[DefaultReportTemplate],
+	)
+}
+
+// GenerateWithTemplate generates the final report using template as the
+// outline of sections the report must contain.
+func (r ReportGenerator) GenerateWithTemplate(
+	ctx context.Context,
+	companyName string,
+	companyURL string,
+	companyIntelligenceFindings string,
+	competitiveLandscapeAnalysis string,
+	marketDynamicsAssessment string,
+	industryTrendAnalysis string,
+	template string,
 ) (string, error) {
 	userPrompt := fmt.Sprintf(`
 Generate a comprehensive business intelligence report for %s (%s).
@@ -79,13 +104,7 @@ INDUSTRY TRENDS ANALYSIS:
 %s
 
 Synthesize all findings into a cohesive executive report with:
-- Executive Summary
-- Key Strategic Insights  
-- Market Opportunities & Threats
-- Competitive Positioning
-- Strategic Recommendations
-- Risk Assessment
-- Confidence Scores for major conclusions
+%s
 
 Present the analysis in a structured format that enables strategic decision-making.`,
 		companyName,
@@ -94,6 +113,7 @@ Present the analysis in a structured format that enables strategic decision-maki
 		competitiveLandscapeAnalysis,
 		marketDynamicsAssessment,
 		industryTrendAnalysis,
+		strings.TrimSpace(template),
 	)
 
 	response, err := r.client.Prompt(
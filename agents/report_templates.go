@@ -0,0 +1,31 @@
+package agents
+
+const DefaultReportTemplate = "executive"
+
+// ReportTemplates holds the built-in section outlines the report generator
+// can follow, keyed by name.
+var ReportTemplates = map[string]string{
+	DefaultReportTemplate: `
+- Executive Summary
+- Key Strategic Insights
+- Market Opportunities & Threats
+- Competitive Positioning
+- Strategic Recommendations
+- Risk Assessment
+- Confidence Scores for major conclusions`,
+	"sales": `
+- Company Snapshot (what they do, size, locations, key people)
+- Recent News and Trigger Events
+- Likely Priorities and Pain Points
+- Competitive Positioning
+- Talking Points and Questions for a First Meeting
+- Confidence Scores for major conclusions`,
+	"investment": `
+- Executive Summary
+- Business Model and Traction
+- Market Size and Dynamics
+- Competitive Landscape
+- Team and Funding History
+- Key Risks and Open Questions
+- Confidence Scores for major conclusions`,
+}
@@ -23,12 +23,12 @@ Focus on delivering comprehensive, well-structured insights that directly addres
 
 type ResearchOrchestrator struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewResearchOrchestrator(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) ResearchOrchestrator {
 	return ResearchOrchestrator{
 		client: client,
@@ -30,14 +30,20 @@ You are a Trend Analysis Agent focused on identifying and analyzing industry tre
 Distinguish between short-term fluctuations and long-term trends. Provide probability-weighted scenarios and timeline estimates.
 `
 
+// TrendAnalysisTools are the tools the trend analysis agent may use.
+var TrendAnalysisTools = []tools.Allowance{
+	{Tool: tools.SearchToolName},
+	{Tool: tools.ScrapeChainToolName, Quota: 6},
+}
+
 type TrendAnalysis struct {
 	client providers.Client
-	tools  map[string]tools.Tooler
+	tools  tools.Toolset
 }
 
 func NewTrendAnalysis(
 	client providers.Client,
-	tools map[string]tools.Tooler,
+	tools tools.Toolset,
 ) TrendAnalysis {
 	return TrendAnalysis{
 		client: client,
@@ -49,6 +55,7 @@ func (r TrendAnalysis) Research(
 	ctx context.Context,
 	companyName string,
 	companyURL string,
+	prior PriorFindings,
 ) (string, error) {
 	userPrompt := fmt.Sprintf(
 		`
@@ -69,6 +76,7 @@ Review %s to understand their current approach, then distinguish between short-t
 		companyURL,
 		companyURL,
 	)
+	userPrompt += prior.prompt()
 
 	response, err := r.client.Prompt(
 		ctx,
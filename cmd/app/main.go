@@ -11,17 +11,19 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"syscall"
 	"time"
 
 	"github.com/mbvlabs/plyo-hackathon/agents"
 	"github.com/mbvlabs/plyo-hackathon/config"
 	"github.com/mbvlabs/plyo-hackathon/controllers"
 	"github.com/mbvlabs/plyo-hackathon/database"
-	"github.com/mbvlabs/plyo-hackathon/models"
 	"github.com/mbvlabs/plyo-hackathon/providers"
 	"github.com/mbvlabs/plyo-hackathon/router"
+	"github.com/mbvlabs/plyo-hackathon/services"
 	"github.com/mbvlabs/plyo-hackathon/tools"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/pressly/goose/v3"
@@ -32,6 +34,8 @@ import (
 
 var appVersion string
 
+const watchlistSchedulerInterval = time.Minute
+
 func startServer(ctx context.Context, srv *http.Server, env string) error {
 	if env == config.ProdEnvironment {
 		eg, egCtx := errgroup.WithContext(ctx)
@@ -86,9 +90,10 @@ func setupControllers(
 	return ctrl, nil
 }
 
-func setupRouter(ctrl controllers.Controllers) (*echo.Echo, error) {
+func setupRouter(ctrl controllers.Controllers, sqlite database.SQLite) (*echo.Echo, error) {
 	router, err := router.New(
 		ctrl,
+		sqlite,
 	)
 	if err != nil {
 		return nil, err
@@ -130,8 +135,26 @@ func runMigrations(ctx context.Context, db *sql.DB) error {
 	return nil
 }
 
+// failReport marks the report failed once its agent gave up. The provider
+// already retries failed completions with backoff, so the job is not handed
+// back to the queue for another full run.
+func failReport(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+	cause error,
+) error {
+	if err := services.FailReport(ctx, conn, q, reportID, cause, config.App.BatchReportConcurrency); err != nil {
+		slog.ErrorContext(ctx, "failed to mark report failed", "error", err, "report_id", reportID)
+		return err
+	}
+
+	return nil
+}
+
 func run(ctx context.Context) error {
-	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt)
+	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
 	defer cancel()
 
 	sqlite, err := database.NewSQLite(ctx)
@@ -156,24 +179,21 @@ func run(ctx context.Context) error {
 		Queue:        q,
 	})
 
-	serper := tools.NewSerper(config.App.SerperAPIkey)
-	serperScrape := tools.NewSerperScrape(config.App.SerperAPIkey)
 	openai := providers.NewClient(config.App.OpenAPIKey)
-	scrapingBee := tools.NewScrapingBee(config.App.ScrapingBeeAPIKey)
-
-	toolsMap := map[string]tools.Tooler{
-		serper.GetName():       &serper,
-		serperScrape.GetName(): &serperScrape,
-		scrapingBee.GetName():  &scrapingBee,
+	researchTools, toolCache, err := services.NewResearchTools(sqlite.Conn(), config.App.ToolCacheBypass)
+	if err != nil {
+		return err
 	}
 
 	// Create agents
-	companyIntel := agents.NewCompanyIntelligence(openai, toolsMap)
-	competitiveIntel := agents.NewCompetitiveIntelligence(openai, toolsMap)
-	marketDynamics := agents.NewMarketDynamics(openai, toolsMap)
-	trendAnalysis := agents.NewTrendAnalysis(openai, toolsMap)
-	dataValidator := agents.NewDataValidation(openai, toolsMap)
-	reportGenerator := agents.NewReportGenerator(openai, nil)
+	pipeline := services.NewPipeline(openai, researchTools, services.FindingsFreshness{
+		CompanyIntelligence:     config.App.CompanyIntelligenceMaxAge,
+		CompetitiveIntelligence: config.App.CompetitiveIntelligenceMaxAge,
+		MarketDynamics:          config.App.MarketDynamicsMaxAge,
+		TrendAnalysis:           config.App.TrendAnalysisMaxAge,
+	})
+	changeDetection := agents.NewChangeDetection(openai, tools.Toolset{})
+	prelimAgent := pipeline.PreliminaryResearch()
 
 	r.Register(agents.ReportGeneratorJobName, func(ctx context.Context, m []byte) error {
 		var params agents.ReportGeneratorJobParams
@@ -182,172 +202,132 @@ func run(ctx context.Context) error {
 			return err
 		}
 
-		report, err := models.FindReport(ctx, sqlite.Conn(), params.ReportID)
-		if err != nil {
-			return err
-		}
+		reportCtx, cancelReport := services.WithReportCancellation(ctx, sqlite.Conn(), params.ReportID)
+		defer cancelReport()
 
-		result, err := reportGenerator.Generate(
-			ctx,
+		if _, err := pipeline.GenerateReport(
+			reportCtx,
+			sqlite.Conn(),
+			params.ReportID,
 			params.CandidateName,
 			params.CompanyURL,
-			report.CompanyIntelligenceData,
-			report.CompetitiveIntelligenceData,
-			report.MarketDynamicsData,
-			report.TrendAnalysisData,
-		)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
+			"",
+		); err != nil {
+			if services.ReportCanceled(reportCtx) {
+				slog.InfoContext(ctx, "report generation stopped, report was canceled", "report_id", params.ReportID)
+				return nil
+			}
+			slog.ErrorContext(ctx, "failed to generate final report", "error", err)
+			return failReport(ctx, sqlite.Conn(), q, params.ReportID, err)
 		}
+		toolCache.LogStats(ctx)
 
-		if err := models.UpdateFinalReport(ctx, sqlite.Conn(), params.ReportID, result); err != nil {
-			slog.ErrorContext(ctx, "failed to update final report", "error", err)
-			return err
+		if err := services.CompleteReport(ctx, sqlite.Conn(), q, params.ReportID, config.App.BatchReportConcurrency); err != nil {
+			slog.ErrorContext(ctx, "failed to complete report", "error", err)
 		}
 
 		return nil
 	})
-	r.Register(agents.TrendAnalysisJobName, func(ctx context.Context, m []byte) error {
-		var params agents.TrendAnalysisJobParams
+	r.Register(agents.ChangeDetectionJobName, func(ctx context.Context, m []byte) error {
+		var params agents.ChangeDetectionJobParams
 		if err := json.Unmarshal(m, &params); err != nil {
 			slog.ErrorContext(ctx, "failed to unmarshal job params", "error", err)
 			return err
 		}
 
-		result, err := trendAnalysis.Research(ctx, params.CandidateName, params.CompanyURL)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
+		if err := services.DetectChanges(ctx, sqlite.Conn(), changeDetection, params.WatchlistChangeID); err != nil {
+			slog.ErrorContext(ctx, "change detection failed", "error", err)
 			return err
 		}
 
-		validatedResult, err := dataValidator.Research(
-			ctx,
-			params.CandidateName,
-			params.CompanyURL,
-			result,
-		)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
-		}
-		if err := models.UpdateTrendAnalysis(ctx, sqlite.Conn(), params.ReportID, validatedResult); err != nil {
-			slog.ErrorContext(ctx, "failed to update trend analysis", "error", err)
-			return err
-		}
-
-		if err := models.UpdateReportProgress(ctx, sqlite.Conn(), params.ReportID); err != nil {
-			slog.ErrorContext(ctx, "failed to update report progress", "error", err)
-			return err
-		}
-		slog.InfoContext(ctx, "completed update trend analysis", "report_id", params.ReportID)
 		return nil
 	})
-	r.Register(agents.MarketDynamicsJobName, func(ctx context.Context, m []byte) error {
-		var params agents.MarketDynamicsJobParams
+	r.Register(agents.PreliminaryResearchJobName, func(ctx context.Context, m []byte) error {
+		var params agents.PreliminaryResearchJobParams
 		if err := json.Unmarshal(m, &params); err != nil {
 			slog.ErrorContext(ctx, "failed to unmarshal job params", "error", err)
 			return err
 		}
 
-		result, err := marketDynamics.Research(ctx, params.CandidateName, params.CompanyURL)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
-		}
+		if params.ResearchBriefID != uuid.Nil {
+			if err := services.ResearchQueuedBrief(ctx, sqlite.Conn(), prelimAgent, params.ResearchBriefID); err != nil {
+				slog.ErrorContext(ctx, "research brief research failed", "error", err)
+				return err
+			}
 
-		validatedResult, err := dataValidator.Research(
-			ctx,
-			params.CandidateName,
-			params.CompanyURL,
-			result,
-		)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
+			return nil
 		}
 
-		if err := models.UpdateMarketDynamics(ctx, sqlite.Conn(), params.ReportID, validatedResult); err != nil {
-			slog.ErrorContext(ctx, "failed to update market dynamics", "error", err)
+		if err := services.ResearchBatchRow(
+			ctx,
+			sqlite.Conn(),
+			q,
+			prelimAgent,
+			params.BatchRowID,
+			config.App.BatchAutoSelectConfidence,
+			config.App.BatchReportConcurrency,
+		); err != nil {
+			slog.ErrorContext(ctx, "batch row research failed", "error", err)
 			return err
 		}
 
-		if err := models.UpdateReportProgress(ctx, sqlite.Conn(), params.ReportID); err != nil {
-			slog.ErrorContext(ctx, "failed to update report progress", "error", err)
-			return err
-		}
-		slog.InfoContext(ctx, "completed update market dynamics", "report_id", params.ReportID)
 		return nil
 	})
-	r.Register(agents.CompetitiveIntelligenceJobName, func(ctx context.Context, m []byte) error {
-		var params agents.CompanyIntelligenceJobParams
-		if err := json.Unmarshal(m, &params); err != nil {
-			slog.ErrorContext(ctx, "failed to unmarshal job params", "error", err)
-			return err
-		}
 
-		result, err := competitiveIntel.Research(ctx, params.CandidateName, params.CompanyURL)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
-		}
+	// The domain research jobs share the same params and differ only in the
+	// agent the pipeline runs for them.
+	for _, jobName := range []string{
+		agents.CompanyIntelligenceJobName,
+		agents.CompetitiveIntelligenceJobName,
+		agents.MarketDynamicsJobName,
+		agents.TrendAnalysisJobName,
+	} {
+		r.Register(jobName, func(ctx context.Context, m []byte) error {
+			var params agents.CompanyIntelligenceJobParams
+			if err := json.Unmarshal(m, &params); err != nil {
+				slog.ErrorContext(ctx, "failed to unmarshal job params", "error", err)
+				return err
+			}
 
-		validatedResult, err := dataValidator.Research(
-			ctx,
-			params.CandidateName,
-			params.CompanyURL,
-			result,
-		)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
-		}
+			reportCtx, cancelReport := services.WithReportCancellation(ctx, sqlite.Conn(), params.ReportID)
+			defer cancelReport()
+
+			if err := pipeline.ResearchDomain(
+				reportCtx,
+				sqlite.Conn(),
+				jobName,
+				params.ReportID,
+				params.CandidateName,
+				params.CompanyURL,
+			); err != nil {
+				if services.ReportCanceled(reportCtx) {
+					slog.InfoContext(ctx, "research stopped, report was canceled", "job", jobName, "report_id", params.ReportID)
+					return nil
+				}
+				slog.ErrorContext(ctx, "research failed", "error", err, "job", jobName)
+				return failReport(ctx, sqlite.Conn(), q, params.ReportID, err)
+			}
 
-		if err := models.UpdateCompetitiveIntelligence(ctx, sqlite.Conn(), params.ReportID, validatedResult); err != nil {
-			slog.ErrorContext(ctx, "failed to update competitive intelligence", "error", err)
-			return err
-		}
+			if err := services.CompleteResearchSection(ctx, sqlite.Conn(), q, jobName, params.ReportID); err != nil {
+				slog.ErrorContext(ctx, "failed to complete research section", "error", err)
+			}
+			slog.InfoContext(ctx, "completed research", "job", jobName, "report_id", params.ReportID)
+			return nil
+		})
+	}
 
-		if err := models.UpdateReportProgress(ctx, sqlite.Conn(), params.ReportID); err != nil {
-			slog.ErrorContext(ctx, "failed to update report progress", "error", err)
-			return err
-		}
-		return nil
-	})
-	r.Register(agents.CompanyIntelligenceJobName, func(ctx context.Context, m []byte) error {
-		var params agents.CompanyIntelligenceJobParams
+	r.Register(services.WebhookDeliveryJobName, func(ctx context.Context, m []byte) error {
+		var params services.WebhookDeliveryJobParams
 		if err := json.Unmarshal(m, &params); err != nil {
 			slog.ErrorContext(ctx, "failed to unmarshal job params", "error", err)
 			return err
 		}
 
-		result, err := companyIntel.Research(ctx, params.CandidateName, params.CompanyURL)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
+		if err := services.DeliverWebhook(ctx, sqlite.Conn(), q, params.DeliveryID); err != nil {
+			slog.ErrorContext(ctx, "webhook delivery failed", "error", err, "delivery_id", params.DeliveryID)
 			return err
 		}
 
-		validatedResult, err := dataValidator.Research(
-			ctx,
-			params.CandidateName,
-			params.CompanyURL,
-			result,
-		)
-		if err != nil {
-			slog.ErrorContext(ctx, "research failed", "error", err)
-			return err
-		}
-
-		if err := models.UpdateCompanyIntelligence(ctx, sqlite.Conn(), params.ReportID, validatedResult); err != nil {
-			slog.ErrorContext(ctx, "failed to update company intelligence", "error", err)
-			return err
-		}
-
-		if err := models.UpdateReportProgress(ctx, sqlite.Conn(), params.ReportID); err != nil {
-			slog.ErrorContext(ctx, "failed to update report progress", "error", err)
-			return err
-		}
-		slog.InfoContext(ctx, "completed company intelligence", "report_id", params.ReportID)
 		return nil
 	})
 
@@ -355,20 +335,14 @@ func run(ctx context.Context) error {
 		r.Start(ctx)
 	}()
 
-	// Create prelim agent
-	prelimAgent := agents.NewPreliminaryResearch(
-		openai,
-		map[string]tools.Tooler{
-			serper.GetName():       &serper,
-			serperScrape.GetName(): &serperScrape,
-		},
-	)
+	go services.RunWatchlistScheduler(ctx, sqlite.Conn(), q, watchlistSchedulerInterval)
+
 	controllers, err := setupControllers(sqlite, q, prelimAgent)
 	if err != nil {
 		return err
 	}
 
-	handler, err := setupRouter(controllers)
+	handler, err := setupRouter(controllers, sqlite)
 	if err != nil {
 		return err
 	}
@@ -8,25 +8,21 @@ import (
 	"github.com/mbvlabs/plyo-hackathon/agents"
 	"github.com/mbvlabs/plyo-hackathon/config"
 	"github.com/mbvlabs/plyo-hackathon/providers"
-	"github.com/mbvlabs/plyo-hackathon/tools"
+	"github.com/mbvlabs/plyo-hackathon/services"
 )
 
 func main() {
 	ctx := context.Background()
-	serper := tools.NewSerper(config.App.SerperAPIkey)
-	serperScrape := tools.NewSerperScrape(config.App.SerperAPIkey)
-	scrapingBee := tools.NewScrapingBee(config.App.ScrapingBeeAPIKey)
 	openai := providers.NewClient(config.App.OpenAPIKey)
 
-	toolsMap := map[string]tools.Tooler{
-		serper.GetName():       &serper,
-		serperScrape.GetName(): &serperScrape,
-		scrapingBee.GetName():  &scrapingBee,
+	researchTools, _, err := services.NewResearchTools(nil, false)
+	if err != nil {
+		log.Fatalf("Failed to set up tools: %v", err)
 	}
 
 	// Create research agent
-	agent := agents.NewCompetitiveIntelligence(openai, toolsMap)
-	validator := agents.NewDataValidation(openai, toolsMap)
+	agent := agents.NewCompetitiveIntelligence(openai, researchTools.CompetitiveIntelligence)
+	validator := agents.NewDataValidation(openai, researchTools.DataValidation)
 
 	// Test the agent
 	companyName := "kfund"
@@ -34,7 +30,7 @@ func main() {
 
 	fmt.Printf("Researching company: %s\n\n", companyName)
 
-	result, err := agent.Research(ctx, companyName, companyURL)
+	result, err := agent.Research(ctx, companyName, companyURL, agents.PriorFindings{})
 	if err != nil {
 		log.Fatalf("Research failed: %v", err)
 	}
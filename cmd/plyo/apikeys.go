@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+const apiKeysUsage = `Usage: plyo api-keys <command> [flags]
+
+Commands:
+  create    Create an API key for a user and print it once
+  list      List API keys without revealing them
+  revoke    Revoke an API key by id
+
+Keys belong to a workspace and act with the role their owner has in it, so
+the owner must be a member. Every command takes -workspace, which defaults
+to the workspace holding data from before workspaces existed.
+`
+
+func apiKeys(args []string) int {
+	if len(args) < 1 {
+		fmt.Fprint(os.Stderr, apiKeysUsage)
+		return exitUsage
+	}
+
+	flags := flag.NewFlagSet("api-keys "+args[0], flag.ContinueOnError)
+	dbPath := flags.String("db", "", "SQLite database holding the keys (default DB_PATH)")
+	workspace := flags.String("workspace", models.DefaultWorkspaceID.String(), "id of the workspace the keys belong to")
+
+	var run func(ctx context.Context, f *flag.FlagSet) int
+
+	switch args[0] {
+	case "create":
+		owner := flags.String("owner", "", "email of the user the key belongs to (required)")
+		name := flags.String("name", "", "what the key is used for, e.g. \"notebooks\" (required)")
+		run = func(ctx context.Context, _ *flag.FlagSet) int {
+			return createAPIKey(ctx, *dbPath, *workspace, *owner, *name)
+		}
+	case "list":
+		run = func(ctx context.Context, _ *flag.FlagSet) int {
+			return listAPIKeys(ctx, *dbPath, *workspace)
+		}
+	case "revoke":
+		run = func(ctx context.Context, f *flag.FlagSet) int {
+			if f.NArg() != 1 {
+				fmt.Fprintln(os.Stderr, "usage: plyo api-keys revoke [flags] <id>")
+				return exitUsage
+			}
+			return revokeAPIKey(ctx, *dbPath, *workspace, f.Arg(0))
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown api-keys command %q\n\n%s", args[0], apiKeysUsage)
+		return exitUsage
+	}
+
+	if err := flags.Parse(args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	return run(context.Background(), flags)
+}
+
+func createAPIKey(ctx context.Context, dbPath, rawWorkspaceID, owner, name string) int {
+	workspaceID, err := uuid.Parse(rawWorkspaceID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid workspace id %q\n", rawWorkspaceID)
+		return exitUsage
+	}
+
+	conn, cleanup, err := openDatabase(ctx, dbPath, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		return exitError
+	}
+	defer cleanup()
+
+	if _, err := models.FindWorkspace(ctx, conn, workspaceID); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find workspace %s: %v\n", workspaceID, err)
+		return exitError
+	}
+
+	user, err := models.FindUserByEmail(ctx, conn, owner)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to find user %s: %v\n", owner, err)
+		return exitError
+	}
+	if _, err := models.FindWorkspaceMembershipByUserID(ctx, conn, workspaceID, user.ID); err != nil {
+		fmt.Fprintf(os.Stderr, "%s is not a member of workspace %s: %v\n", owner, workspaceID, err)
+		return exitError
+	}
+
+	key, token, err := models.CreateAPIKey(ctx, conn, models.CreateAPIKeyData{
+		UserID:      user.ID,
+		Name:        name,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create api key: %v\n", err)
+		return exitError
+	}
+
+	fmt.Fprintf(os.Stderr, "Created key %s for %s. Store it now, it cannot be shown again.\n", key.ID, user.Email)
+	fmt.Println(token)
+
+	return exitOK
+}
+
+func listAPIKeys(ctx context.Context, dbPath, rawWorkspaceID string) int {
+	workspaceID, err := uuid.Parse(rawWorkspaceID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid workspace id %q\n", rawWorkspaceID)
+		return exitUsage
+	}
+
+	conn, cleanup, err := openDatabase(ctx, dbPath, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		return exitError
+	}
+	defer cleanup()
+
+	keys, err := models.AllAPIKeys(ctx, conn, workspaceID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to list api keys: %v\n", err)
+		return exitError
+	}
+
+	owners := map[string]string{}
+	for _, key := range keys {
+		if _, ok := owners[key.UserID]; ok {
+			continue
+		}
+		userID, err := uuid.Parse(key.UserID)
+		if err != nil {
+			continue
+		}
+		user, err := models.FindUser(ctx, conn, userID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to find owner of api key %s: %v\n", key.ID, err)
+			return exitError
+		}
+		owners[key.UserID] = user.Email
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tOWNER\tNAME\tPREFIX\tCREATED\tLAST USED\tSTATUS")
+	for _, key := range keys {
+		status := "active"
+		if key.Revoked() {
+			status = "revoked " + key.RevokedAt.Format(time.DateOnly)
+		}
+
+		lastUsed := "never"
+		if !key.LastUsedAt.IsZero() {
+			lastUsed = key.LastUsedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s…\t%s\t%s\t%s\n",
+			key.ID,
+			owners[key.UserID],
+			key.Name,
+			key.Prefix,
+			key.CreatedAt.Format(time.DateOnly),
+			lastUsed,
+			status,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return exitError
+	}
+
+	return exitOK
+}
+
+func revokeAPIKey(ctx context.Context, dbPath, rawWorkspaceID, rawID string) int {
+	workspaceID, err := uuid.Parse(rawWorkspaceID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid workspace id %q\n", rawWorkspaceID)
+		return exitUsage
+	}
+
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid api key id %q\n", rawID)
+		return exitUsage
+	}
+
+	conn, cleanup, err := openDatabase(ctx, dbPath, false)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		return exitError
+	}
+	defer cleanup()
+
+	revoked, err := models.RevokeAPIKey(ctx, conn, workspaceID, id)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to revoke api key: %v\n", err)
+		return exitError
+	}
+
+	if !revoked {
+		fmt.Fprintf(os.Stderr, "no active api key with id %s in workspace %s\n", id, workspaceID)
+		return exitError
+	}
+
+	fmt.Fprintf(os.Stderr, "Revoked key %s\n", id)
+	return exitOK
+}
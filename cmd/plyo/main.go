@@ -0,0 +1,439 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/providers"
+	"github.com/mbvlabs/plyo-hackathon/services"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/pressly/goose/v3"
+)
+
+const (
+	formatMarkdown = "markdown"
+	formatJSON     = "json"
+)
+
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitUsage     = 2
+	exitAmbiguous = 3
+)
+
+const usage = `Usage: plyo <command> [flags]
+
+Commands:
+  research    Research a company end to end and write the report
+  api-keys    Create, list and revoke API keys for the JSON API
+  mock-idp    Run an OpenID Connect identity provider to try single sign-on
+
+Run "plyo <command> -h" for the flags of a command.
+`
+
+type researchFlags struct {
+	name          string
+	url           string
+	template      string
+	out           string
+	format        string
+	dbPath        string
+	ephemeral     bool
+	minConfidence float64
+	timeout       time.Duration
+	refresh       bool
+	verbose       bool
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(exitUsage)
+	}
+
+	switch os.Args[1] {
+	case "research":
+		os.Exit(research(os.Args[2:]))
+	case "api-keys":
+		os.Exit(apiKeys(os.Args[2:]))
+	case "mock-idp":
+		os.Exit(mockIDPCommand(os.Args[2:]))
+	case "-h", "--help", "help":
+		fmt.Fprint(os.Stdout, usage)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(exitUsage)
+	}
+}
+
+func research(args []string) int {
+	var f researchFlags
+
+	flags := flag.NewFlagSet("research", flag.ContinueOnError)
+	flags.StringVar(&f.name, "name", "", "company name to research (required)")
+	flags.StringVar(&f.url, "url", "", "company website, used to identify the right company")
+	flags.StringVar(
+		&f.template,
+		"template",
+		agents.DefaultReportTemplate,
+		"report template: one of "+strings.Join(templateNames(), ", ")+", or a path to a markdown file with the section outline",
+	)
+	flags.StringVar(&f.out, "out", "", "file to write the result to (default stdout)")
+	flags.StringVar(&f.format, "format", "", "output format: markdown or json (default from --out extension, else markdown)")
+	flags.StringVar(&f.dbPath, "db", "", "SQLite database to store the research in (default DB_PATH)")
+	flags.BoolVar(&f.ephemeral, "ephemeral", false, "use a temporary database that is removed afterwards")
+	flags.Float64Var(&f.minConfidence, "min-confidence", 0, "identification confidence (0-1) required to continue without disambiguation")
+	flags.DurationVar(&f.timeout, "timeout", 30*time.Minute, "maximum time for the whole run")
+	flags.BoolVar(&f.refresh, "refresh", false, "fetch every search and page again instead of using cached results")
+	flags.BoolVar(&f.verbose, "verbose", false, "log progress to stderr")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if f.name == "" {
+		fmt.Fprintln(os.Stderr, "--name is required")
+		flags.Usage()
+		return exitUsage
+	}
+
+	format, err := outputFormat(f.format, f.out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	template, err := reportTemplate(f.template)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitUsage
+	}
+
+	level := slog.LevelWarn
+	if f.verbose {
+		level = slog.LevelInfo
+	}
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: level})))
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	ctx, cancelTimeout := context.WithTimeout(ctx, f.timeout)
+	defer cancelTimeout()
+
+	conn, cleanup, err := openDatabase(ctx, f.dbPath, f.ephemeral)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open database: %v\n", err)
+		return exitError
+	}
+	defer cleanup()
+
+	researchTools, toolCache, err := services.NewResearchTools(conn, f.refresh || config.App.ToolCacheBypass)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to set up tools: %v\n", err)
+		return exitError
+	}
+	openai := providers.NewClient(config.App.OpenAPIKey)
+
+	pipeline := services.NewPipeline(
+		openai,
+		researchTools,
+		services.FindingsFreshness{
+			CompanyIntelligence:     config.App.CompanyIntelligenceMaxAge,
+			CompetitiveIntelligence: config.App.CompetitiveIntelligenceMaxAge,
+			MarketDynamics:          config.App.MarketDynamicsMaxAge,
+			TrendAnalysis:           config.App.TrendAnalysisMaxAge,
+		},
+	)
+
+	slog.InfoContext(ctx, "starting research", "name", f.name, "url", f.url)
+
+	result, runErr := pipeline.Run(ctx, conn, services.RunOptions{
+		CompanyName:   f.name,
+		CompanyURL:    f.url,
+		Template:      template,
+		MinConfidence: f.minConfidence,
+	})
+	toolCache.LogStats(ctx)
+	if runErr != nil && !errors.Is(runErr, services.ErrAmbiguousCompany) {
+		fmt.Fprintf(os.Stderr, "research failed: %v\n", runErr)
+		return exitError
+	}
+
+	out, closeOut, err := openOutput(f.out)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to open output: %v\n", err)
+		return exitError
+	}
+	defer closeOut()
+
+	if errors.Is(runErr, services.ErrAmbiguousCompany) {
+		return writeAmbiguous(out, format, f.name, result)
+	}
+
+	if err := writeResult(out, format, result); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to write result: %v\n", err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+// outputFormat resolves the explicit format flag or falls back to the
+// extension of the output file.
+func outputFormat(format, out string) (string, error) {
+	switch strings.ToLower(format) {
+	case formatMarkdown, "md":
+		return formatMarkdown, nil
+	case formatJSON:
+		return formatJSON, nil
+	case "":
+		if strings.EqualFold(filepath.Ext(out), ".json") {
+			return formatJSON, nil
+		}
+		return formatMarkdown, nil
+	default:
+		return "", fmt.Errorf("unknown format %q, use markdown or json", format)
+	}
+}
+
+// reportTemplate returns a built-in template by name or reads the outline
+// from a file.
+func reportTemplate(nameOrPath string) (string, error) {
+	if template, ok := agents.ReportTemplates[nameOrPath]; ok {
+		return template, nil
+	}
+
+	content, err := os.ReadFile(nameOrPath)
+	if err != nil {
+		return "", fmt.Errorf(
+			"template %q is neither a built-in template (%s) nor a readable file: %w",
+			nameOrPath,
+			strings.Join(templateNames(), ", "),
+			err,
+		)
+	}
+
+	if strings.TrimSpace(string(content)) == "" {
+		return "", fmt.Errorf("template file %q is empty", nameOrPath)
+	}
+
+	return string(content), nil
+}
+
+func templateNames() []string {
+	names := make([]string, 0, len(agents.ReportTemplates))
+	for name := range agents.ReportTemplates {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func openDatabase(
+	ctx context.Context,
+	path string,
+	ephemeral bool,
+) (*sql.DB, func(), error) {
+	cleanup := func() {}
+
+	switch {
+	case ephemeral:
+		dir, err := os.MkdirTemp("", "plyo-*")
+		if err != nil {
+			return nil, nil, err
+		}
+		path = filepath.Join(dir, "plyo.db")
+		cleanup = func() { os.RemoveAll(dir) }
+	case path == "":
+		path = config.DB.GetDatabaseURL()
+	}
+
+	conn, err := sql.Open("sqlite3", path)
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+
+	if err := migrate(ctx, conn); err != nil {
+		conn.Close()
+		cleanup()
+		return nil, nil, err
+	}
+
+	return conn, func() {
+		conn.Close()
+		cleanup()
+	}, nil
+}
+
+func migrate(ctx context.Context, conn *sql.DB) error {
+	fsys, err := fs.Sub(database.Migrations, "migrations")
+	if err != nil {
+		return err
+	}
+
+	provider, err := goose.NewProvider(goose.DialectSQLite3, conn, fsys)
+	if err != nil {
+		return err
+	}
+
+	_, err = provider.Up(ctx)
+	return err
+}
+
+func openOutput(path string) (io.Writer, func(), error) {
+	if path == "" || path == "-" {
+		return os.Stdout, func() {}, nil
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return file, func() { file.Close() }, nil
+}
+
+type candidateOutput struct {
+	Name        string `json:"name"`
+	Domain      string `json:"domain"`
+	Description string `json:"description,omitempty"`
+	Industry    string `json:"industry,omitempty"`
+	Location    string `json:"location,omitempty"`
+}
+
+type researchBriefOutput struct {
+	IdentificationStatus string  `json:"identification_status"`
+	CompanyName          string  `json:"company_name"`
+	OfficialDomain       string  `json:"official_domain"`
+	Headquarters         string  `json:"headquarters"`
+	Industry             string  `json:"industry"`
+	CompanyType          string  `json:"company_type"`
+	Status               string  `json:"status"`
+	ConfidenceScore      float64 `json:"confidence_score"`
+}
+
+type sectionsOutput struct {
+	CompanyIntelligence     string `json:"company_intelligence"`
+	CompetitiveIntelligence string `json:"competitive_intelligence"`
+	MarketDynamics          string `json:"market_dynamics"`
+	TrendAnalysis           string `json:"trend_analysis"`
+}
+
+type resultOutput struct {
+	Status        string              `json:"status"`
+	ReportID      string              `json:"report_id,omitempty"`
+	Company       *candidateOutput    `json:"company,omitempty"`
+	ResearchBrief researchBriefOutput `json:"research_brief"`
+	Candidates    []candidateOutput   `json:"candidates,omitempty"`
+	Sections      *sectionsOutput     `json:"sections,omitempty"`
+	Report        string              `json:"report,omitempty"`
+	GeneratedAt   *time.Time          `json:"generated_at,omitempty"`
+}
+
+func writeResult(w io.Writer, format string, result services.RunResult) error {
+	if format == formatMarkdown {
+		_, err := io.WriteString(w, strings.TrimSpace(result.Report.FinalReport)+"\n")
+		return err
+	}
+
+	company := toCandidateOutput(result.Candidate)
+	generatedAt := result.Report.UpdatedAt
+
+	return writeJSON(w, resultOutput{
+		Status:        "completed",
+		ReportID:      result.Report.ID.String(),
+		Company:       &company,
+		ResearchBrief: toResearchBriefOutput(result.ResearchBrief),
+		Sections: &sectionsOutput{
+			CompanyIntelligence:     result.Report.CompanyIntelligenceData,
+			CompetitiveIntelligence: result.Report.CompetitiveIntelligenceData,
+			MarketDynamics:          result.Report.MarketDynamicsData,
+			TrendAnalysis:           result.Report.TrendAnalysisData,
+		},
+		Report:      result.Report.FinalReport,
+		GeneratedAt: &generatedAt,
+	})
+}
+
+// writeAmbiguous reports the candidates found for a company that could not be
+// identified, so the caller can rerun with --url.
+func writeAmbiguous(w io.Writer, format string, name string, result services.RunResult) int {
+	candidates := make([]candidateOutput, len(result.Candidates))
+	for i, candidate := range result.Candidates {
+		candidates[i] = toCandidateOutput(candidate)
+	}
+
+	if format == formatJSON {
+		if err := writeJSON(w, resultOutput{
+			Status:        "ambiguous",
+			ResearchBrief: toResearchBriefOutput(result.ResearchBrief),
+			Candidates:    candidates,
+		}); err != nil {
+			fmt.Fprintf(os.Stderr, "failed to write result: %v\n", err)
+			return exitError
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%q could not be identified unambiguously; rerun with --url set to one of:\n", name)
+	for _, candidate := range candidates {
+		fmt.Fprintf(os.Stderr, "  %s\t%s\t%s\n", candidate.Domain, candidate.Name, candidate.Location)
+	}
+
+	return exitAmbiguous
+}
+
+func writeJSON(w io.Writer, v any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(v)
+}
+
+func toCandidateOutput(candidate models.CompanyCandidates) candidateOutput {
+	return candidateOutput{
+		Name:        candidate.Name,
+		Domain:      candidate.Domain,
+		Description: candidate.Description,
+		Industry:    candidate.Industry,
+		Location:    candidate.Location,
+	}
+}
+
+func toResearchBriefOutput(brief models.ResearchBrief) researchBriefOutput {
+	return researchBriefOutput{
+		IdentificationStatus: brief.IdentificationStatus,
+		CompanyName:          brief.CompanyName,
+		OfficialDomain:       brief.OfficialDomain,
+		Headquarters:         brief.Headquarters,
+		Industry:             brief.Industry,
+		CompanyType:          brief.CompanyType,
+		Status:               brief.Status,
+		ConfidenceScore:      brief.ConfidenceScore,
+	}
+}
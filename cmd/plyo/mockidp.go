@@ -0,0 +1,444 @@
+package main
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"html/template"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mbvlabs/plyo-hackathon/providers"
+)
+
+const mockIDPUsage = `Usage: plyo mock-idp [flags]
+
+Runs an OpenID Connect identity provider for trying out single sign-on
+locally. It signs in whoever asks, so never expose it. Point the app at it
+with OIDC_ISSUER_URL set to the printed issuer and OIDC_CLIENT_ID and
+OIDC_CLIENT_SECRET matching -client-id and -client-secret.
+
+Flags:
+`
+
+const (
+	mockIDPCodeTTL   = time.Minute
+	mockIDPTokenTTL  = 5 * time.Minute
+	mockIDPKeyLength = 2048
+)
+
+type mockIDPIdentity struct {
+	Email  string
+	Name   string
+	Groups string
+}
+
+type mockIDPCode struct {
+	redirectURI string
+	challenge   string
+	nonce       string
+	identity    mockIDPIdentity
+	expiresAt   time.Time
+}
+
+type mockIDP struct {
+	issuer       string
+	clientID     string
+	clientSecret string
+	identity     mockIDPIdentity
+	auto         bool
+	key          *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]mockIDPCode
+}
+
+func mockIDPCommand(args []string) int {
+	flags := flag.NewFlagSet("mock-idp", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), mockIDPUsage)
+		flags.PrintDefaults()
+	}
+	addr := flags.String("addr", "127.0.0.1:9999", "address to listen on")
+	issuer := flags.String("issuer", "", "issuer URL (default http://<addr>)")
+	clientID := flags.String("client-id", "plyo", "client id the app signs in with")
+	clientSecret := flags.String("client-secret", "", "client secret the app signs in with, none when empty")
+	email := flags.String("email", "analyst@example.com", "email of the signed-in user")
+	name := flags.String("name", "Mock Analyst", "name of the signed-in user")
+	groups := flags.String("groups", "", "comma separated groups of the signed-in user")
+	auto := flags.Bool("auto", false, "sign in as the flag identity without showing the sign-in form")
+
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	if *issuer == "" {
+		*issuer = "http://" + *addr
+	}
+
+	key, err := rsa.GenerateKey(rand.Reader, mockIDPKeyLength)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "generating signing key: %v\n", err)
+		return exitError
+	}
+
+	idp := &mockIDP{
+		issuer:       strings.TrimSuffix(*issuer, "/"),
+		clientID:     *clientID,
+		clientSecret: *clientSecret,
+		identity:     mockIDPIdentity{Email: *email, Name: *name, Groups: *groups},
+		auto:         *auto,
+		key:          key,
+		codes:        map[string]mockIDPCode{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", idp.discovery)
+	mux.HandleFunc("GET /authorize", idp.authorize)
+	mux.HandleFunc("POST /authorize", idp.approve)
+	mux.HandleFunc("POST /token", idp.token)
+	mux.HandleFunc("GET /jwks", idp.jwks)
+
+	fmt.Fprintf(os.Stderr, "mock identity provider listening, issuer %s\n", idp.issuer)
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	if err := server.ListenAndServe(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	return exitOK
+}
+
+func (m *mockIDP) discovery(w http.ResponseWriter, _ *http.Request) {
+	writeMockIDPJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                m.issuer,
+		"authorization_endpoint":                m.issuer + "/authorize",
+		"token_endpoint":                        m.issuer + "/token",
+		"jwks_uri":                              m.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic", "none"},
+	})
+}
+
+var mockIDPSignIn = template.Must(template.New("sign-in").Parse(`<!doctype html>
+<html>
+<head><title>Mock identity provider</title></head>
+<body style="font-family: sans-serif; max-width: 24rem; margin: 4rem auto">
+<h1>Mock identity provider</h1>
+<p>Sign in as anyone. Groups are comma separated.</p>
+<form method="post">
+{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+{{end}}<p><label>Email<br><input name="email" value="{{.Identity.Email}}" required></label></p>
+<p><label>Name<br><input name="name" value="{{.Identity.Name}}"></label></p>
+<p><label>Groups<br><input name="groups" value="{{.Identity.Groups}}"></label></p>
+<p><button type="submit">Sign in</button> <button type="submit" name="deny" value="1">Deny</button></p>
+</form>
+</body>
+</html>
+`))
+
+// mockIDPAuthorizeParams are the authorization request parameters carried
+// from the sign-in form to its submission.
+var mockIDPAuthorizeParams = []string{
+	"response_type",
+	"client_id",
+	"redirect_uri",
+	"state",
+	"nonce",
+	"code_challenge",
+	"code_challenge_method",
+}
+
+func (m *mockIDP) authorize(w http.ResponseWriter, r *http.Request) {
+	if _, ok := m.checkAuthorizeRequest(w, r.URL.Query()); !ok {
+		return
+	}
+
+	if m.auto {
+		m.issueCode(w, r, r.URL.Query(), m.identity)
+		return
+	}
+
+	params := map[string]string{}
+	for _, name := range mockIDPAuthorizeParams {
+		params[name] = r.URL.Query().Get(name)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := mockIDPSignIn.Execute(w, map[string]any{
+		"Params":   params,
+		"Identity": m.identity,
+	}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (m *mockIDP) approve(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, ok := m.checkAuthorizeRequest(w, r.PostForm)
+	if !ok {
+		return
+	}
+
+	if r.PostForm.Get("deny") != "" {
+		redirectMockIDP(w, r, redirectURI, url.Values{
+			"error":             {"access_denied"},
+			"error_description": {"The user denied the sign-in"},
+			"state":             {r.PostForm.Get("state")},
+		})
+		return
+	}
+
+	m.issueCode(w, r, r.PostForm, mockIDPIdentity{
+		Email:  strings.TrimSpace(r.PostForm.Get("email")),
+		Name:   strings.TrimSpace(r.PostForm.Get("name")),
+		Groups: r.PostForm.Get("groups"),
+	})
+}
+
+// checkAuthorizeRequest validates an authorization request and returns its
+// redirect URI. Errors are shown to the user instead of being redirected,
+// as the redirect URI cannot be trusted before the client is known.
+func (m *mockIDP) checkAuthorizeRequest(w http.ResponseWriter, params url.Values) (*url.URL, bool) {
+	redirectURI, err := url.Parse(params.Get("redirect_uri"))
+	switch {
+	case params.Get("client_id") != m.clientID:
+		http.Error(w, "unknown client_id", http.StatusBadRequest)
+	case err != nil || !redirectURI.IsAbs():
+		http.Error(w, "redirect_uri must be an absolute URL", http.StatusBadRequest)
+	case params.Get("response_type") != "code":
+		http.Error(w, "only response_type=code is supported", http.StatusBadRequest)
+	case params.Get("code_challenge_method") != "S256" || params.Get("code_challenge") == "":
+		http.Error(w, "PKCE with code_challenge_method=S256 is required", http.StatusBadRequest)
+	default:
+		return redirectURI, true
+	}
+
+	return nil, false
+}
+
+func (m *mockIDP) issueCode(
+	w http.ResponseWriter,
+	r *http.Request,
+	params url.Values,
+	identity mockIDPIdentity,
+) {
+	code, err := providers.NewOIDCSecret()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	m.mu.Lock()
+	m.codes[code] = mockIDPCode{
+		redirectURI: params.Get("redirect_uri"),
+		challenge:   params.Get("code_challenge"),
+		nonce:       params.Get("nonce"),
+		identity:    identity,
+		expiresAt:   time.Now().Add(mockIDPCodeTTL),
+	}
+	m.mu.Unlock()
+
+	redirectURI, _ := url.Parse(params.Get("redirect_uri"))
+	redirectMockIDP(w, r, redirectURI, url.Values{
+		"code":  {code},
+		"state": {params.Get("state")},
+	})
+}
+
+func (m *mockIDP) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		mockIDPTokenError(w, http.StatusBadRequest, "invalid_request", err.Error())
+		return
+	}
+
+	if !m.authenticateClient(r) {
+		mockIDPTokenError(w, http.StatusUnauthorized, "invalid_client", "unknown client or wrong secret")
+		return
+	}
+
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		mockIDPTokenError(w, http.StatusBadRequest, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	// Codes are single use, whether the exchange succeeds or not.
+	m.mu.Lock()
+	code, ok := m.codes[r.PostForm.Get("code")]
+	delete(m.codes, r.PostForm.Get("code"))
+	m.mu.Unlock()
+
+	switch {
+	case !ok || time.Now().After(code.expiresAt):
+		mockIDPTokenError(w, http.StatusBadRequest, "invalid_grant", "unknown or expired code")
+		return
+	case r.PostForm.Get("redirect_uri") != code.redirectURI:
+		mockIDPTokenError(w, http.StatusBadRequest, "invalid_grant", "redirect_uri does not match the authorization request")
+		return
+	case subtle.ConstantTimeCompare(
+		[]byte(providers.PKCEChallenge(r.PostForm.Get("code_verifier"))),
+		[]byte(code.challenge),
+	) != 1:
+		mockIDPTokenError(w, http.StatusBadRequest, "invalid_grant", "code_verifier does not match the code_challenge")
+		return
+	}
+
+	idToken, err := m.signIDToken(code)
+	if err != nil {
+		mockIDPTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	accessToken, err := providers.NewOIDCSecret()
+	if err != nil {
+		mockIDPTokenError(w, http.StatusInternalServerError, "server_error", err.Error())
+		return
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+	writeMockIDPJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   int(mockIDPTokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// authenticateClient accepts client_secret_basic, and a client_id in the
+// form for public clients when no secret is configured.
+func (m *mockIDP) authenticateClient(r *http.Request) bool {
+	clientID, secret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		secret, _ = url.QueryUnescape(secret)
+	} else {
+		clientID = r.PostForm.Get("client_id")
+	}
+
+	return clientID == m.clientID &&
+		subtle.ConstantTimeCompare([]byte(secret), []byte(m.clientSecret)) == 1
+}
+
+func (m *mockIDP) signIDToken(code mockIDPCode) (string, error) {
+	now := time.Now()
+
+	// Derive the subject from the email so signing in again with the same
+	// email is the same identity.
+	subject := sha256.Sum256([]byte(strings.ToLower(code.identity.Email)))
+
+	groups := []string{}
+	for group := range strings.SplitSeq(code.identity.Groups, ",") {
+		if group = strings.TrimSpace(group); group != "" {
+			groups = append(groups, group)
+		}
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": m.keyID(),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	claims, err := json.Marshal(map[string]any{
+		"iss":            m.issuer,
+		"sub":            hex.EncodeToString(subject[:16]),
+		"aud":            m.clientID,
+		"azp":            m.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(mockIDPTokenTTL).Unix(),
+		"nonce":          code.nonce,
+		"email":          code.identity.Email,
+		"email_verified": true,
+		"name":           code.identity.Name,
+		"groups":         groups,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, m.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// keyID derives the key id from the key, so restarting the identity
+// provider looks like a key rotation to the app.
+func (m *mockIDP) keyID() string {
+	sum := sha256.Sum256(m.key.N.Bytes())
+	return hex.EncodeToString(sum[:8])
+}
+
+func (m *mockIDP) jwks(w http.ResponseWriter, _ *http.Request) {
+	writeMockIDPJSON(w, http.StatusOK, map[string]any{
+		"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": m.keyID(),
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(m.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(m.key.E)).Bytes()),
+		}},
+	})
+}
+
+func redirectMockIDP(w http.ResponseWriter, r *http.Request, target *url.URL, params url.Values) {
+	query := target.Query()
+	for name, values := range params {
+		query[name] = values
+	}
+	target.RawQuery = query.Encode()
+
+	http.Redirect(w, r, target.String(), http.StatusFound)
+}
+
+func mockIDPTokenError(w http.ResponseWriter, status int, code, description string) {
+	writeMockIDPJSON(w, status, map[string]string{
+		"error":             code,
+		"error_description": description,
+	})
+}
+
+func writeMockIDPJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -2,6 +2,7 @@ package config
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/caarlos0/env/v10"
 )
@@ -22,6 +23,62 @@ type app struct {
 	OpenAPIKey        string `env:"OPENAI_API_KEY"`
 	SerperAPIkey      string `env:"SERPER_API_KEY"`
 	ScrapingBeeAPIKey string `env:"SCRAPING_BEE_API_KEY"`
+
+	// ScrapeMaxTokens is the budget every scraped page is truncated to
+	// before it reaches a model. Zero keeps whole pages.
+	ScrapeMaxTokens int `env:"SCRAPE_MAX_TOKENS" envDefault:"6000"`
+	// WebFetchUserAgent is the user agent pages are fetched directly with,
+	// and its product token the name robots.txt rules apply to.
+	WebFetchUserAgent string `env:"WEB_FETCH_USER_AGENT" envDefault:""`
+	// ScrapeBackends are the scrapers pages are tried with, in order. A
+	// backend failing ScrapeBreakerErrorRate of its recent attempts is
+	// skipped for ScrapeBreakerCooldown.
+	ScrapeBackends         []string      `env:"SCRAPE_BACKENDS"           envDefault:"web_fetch,serper_scrape,scrapingbee_scraper" envSeparator:","`
+	ScrapeBreakerErrorRate float64       `env:"SCRAPE_BREAKER_ERROR_RATE" envDefault:"0.5"`
+	ScrapeBreakerCooldown  time.Duration `env:"SCRAPE_BREAKER_COOLDOWN"   envDefault:"2m"`
+	// SearchBackends are the search engines agents search with, in order,
+	// out of serper and searxng. The SEARCH_BACKENDS_* variables set an
+	// agent's apart. With SearchMergeResults every backend of an agent is
+	// asked and their results merged; without, the next is asked only when
+	// one fails.
+	SearchBackends                        []string `env:"SEARCH_BACKENDS"                          envDefault:"serper" envSeparator:","`
+	PreliminaryResearchSearchBackends     []string `env:"SEARCH_BACKENDS_PRELIMINARY_RESEARCH"     envDefault:""       envSeparator:","`
+	CompanyIntelligenceSearchBackends     []string `env:"SEARCH_BACKENDS_COMPANY_INTELLIGENCE"     envDefault:""       envSeparator:","`
+	CompetitiveIntelligenceSearchBackends []string `env:"SEARCH_BACKENDS_COMPETITIVE_INTELLIGENCE" envDefault:""       envSeparator:","`
+	MarketDynamicsSearchBackends          []string `env:"SEARCH_BACKENDS_MARKET_DYNAMICS"          envDefault:""       envSeparator:","`
+	TrendAnalysisSearchBackends           []string `env:"SEARCH_BACKENDS_TREND_ANALYSIS"           envDefault:""       envSeparator:","`
+	DataValidationSearchBackends          []string `env:"SEARCH_BACKENDS_DATA_VALIDATION"          envDefault:""       envSeparator:","`
+	SearchMergeResults                    bool     `env:"SEARCH_MERGE_RESULTS"                     envDefault:"false"`
+	// SearXNGURL is the base URL of the SearXNG instance the searxng
+	// backend searches.
+	SearXNGURL string `env:"SEARXNG_URL" envDefault:""`
+	// SiteCrawlMaxPages is how many pages of a company's website a crawl
+	// reads unless the agent asks for another number.
+	SiteCrawlMaxPages int `env:"SITE_CRAWL_MAX_PAGES" envDefault:"8"`
+	// Searches and pages are cached for these, zero turning the cache off
+	// for the tool. ToolCacheBypass refetches everything, refreshing the
+	// cache.
+	SearchCacheTTL  time.Duration `env:"CACHE_TTL_SEARCH"  envDefault:"24h"`
+	ScrapeCacheTTL  time.Duration `env:"CACHE_TTL_SCRAPE"  envDefault:"72h"`
+	ToolCacheBypass bool          `env:"TOOL_CACHE_BYPASS" envDefault:"false"`
+	// The search and scraping APIs are called through a proxy when
+	// ToolsHTTPProxy is set, else the one of HTTPS_PROXY, if any. Requests
+	// time out after ToolsHTTPTimeout, and failed ones that change nothing
+	// are retried ToolsHTTPRetries times.
+	ToolsHTTPTimeout time.Duration `env:"TOOLS_HTTP_TIMEOUT" envDefault:"60s"`
+	ToolsHTTPRetries int           `env:"TOOLS_HTTP_RETRIES" envDefault:"2"`
+	ToolsHTTPProxy   string        `env:"TOOLS_HTTP_PROXY"   envDefault:""`
+
+	BatchReportConcurrency    int64   `env:"BATCH_REPORT_CONCURRENCY"     envDefault:"3"`
+	BatchAutoSelectConfidence float64 `env:"BATCH_AUTO_SELECT_CONFIDENCE" envDefault:"0.8"`
+
+	// Sections researched for an earlier report on the same company are
+	// offered to the domain agents for as long as they are younger than
+	// these. Zero always researches the section from scratch.
+	CompanyIntelligenceMaxAge     time.Duration `env:"FINDINGS_MAX_AGE_COMPANY_INTELLIGENCE"     envDefault:"720h"`
+	CompetitiveIntelligenceMaxAge time.Duration `env:"FINDINGS_MAX_AGE_COMPETITIVE_INTELLIGENCE" envDefault:"336h"`
+	MarketDynamicsMaxAge          time.Duration `env:"FINDINGS_MAX_AGE_MARKET_DYNAMICS"          envDefault:"336h"`
+	TrendAnalysisMaxAge           time.Duration `env:"FINDINGS_MAX_AGE_TREND_ANALYSIS"           envDefault:"168h"`
 }
 
 func (a app) GetFullDomain() string {
@@ -37,6 +94,16 @@ func (a app) GetFullDomain() string {
 	)
 }
 
+// GetWebFetchUserAgent returns the configured user agent for fetching pages,
+// or one naming the crawler and where to find out about it.
+func (a app) GetWebFetchUserAgent() string {
+	if a.WebFetchUserAgent != "" {
+		return a.WebFetchUserAgent
+	}
+
+	return fmt.Sprintf("PlyoBot/1.0 (+%s)", a.GetFullDomain())
+}
+
 func newAppConfig() app {
 	appCfg := app{}
 
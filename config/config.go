@@ -4,4 +4,5 @@ var (
 	App  = newAppConfig()
 	Auth = newAuthConfig()
 	DB   = newDatabaseConfig()
+	OIDC = newOIDCConfig()
 )
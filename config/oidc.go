@@ -0,0 +1,47 @@
+package config
+
+import "github.com/caarlos0/env/v10"
+
+// oidc configures single sign-on through an OpenID Connect identity
+// provider. Single sign-on is off while IssuerURL is empty.
+type oidc struct {
+	IssuerURL    string `env:"OIDC_ISSUER_URL"    envDefault:""`
+	ClientID     string `env:"OIDC_CLIENT_ID"     envDefault:""`
+	ClientSecret string `env:"OIDC_CLIENT_SECRET" envDefault:""`
+	Scopes       string `env:"OIDC_SCOPES"        envDefault:"openid email profile"`
+	// ProviderName labels the sign-in button, e.g. "Okta".
+	ProviderName string `env:"OIDC_PROVIDER_NAME" envDefault:"SSO"`
+	// GroupsClaim names the ID token claim listing the user's groups.
+	GroupsClaim string `env:"OIDC_GROUPS_CLAIM" envDefault:"groups"`
+	// RoleMapping maps groups to workspace roles, as comma separated
+	// group=role pairs, e.g. "deal-leads=owner,analysts=editor".
+	RoleMapping string `env:"OIDC_ROLE_MAPPING" envDefault:""`
+	// DefaultRole is given to users in none of the mapped groups. Set it to
+	// "none" to refuse such users.
+	DefaultRole string `env:"OIDC_DEFAULT_ROLE" envDefault:"viewer"`
+	// WorkspaceID is the workspace users are provisioned into, the default
+	// workspace when empty.
+	WorkspaceID string `env:"OIDC_WORKSPACE_ID" envDefault:""`
+	// Enforce turns off password sign-in and registration.
+	Enforce bool `env:"OIDC_ENFORCE" envDefault:"false"`
+	// LinkExistingAccounts links a first single sign-on to an existing
+	// password account with the same verified email, without the user
+	// signing in with their password first.
+	LinkExistingAccounts bool `env:"OIDC_LINK_EXISTING_ACCOUNTS" envDefault:"false"`
+}
+
+func (o oidc) Enabled() bool {
+	return o.IssuerURL != ""
+}
+
+func newOIDCConfig() oidc {
+	oidcCfg := oidc{}
+
+	if err := env.ParseWithOptions(&oidcCfg, env.Options{
+		RequiredIfNoDef: true,
+	}); err != nil {
+		panic(err)
+	}
+
+	return oidcCfg
+}
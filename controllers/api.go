@@ -1,20 +1,620 @@
 package controllers
 
 import (
-	"github.com/mbvlabs/plyo-hackathon/database"
+	"database/sql"
+	"errors"
+	"fmt"
+	"html"
+	"log/slog"
 	"net/http"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"maragu.dev/goqite"
+)
+
+const (
+	apiReportStatusPending     = "pending"
+	apiReportStatusResearching = "researching"
+	apiReportStatusGenerating  = "generating"
+	apiReportStatusCompleted   = "completed"
+	apiReportStatusFailed      = "failed"
+	apiReportStatusCanceled    = "canceled"
 )
 
+const (
+	sectionCompanyIntelligence     = "company_intelligence"
+	sectionCompetitiveIntelligence = "competitive_intelligence"
+	sectionMarketDynamics          = "market_dynamics"
+	sectionTrendAnalysis           = "trend_analysis"
+)
+
+var reportSectionNames = []string{
+	sectionCompanyIntelligence,
+	sectionCompetitiveIntelligence,
+	sectionMarketDynamics,
+	sectionTrendAnalysis,
+}
+
 type API struct {
 	db database.SQLite
+	q  *goqite.Queue
 }
 
-func newAPI(db database.SQLite) API {
-	return API{db}
+func newAPI(db database.SQLite, q *goqite.Queue) API {
+	return API{db, q}
 }
 
 func (a API) Health(ctx echo.Context) error {
 	return ctx.JSON(http.StatusOK, "app is healthy and running")
 }
+
+type apiError struct {
+	Error string `json:"error"`
+}
+
+type apiCandidate struct {
+	ID          uuid.UUID `json:"id"`
+	Name        string    `json:"name"`
+	Domain      string    `json:"domain"`
+	Description string    `json:"description"`
+	Industry    string    `json:"industry"`
+	Location    string    `json:"location"`
+}
+
+type apiResearchBrief struct {
+	ID                   uuid.UUID `json:"id"`
+	IdentificationStatus string    `json:"identification_status"`
+	CompanyName          string    `json:"company_name"`
+	OfficialDomain       string    `json:"official_domain"`
+	Headquarters         string    `json:"headquarters"`
+	Industry             string    `json:"industry"`
+	CompanyType          string    `json:"company_type"`
+	Status               string    `json:"status"`
+	GeographicScope      string    `json:"geographic_scope"`
+	ResearchDepth        string    `json:"research_depth"`
+	ConfidenceScore      float64   `json:"confidence_score"`
+	LastUpdated          time.Time `json:"last_updated"`
+	// ResearchStatus is pending until the preliminary research has found
+	// the candidates, then completed or failed.
+	ResearchStatus string         `json:"research_status"`
+	ResearchError  string         `json:"research_error,omitempty"`
+	Query          string         `json:"query,omitempty"`
+	Candidates     []apiCandidate `json:"candidates"`
+}
+
+type apiReport struct {
+	ID                 uuid.UUID         `json:"id"`
+	CompanyCandidateID string            `json:"company_candidate_id"`
+	CompanyName        string            `json:"company_name"`
+	Status             string            `json:"status"`
+	ProgressPercentage int64             `json:"progress_percentage"`
+	SectionsCompleted  map[string]bool   `json:"sections_completed"`
+	FinalReportReady   bool              `json:"final_report_ready"`
+	CreatedAt          time.Time         `json:"created_at"`
+	UpdatedAt          time.Time         `json:"updated_at"`
+	Links              map[string]string `json:"links"`
+}
+
+type apiReportSection struct {
+	Name      string `json:"name"`
+	Completed bool   `json:"completed"`
+	Content   string `json:"content"`
+}
+
+type apiFinalReport struct {
+	ReportID uuid.UUID `json:"report_id"`
+	Format   string    `json:"format"`
+	Content  string    `json:"content"`
+}
+
+type apiReportSearchResult struct {
+	ReportID    uuid.UUID         `json:"report_id"`
+	CompanyName string            `json:"company_name"`
+	CreatedAt   time.Time         `json:"created_at"`
+	Snippet     string            `json:"snippet"`
+	SnippetHTML string            `json:"snippet_html"`
+	Links       map[string]string `json:"links"`
+}
+
+type apiReportSearch struct {
+	Query      string                  `json:"query"`
+	Page       int64                   `json:"page"`
+	PageSize   int64                   `json:"page_size"`
+	TotalPages int64                   `json:"total_pages"`
+	TotalCount int64                   `json:"total_count"`
+	Results    []apiReportSearchResult `json:"results"`
+}
+
+type CreateResearchBriefAPIPayload struct {
+	Query string `json:"query"`
+	URL   string `json:"url"`
+}
+
+func (a API) CreateResearchBrief(c echo.Context) error {
+	var payload CreateResearchBriefAPIPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, apiError{"invalid request body"})
+	}
+
+	query := strings.TrimSpace(payload.Query)
+	if query == "" {
+		return c.JSON(http.StatusUnprocessableEntity, apiError{"query is required"})
+	}
+	if payload.URL != "" {
+		query = fmt.Sprintf("%s (%s)", query, payload.URL)
+	}
+
+	researchbrief, err := services.QueueResearchBrief(
+		c.Request().Context(),
+		a.db.Conn(),
+		a.q,
+		query,
+		currentUserID(c),
+		currentWorkspace(c).ID.String(),
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to queue research brief",
+			"error", err,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to queue research brief"})
+	}
+
+	response, err := a.researchBriefResponse(c, researchbrief)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to fetch company candidates"})
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, routes.APIResearchBriefShow.GetPath(researchbrief.ID))
+	return c.JSON(http.StatusAccepted, response)
+}
+
+func (a API) ShowResearchBrief(c echo.Context) error {
+	researchbriefID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiError{"invalid research brief id"})
+	}
+
+	researchbrief, err := models.FindResearchBrief(c.Request().Context(), a.db.Conn(), currentWorkspace(c).ID, researchbriefID)
+	if err != nil {
+		return apiFindError(c, err, "research brief")
+	}
+
+	response, err := a.researchBriefResponse(c, researchbrief)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to fetch company candidates"})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (a API) ListCandidates(c echo.Context) error {
+	researchbriefID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, apiError{"invalid research brief id"})
+	}
+
+	_, err = models.FindResearchBrief(c.Request().Context(), a.db.Conn(), currentWorkspace(c).ID, researchbriefID)
+	if err != nil {
+		return apiFindError(c, err, "research brief")
+	}
+
+	candidates, err := models.FindCompanyCandidatesByResearchBriefID(
+		c.Request().Context(),
+		a.db.Conn(),
+		researchbriefID.String(),
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch company candidates",
+			"error", err,
+			"research_brief_id", researchbriefID,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to fetch company candidates"})
+	}
+
+	return c.JSON(http.StatusOK, toAPICandidates(candidates))
+}
+
+type CreateReportAPIPayload struct {
+	CandidateID string `json:"candidate_id"`
+}
+
+func (a API) CreateReport(c echo.Context) error {
+	var payload CreateReportAPIPayload
+	if err := c.Bind(&payload); err != nil {
+		return c.JSON(http.StatusBadRequest, apiError{"invalid request body"})
+	}
+
+	candidateID, err := uuid.Parse(payload.CandidateID)
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, apiError{"candidate_id must be a valid id"})
+	}
+
+	candidate, err := models.FindCompanyCandidates(c.Request().Context(), a.db.Conn(), currentWorkspace(c).ID, candidateID)
+	if err != nil {
+		return apiFindError(c, err, "company candidate")
+	}
+
+	report, err := services.CreateReport(c.Request().Context(), a.db.Conn(), candidate)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to create report",
+			"error", err,
+			"candidate_id", candidateID,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to create report"})
+	}
+
+	if err := services.StartResearch(
+		c.Request().Context(),
+		a.db.Conn(),
+		a.q,
+		report,
+		candidate.Domain,
+	); err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to start research",
+			"error", err,
+			"report_id", report.ID,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to start research"})
+	}
+
+	report, err = models.FindReportByID(c.Request().Context(), a.db.Conn(), report.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to fetch report"})
+	}
+
+	c.Response().Header().Set(echo.HeaderLocation, routes.APIReportShow.GetPath(report.ID))
+	return c.JSON(http.StatusAccepted, toAPIReport(report))
+}
+
+const apiReportSearchPageSize = 20
+
+func (a API) SearchReports(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+	if query == "" {
+		return c.JSON(http.StatusBadRequest, apiError{"q is required"})
+	}
+
+	results, err := models.SearchReports(
+		c.Request().Context(),
+		a.db.Conn(),
+		currentWorkspace(c).ID,
+		query,
+		pageParam(c),
+		apiReportSearchPageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to search reports",
+			"error", err,
+			"query", query,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to search reports"})
+	}
+
+	response := apiReportSearch{
+		Query:      query,
+		Page:       results.Page,
+		PageSize:   results.PageSize,
+		TotalPages: results.TotalPages,
+		TotalCount: results.TotalCount,
+		Results:    make([]apiReportSearchResult, len(results.Results)),
+	}
+	for i, result := range results.Results {
+		response.Results[i] = toAPIReportSearchResult(result)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+func (a API) ShowReport(c echo.Context) error {
+	report, ok, err := a.findReport(c)
+	if !ok {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, toAPIReport(report))
+}
+
+func (a API) CancelReport(c echo.Context) error {
+	report, ok, err := a.findReport(c)
+	if !ok {
+		return err
+	}
+
+	canceled, err := services.CancelReport(
+		c.Request().Context(),
+		a.db.Conn(),
+		a.q,
+		report.ID,
+		config.App.BatchReportConcurrency,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to cancel report",
+			"error", err,
+			"report_id", report.ID,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to cancel report"})
+	}
+	if !canceled {
+		return c.JSON(http.StatusConflict, apiError{"report has already finished"})
+	}
+
+	report, err = models.FindReportByID(c.Request().Context(), a.db.Conn(), report.ID)
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to fetch report"})
+	}
+
+	return c.JSON(http.StatusOK, toAPIReport(report))
+}
+
+func (a API) ShowReportSections(c echo.Context) error {
+	report, ok, err := a.findReport(c)
+	if !ok {
+		return err
+	}
+
+	sections := make([]apiReportSection, len(reportSectionNames))
+	for i, name := range reportSectionNames {
+		sections[i], _ = reportSection(report, name)
+	}
+
+	return c.JSON(http.StatusOK, sections)
+}
+
+func (a API) ShowReportSection(c echo.Context) error {
+	report, ok, err := a.findReport(c)
+	if !ok {
+		return err
+	}
+
+	section, ok := reportSection(report, c.Param("section"))
+	if !ok {
+		return c.JSON(http.StatusNotFound, apiError{fmt.Sprintf(
+			"unknown section, use one of: %s",
+			strings.Join(reportSectionNames, ", "),
+		)})
+	}
+
+	return c.JSON(http.StatusOK, section)
+}
+
+func (a API) ShowFinalReport(c echo.Context) error {
+	report, ok, err := a.findReport(c)
+	if !ok {
+		return err
+	}
+
+	if report.FinalReport == "" {
+		return c.JSON(http.StatusConflict, apiError{"final report is not ready yet"})
+	}
+
+	return c.JSON(http.StatusOK, apiFinalReport{
+		ReportID: report.ID,
+		Format:   "markdown",
+		Content:  report.FinalReport,
+	})
+}
+
+func (a API) ExportReport(c echo.Context) error {
+	report, ok, err := a.findReport(c)
+	if !ok {
+		return err
+	}
+
+	format := c.Param("format")
+	content, err := services.ExportReport(c.Request().Context(), a.db.Conn(), report, format)
+	switch {
+	case errors.Is(err, services.ErrUnknownExportFormat):
+		return c.JSON(http.StatusNotFound, apiError{fmt.Sprintf(
+			"unknown export format, use one of: %s",
+			strings.Join(services.ReportExportFormats, ", "),
+		)})
+	case errors.Is(err, services.ErrReportNotReady):
+		return c.JSON(http.StatusConflict, apiError{"final report is not ready yet"})
+	case err != nil:
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to export report",
+			"error", err,
+			"report_id", report.ID,
+			"format", format,
+		)
+		return c.JSON(http.StatusInternalServerError, apiError{"failed to export report"})
+	}
+
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", services.ReportExportFileName(report, format)),
+	)
+
+	return c.Blob(http.StatusOK, services.ReportExportContentType(format), content)
+}
+
+// findReport loads the report named by the id path param. When it returns
+// false the JSON error response has been written and err is the result of
+// writing it.
+func (a API) findReport(c echo.Context) (models.Report, bool, error) {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return models.Report{}, false, c.JSON(http.StatusBadRequest, apiError{"invalid report id"})
+	}
+
+	report, err := models.FindReport(c.Request().Context(), a.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return models.Report{}, false, apiFindError(c, err, "report")
+	}
+
+	return report, true, nil
+}
+
+func (a API) researchBriefResponse(
+	c echo.Context,
+	researchbrief models.ResearchBrief,
+) (apiResearchBrief, error) {
+	candidates, err := models.FindCompanyCandidatesByResearchBriefID(
+		c.Request().Context(),
+		a.db.Conn(),
+		researchbrief.ID.String(),
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch company candidates",
+			"error", err,
+			"research_brief_id", researchbrief.ID,
+		)
+		return apiResearchBrief{}, err
+	}
+
+	return apiResearchBrief{
+		ID:                   researchbrief.ID,
+		IdentificationStatus: researchbrief.IdentificationStatus,
+		CompanyName:          researchbrief.CompanyName,
+		OfficialDomain:       researchbrief.OfficialDomain,
+		Headquarters:         researchbrief.Headquarters,
+		Industry:             researchbrief.Industry,
+		CompanyType:          researchbrief.CompanyType,
+		Status:               researchbrief.Status,
+		GeographicScope:      researchbrief.GeographicScope,
+		ResearchDepth:        researchbrief.ResearchDepth,
+		ConfidenceScore:      researchbrief.ConfidenceScore,
+		LastUpdated:          researchbrief.LastUpdated,
+		ResearchStatus:       researchbrief.ResearchStatus,
+		ResearchError:        researchbrief.ResearchError,
+		Query:                researchbrief.Query,
+		Candidates:           toAPICandidates(candidates),
+	}, nil
+}
+
+func apiFindError(c echo.Context, err error, resource string) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return c.JSON(http.StatusNotFound, apiError{resource + " not found"})
+	}
+
+	slog.ErrorContext(
+		c.Request().Context(),
+		"failed to fetch "+resource,
+		"error", err,
+	)
+	return c.JSON(http.StatusInternalServerError, apiError{"failed to fetch " + resource})
+}
+
+func toAPICandidates(candidates []models.CompanyCandidates) []apiCandidate {
+	result := make([]apiCandidate, len(candidates))
+	for i, candidate := range candidates {
+		result[i] = apiCandidate{
+			ID:          candidate.ID,
+			Name:        candidate.Name,
+			Domain:      candidate.Domain,
+			Description: candidate.Description,
+			Industry:    candidate.Industry,
+			Location:    candidate.Location,
+		}
+	}
+
+	return result
+}
+
+func toAPIReport(report models.Report) apiReport {
+	status := apiReportStatusResearching
+	switch {
+	case report.FinalReport != "":
+		status = apiReportStatusCompleted
+	case report.Status == "failed":
+		status = apiReportStatusFailed
+	case report.Status == models.ReportStatusCanceled:
+		status = apiReportStatusCanceled
+	case report.Status == "generating" || services.AllAgentsCompleted(report):
+		status = apiReportStatusGenerating
+	case report.Status == "pending":
+		status = apiReportStatusPending
+	}
+
+	links := map[string]string{
+		"self":     routes.APIReportShow.GetPath(report.ID),
+		"sections": routes.APIReportSections.GetPath(report.ID),
+	}
+	if report.FinalReport != "" {
+		links["final"] = routes.APIReportFinal.GetPath(report.ID)
+		for _, format := range services.ReportExportFormats {
+			links["export_"+format] = routes.APIReportExport.GetPath(report.ID, format)
+		}
+	}
+
+	return apiReport{
+		ID:                 report.ID,
+		CompanyCandidateID: report.CompanyCandidateID,
+		CompanyName:        report.CompanyName,
+		Status:             status,
+		ProgressPercentage: models.CalculateProgress(report),
+		SectionsCompleted: map[string]bool{
+			sectionCompanyIntelligence:     report.CompanyIntelligenceCompleted,
+			sectionCompetitiveIntelligence: report.CompetitiveIntelligenceCompleted,
+			sectionMarketDynamics:          report.MarketDynamicsCompleted,
+			sectionTrendAnalysis:           report.TrendAnalysisCompleted,
+		},
+		FinalReportReady: report.FinalReport != "",
+		CreatedAt:        report.CreatedAt,
+		UpdatedAt:        report.UpdatedAt,
+		Links:            links,
+	}
+}
+
+// toAPIReportSearchResult returns the snippet twice: as plain text, and as
+// escaped HTML with the matched terms wrapped in <mark>.
+func toAPIReportSearchResult(result models.ReportSearchResult) apiReportSearchResult {
+	var text, markup strings.Builder
+	for _, part := range result.Snippet {
+		text.WriteString(part.Text)
+		if part.Match {
+			markup.WriteString("<mark>" + html.EscapeString(part.Text) + "</mark>")
+		} else {
+			markup.WriteString(html.EscapeString(part.Text))
+		}
+	}
+
+	return apiReportSearchResult{
+		ReportID:    result.ReportID,
+		CompanyName: result.CompanyName,
+		CreatedAt:   result.CreatedAt,
+		Snippet:     text.String(),
+		SnippetHTML: markup.String(),
+		Links: map[string]string{
+			"report": routes.APIReportShow.GetPath(result.ReportID),
+		},
+	}
+}
+
+func reportSection(report models.Report, name string) (apiReportSection, bool) {
+	switch name {
+	case sectionCompanyIntelligence:
+		return apiReportSection{name, report.CompanyIntelligenceCompleted, report.CompanyIntelligenceData}, true
+	case sectionCompetitiveIntelligence:
+		return apiReportSection{name, report.CompetitiveIntelligenceCompleted, report.CompetitiveIntelligenceData}, true
+	case sectionMarketDynamics:
+		return apiReportSection{name, report.MarketDynamicsCompleted, report.MarketDynamicsData}, true
+	case sectionTrendAnalysis:
+		return apiReportSection{name, report.TrendAnalysisCompleted, report.TrendAnalysisData}, true
+	default:
+		return apiReportSection{}, false
+	}
+}
@@ -0,0 +1,111 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+// APIKeys lets every member manage their own keys for the current workspace.
+// A key acts with its owner's role, so members need no extra permission.
+type APIKeys struct {
+	db database.SQLite
+}
+
+func newAPIKeys(db database.SQLite) APIKeys {
+	return APIKeys{db}
+}
+
+func (a APIKeys) Index(c echo.Context) error {
+	return a.renderIndex(c, "")
+}
+
+func (a APIKeys) Create(c echo.Context) error {
+	_, token, err := models.CreateAPIKey(c.Request().Context(), a.db.Conn(), models.CreateAPIKeyData{
+		UserID:      currentUser(c).ID,
+		Name:        strings.TrimSpace(c.FormValue("name")),
+		WorkspaceID: currentWorkspace(c).ID,
+	})
+	if err != nil {
+		if !errors.Is(err, models.ErrDomainValidation) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to create api key",
+				"error", err,
+			)
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Enter a name of at most 100 characters"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.APIKeyIndex.Path)
+	}
+
+	// The plaintext key is rendered directly instead of redirecting, as it
+	// is not stored anywhere it could be read back from.
+	return a.renderIndex(c, token)
+}
+
+func (a APIKeys) Revoke(c echo.Context) error {
+	keyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	revoked, err := models.RevokeUserAPIKey(
+		c.Request().Context(),
+		a.db.Conn(),
+		currentWorkspace(c).ID,
+		currentUser(c).ID,
+		keyID,
+	)
+	switch {
+	case err != nil:
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to revoke api key",
+			"error", err,
+			"api_key_id", keyID,
+		)
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Failed to revoke the key"); flashErr != nil {
+			return flashErr
+		}
+	case !revoked:
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "The key does not exist or was already revoked"); flashErr != nil {
+			return flashErr
+		}
+	default:
+		if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Key revoked"); flashErr != nil {
+			return flashErr
+		}
+	}
+
+	return getSSE(c).Redirect(routes.APIKeyIndex.Path)
+}
+
+func (a APIKeys) renderIndex(c echo.Context, token string) error {
+	keys, err := models.AllUserAPIKeys(
+		c.Request().Context(),
+		a.db.Conn(),
+		currentWorkspace(c).ID,
+		currentUser(c).ID,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch api keys",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.APIKeyIndex(keys, token))
+}
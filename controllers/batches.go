@@ -0,0 +1,264 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+	"maragu.dev/goqite"
+)
+
+const recentBatchesLimit = 20
+
+type Batches struct {
+	db database.SQLite
+	q  *goqite.Queue
+}
+
+func newBatches(
+	db database.SQLite,
+	q *goqite.Queue,
+) Batches {
+	return Batches{db, q}
+}
+
+func (b Batches) Index(c echo.Context) error {
+	batches, err := models.RecentBatches(
+		c.Request().Context(),
+		b.db.Conn(),
+		currentWorkspace(c).ID,
+		recentBatchesLimit,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch batches",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.BatchIndex(batches, services.MaxBatchRows))
+}
+
+func (b Batches) Create(c echo.Context) error {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Choose a CSV file to upload"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.BatchIndex.Path)
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+	defer file.Close()
+
+	name := strings.TrimSpace(c.FormValue("name"))
+	if name == "" {
+		name = strings.TrimSuffix(fileHeader.Filename, filepath.Ext(fileHeader.Filename))
+	}
+
+	batch, err := services.ImportBatchCSV(
+		c.Request().Context(),
+		b.db.Conn(),
+		b.q,
+		name,
+		currentUserID(c),
+		currentWorkspace(c).ID.String(),
+		file,
+	)
+	if err != nil {
+		if !errors.Is(err, models.ErrDomainValidation) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to import batch",
+				"error", err,
+			)
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to import CSV: %v", err)); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.BatchIndex.Path)
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.BatchShow.GetPath(batch.ID))
+}
+
+func (b Batches) Show(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	batch, err := models.FindBatch(c.Request().Context(), b.db.Conn(), currentWorkspace(c).ID, batchID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	rows, candidates, err := b.rowsWithCandidates(c, batch)
+	if err != nil {
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.BatchShow(batch, rows, candidates))
+}
+
+func (b Batches) Stream(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, "Invalid batch ID")
+	}
+
+	batch, err := models.FindBatch(c.Request().Context(), b.db.Conn(), currentWorkspace(c).ID, batchID)
+	if err != nil {
+		return c.String(http.StatusNotFound, "Batch not found")
+	}
+
+	rows, candidates, err := b.rowsWithCandidates(c, batch)
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Failed to fetch batch rows")
+	}
+
+	return getSSE(c).PatchElementTempl(views.BatchRows(batch, rows, candidates))
+}
+
+func (b Batches) SelectCandidate(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	rowID, err := uuid.Parse(c.Param("row_id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	candidateID, err := uuid.Parse(c.QueryParam("candidate_id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	batch, err := models.FindBatch(c.Request().Context(), b.db.Conn(), currentWorkspace(c).ID, batchID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	row, err := models.FindBatchRow(c.Request().Context(), b.db.Conn(), rowID)
+	if err != nil || row.BatchID != batch.ID.String() {
+		return render(c, views.NotFound())
+	}
+
+	if row.Status != models.BatchRowNeedsReview {
+		return getSSE(c).Redirect(routes.BatchShow.GetPath(batchID))
+	}
+
+	if err := services.SelectBatchCandidate(
+		c.Request().Context(),
+		b.db.Conn(),
+		b.q,
+		row,
+		candidateID,
+		config.App.BatchReportConcurrency,
+	); err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to select batch candidate",
+			"error", err,
+			"batch_row_id", rowID,
+		)
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to select company for %s: %v", row.InputName, err)); flashErr != nil {
+			return flashErr
+		}
+	}
+
+	return getSSE(c).Redirect(routes.BatchShow.GetPath(batchID))
+}
+
+func (b Batches) Download(c echo.Context) error {
+	batchID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	batch, err := models.FindBatch(c.Request().Context(), b.db.Conn(), currentWorkspace(c).ID, batchID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	var buf bytes.Buffer
+	if err := services.WriteBatchArchive(c.Request().Context(), b.db.Conn(), &buf, batch); err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to build batch archive",
+			"error", err,
+			"batch_id", batchID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("batch-%s.zip", batch.ID.String())),
+	)
+
+	return c.Blob(http.StatusOK, "application/zip", buf.Bytes())
+}
+
+// rowsWithCandidates fetches the rows of a batch together with the company
+// candidates of every row that waits for review.
+func (b Batches) rowsWithCandidates(
+	c echo.Context,
+	batch models.Batch,
+) ([]models.BatchRow, map[uuid.UUID][]models.CompanyCandidates, error) {
+	rows, err := models.FindBatchRowsByBatchID(c.Request().Context(), b.db.Conn(), batch.ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch batch rows",
+			"error", err,
+			"batch_id", batch.ID,
+		)
+		return nil, nil, err
+	}
+
+	candidates := make(map[uuid.UUID][]models.CompanyCandidates)
+	for _, row := range rows {
+		if row.Status != models.BatchRowNeedsReview {
+			continue
+		}
+
+		rowCandidates, err := models.FindCompanyCandidatesByResearchBriefID(
+			c.Request().Context(),
+			b.db.Conn(),
+			row.ResearchBriefID,
+		)
+		if err != nil {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to fetch company candidates",
+				"error", err,
+				"batch_row_id", row.ID,
+			)
+			continue
+		}
+		candidates[row.ID] = rowCandidates
+	}
+
+	return rows, candidates, nil
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+const companiesPageSize = 25
+
+type Companies struct {
+	db database.SQLite
+}
+
+func newCompanies(db database.SQLite) Companies {
+	return Companies{db}
+}
+
+func (co Companies) Index(c echo.Context) error {
+	search := strings.TrimSpace(c.QueryParam("q"))
+
+	companies, err := models.PaginateCompanies(
+		c.Request().Context(),
+		co.db.Conn(),
+		currentWorkspace(c).ID,
+		search,
+		pageParam(c),
+		companiesPageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch companies",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.CompanyIndex(companies, search))
+}
+
+func (co Companies) Show(c echo.Context) error {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	workspaceID := currentWorkspace(c).ID
+
+	company, err := models.FindCompany(c.Request().Context(), co.db.Conn(), workspaceID, companyID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to fetch company",
+				"error", err,
+				"company_id", companyID,
+			)
+			return render(c, views.InternalError())
+		}
+		return render(c, views.NotFound())
+	}
+
+	// Links to a merged duplicate keep working.
+	if company.Merged() {
+		return c.Redirect(http.StatusFound, routes.CompanyShow.GetPath(uuid.MustParse(company.MergedIntoID)))
+	}
+
+	researchBriefs, err := models.FindResearchBriefsByCompanyID(
+		c.Request().Context(),
+		co.db.Conn(),
+		workspaceID,
+		company.ID,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch research briefs of company",
+			"error", err,
+			"company_id", company.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	reports, err := models.FindReportsByCompanyID(c.Request().Context(), co.db.Conn(), workspaceID, company.ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch reports of company",
+			"error", err,
+			"company_id", company.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	changes := make(map[uuid.UUID]models.WatchlistChange, len(reports))
+	for _, report := range reports {
+		change, err := models.FindWatchlistChangeByReportID(c.Request().Context(), co.db.Conn(), report.ID)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to fetch watchlist change of report",
+					"error", err,
+					"report_id", report.ID,
+				)
+			}
+			continue
+		}
+		changes[report.ID] = change
+	}
+
+	duplicates, err := models.FindCompanyDuplicates(c.Request().Context(), co.db.Conn(), company)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch duplicates of company",
+			"error", err,
+			"company_id", company.ID,
+		)
+		duplicates = nil
+	}
+
+	merged, err := models.FindMergedCompanies(c.Request().Context(), co.db.Conn(), company.ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch companies merged into company",
+			"error", err,
+			"company_id", company.ID,
+		)
+		merged = nil
+	}
+
+	findings, err := models.FindLatestCompanyFindings(c.Request().Context(), co.db.Conn(), company.ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch findings of company",
+			"error", err,
+			"company_id", company.ID,
+		)
+		findings = nil
+	}
+
+	return render(c, views.CompanyShow(
+		company,
+		currentMembership(c),
+		researchBriefs,
+		reports,
+		changes,
+		duplicates,
+		merged,
+		findings,
+	))
+}
+
+func (co Companies) Merge(c echo.Context) error {
+	companyID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	duplicate, err := services.MergeCompanies(
+		c.Request().Context(),
+		co.db.Conn(),
+		currentWorkspace(c).ID,
+		companyID,
+		c.FormValue("domain"),
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return render(c, views.NotFound())
+		}
+
+		message := err.Error()
+		if !errors.Is(err, services.ErrCompanyMergeSelf) &&
+			!errors.Is(err, services.ErrCompanyMerged) &&
+			!errors.Is(err, services.ErrCompanyMergeUnknown) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to merge companies",
+				"error", err,
+				"company_id", companyID,
+			)
+			message = "Failed to merge the companies"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.CompanyShow.GetPath(companyID))
+	}
+
+	if flashErr := cookies.AddFlash(
+		c,
+		cookies.FlashSuccess,
+		"Merged "+duplicate.Name+" ("+duplicate.Domain+") into this company",
+	); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.CompanyShow.GetPath(companyID))
+}
@@ -3,10 +3,15 @@ package controllers
 import (
 	"context"
 	"net/http"
+	"slices"
+	"strconv"
 
+	"github.com/google/uuid"
 	"github.com/mbvlabs/plyo-hackathon/agents"
 	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
 	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/middleware"
 	"github.com/starfederation/datastar-go/datastar"
 	"maragu.dev/goqite"
 
@@ -21,6 +26,18 @@ type Controllers struct {
 	Pages          Pages
 	ResearchBriefs ResearchBriefs
 	Reports        Reports
+	Companies      Companies
+	Watchlists     Watchlists
+	Batches        Batches
+	Webhooks       Webhooks
+	APIKeys        APIKeys
+	ShareLinks     ShareLinks
+	ToolCalls      ToolCalls
+	Sessions       Sessions
+	OIDC           OIDC
+	Registrations  Registrations
+	Workspaces     Workspaces
+	Invitations    Invitations
 }
 
 func New(
@@ -40,9 +57,24 @@ func New(
 
 	assets := newAssets()
 	pages := newPages(db, q, pageCacher)
-	api := newAPI(db)
+	api := newAPI(db, q)
 	researchbriefs := newResearchBriefs(prelimAgent, db)
 	reports := newReports(db, q)
+	companies := newCompanies(db)
+	watchlists := newWatchlists(db, q)
+	batches := newBatches(db, q)
+	webhooks := newWebhooks(db)
+	apiKeys := newAPIKeys(db)
+	shareLinks := newShareLinks(db)
+	toolCalls := newToolCalls(db)
+	sessions := newSessions(db)
+	oidc, err := newOIDC(db)
+	if err != nil {
+		return Controllers{}, err
+	}
+	registrations := newRegistrations(db)
+	workspaces := newWorkspaces(db)
+	invitations := newInvitations(db)
 
 	return Controllers{
 		assets,
@@ -50,6 +82,18 @@ func New(
 		pages,
 		researchbriefs,
 		reports,
+		companies,
+		watchlists,
+		batches,
+		webhooks,
+		apiKeys,
+		shareLinks,
+		toolCalls,
+		sessions,
+		oidc,
+		registrations,
+		workspaces,
+		invitations,
 	}, nil
 }
 
@@ -78,6 +122,61 @@ func render(ctx echo.Context, t templ.Component) error {
 	return ctx.HTML(http.StatusOK, buf.String())
 }
 
+// currentUser returns the user set by middleware.RequireUser or
+// middleware.APIKeyAuth, or the zero User for requests without one.
+func currentUser(c echo.Context) models.User {
+	user, _ := c.Get(middleware.UserContextKey).(models.User)
+	return user
+}
+
+// currentUserID returns the id of the current user as stored on owned
+// records, or "" for requests without one.
+func currentUserID(c echo.Context) string {
+	user := currentUser(c)
+	if user.ID == uuid.Nil {
+		return ""
+	}
+
+	return user.ID.String()
+}
+
+// currentWorkspace returns the workspace set by middleware.RequireWorkspace
+// or middleware.APIKeyAuth, which every query of the request is scoped to.
+func currentWorkspace(c echo.Context) models.Workspace {
+	workspace, _ := c.Get(middleware.WorkspaceContextKey).(models.Workspace)
+	return workspace
+}
+
+// currentMembership returns the membership granting the current user access
+// to the current workspace.
+func currentMembership(c echo.Context) models.WorkspaceMembership {
+	membership, _ := c.Get(middleware.WorkspaceMembershipContextKey).(models.WorkspaceMembership)
+	return membership
+}
+
+// pageParam returns the page requested in the page query parameter, or the
+// first page when it is missing or invalid.
+func pageParam(c echo.Context) int64 {
+	page, err := strconv.ParseInt(c.QueryParam("page"), 10, 64)
+	if err != nil || page < 1 {
+		return 1
+	}
+
+	return page
+}
+
+// choiceParam returns the query parameter name when it is one of choices,
+// so list filters and sort orders fall back to their defaults on anything
+// unexpected.
+func choiceParam(c echo.Context, name string, choices []string) string {
+	value := c.QueryParam(name)
+	if !slices.Contains(choices, value) {
+		return ""
+	}
+
+	return value
+}
+
 func getSSE(c echo.Context) *datastar.ServerSentEventGenerator {
 	return datastar.NewSSE(c.Response(), c.Request())
 }
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+type Invitations struct {
+	db database.SQLite
+}
+
+func newInvitations(db database.SQLite) Invitations {
+	return Invitations{db}
+}
+
+func (i Invitations) Show(c echo.Context) error {
+	// Keep the token out of referrers.
+	c.Response().Header().Set("Referrer-Policy", "no-referrer")
+
+	token := c.Param("token")
+
+	invitation, err := models.FindWorkspaceInvitationByToken(c.Request().Context(), i.db.Conn(), token)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	workspace, err := models.FindWorkspace(
+		c.Request().Context(),
+		i.db.Conn(),
+		uuid.MustParse(invitation.WorkspaceID),
+	)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	var problem string
+	switch {
+	case invitation.Accepted(), invitation.Revoked():
+		problem = services.ErrInvitationInvalid.Error() + "."
+	case invitation.Expired(time.Now()):
+		problem = services.ErrInvitationExpired.Error() + ", ask an owner of the workspace for a new one."
+	case !invitation.MatchesEmail(currentUser(c).Email):
+		problem = "This invitation was sent to " + invitation.Email + ". Sign in with that email address to accept it."
+	}
+
+	return render(c, views.InvitationShow(invitation, workspace, token, problem))
+}
+
+func (i Invitations) Accept(c echo.Context) error {
+	token := c.Param("token")
+
+	membership, err := services.AcceptWorkspaceInvitation(
+		c.Request().Context(),
+		i.db.Conn(),
+		token,
+		currentUser(c),
+		time.Now(),
+	)
+	if err != nil {
+		message := err.Error()
+		if !errors.Is(err, services.ErrInvitationInvalid) &&
+			!errors.Is(err, services.ErrInvitationExpired) &&
+			!errors.Is(err, services.ErrInvitationEmailMismatch) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to accept workspace invitation",
+				"error", err,
+			)
+			message = "Failed to accept invitation"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.InvitationShow.GetPath(token))
+	}
+
+	if err := cookies.SetCurrentWorkspace(c, uuid.MustParse(membership.WorkspaceID)); err != nil {
+		return err
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "You joined the workspace"); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/providers"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+// OIDC signs users in through the configured OpenID Connect identity
+// provider. Its routes answer with not found while single sign-on is off.
+type OIDC struct {
+	db          database.SQLite
+	provider    *providers.OIDC
+	mapping     services.OIDCRoleMapping
+	workspaceID uuid.UUID
+}
+
+func newOIDC(db database.SQLite) (OIDC, error) {
+	if !config.OIDC.Enabled() {
+		if config.OIDC.Enforce {
+			return OIDC{}, errors.New("OIDC_ENFORCE needs OIDC_ISSUER_URL, or nobody could sign in")
+		}
+		return OIDC{db: db}, nil
+	}
+
+	mapping, err := services.ParseOIDCRoleMapping(config.OIDC.RoleMapping, config.OIDC.DefaultRole)
+	if err != nil {
+		return OIDC{}, fmt.Errorf("OIDC_ROLE_MAPPING: %w", err)
+	}
+
+	workspaceID := models.DefaultWorkspaceID
+	if config.OIDC.WorkspaceID != "" {
+		workspaceID, err = uuid.Parse(config.OIDC.WorkspaceID)
+		if err != nil {
+			return OIDC{}, fmt.Errorf("OIDC_WORKSPACE_ID: %w", err)
+		}
+	}
+
+	provider := providers.NewOIDC(providers.OIDCConfig{
+		IssuerURL:    config.OIDC.IssuerURL,
+		ClientID:     config.OIDC.ClientID,
+		ClientSecret: config.OIDC.ClientSecret,
+		RedirectURL:  config.App.GetFullDomain() + routes.OIDCCallback.Path,
+		Scopes:       strings.Fields(config.OIDC.Scopes),
+	})
+
+	return OIDC{db, provider, mapping, workspaceID}, nil
+}
+
+func (o OIDC) Start(c echo.Context) error {
+	if o.provider == nil {
+		return render(c, views.NotFound())
+	}
+
+	flow := cookies.OIDCFlow{Next: safeRedirectPath(c.QueryParam("next"))}
+	for _, secret := range []*string{&flow.State, &flow.Nonce, &flow.Verifier} {
+		value, err := providers.NewOIDCSecret()
+		if err != nil {
+			return err
+		}
+		*secret = value
+	}
+
+	authURL, err := o.provider.AuthCodeURL(
+		c.Request().Context(),
+		flow.State,
+		flow.Nonce,
+		flow.Verifier,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to start single sign-on",
+			"error", err,
+		)
+		return o.failed(c, flow.Next, "Single sign-on is unavailable right now, please try again later")
+	}
+
+	if err := cookies.SetOIDCFlow(c, flow); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, authURL)
+}
+
+func (o OIDC) Callback(c echo.Context) error {
+	if o.provider == nil {
+		return render(c, views.NotFound())
+	}
+
+	flow, ok, err := cookies.PopOIDCFlow(c)
+	if err != nil {
+		return err
+	}
+	if !ok || subtle.ConstantTimeCompare([]byte(flow.State), []byte(c.QueryParam("state"))) != 1 {
+		return o.failed(c, routes.HomePage.Path, "Your sign-in expired, please try again")
+	}
+
+	if providerErr := c.QueryParam("error"); providerErr != "" {
+		slog.WarnContext(
+			c.Request().Context(),
+			"identity provider refused single sign-on",
+			"error", providerErr,
+			"description", c.QueryParam("error_description"),
+		)
+		return o.failed(c, flow.Next, "The identity provider did not sign you in")
+	}
+
+	rawIDToken, err := o.provider.Exchange(c.Request().Context(), c.QueryParam("code"), flow.Verifier)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to exchange single sign-on code",
+			"error", err,
+		)
+		return o.failed(c, flow.Next, "Failed to sign in, please try again")
+	}
+
+	claims, err := o.provider.VerifyIDToken(
+		c.Request().Context(),
+		rawIDToken,
+		flow.Nonce,
+		config.OIDC.GroupsClaim,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to verify single sign-on id token",
+			"error", err,
+		)
+		return o.failed(c, flow.Next, "Failed to sign in, please try again")
+	}
+
+	// A user signed in with their password confirms that an existing
+	// account with the identity's email is theirs to link.
+	linking := services.OIDCAccountLinking{ByEmail: config.OIDC.LinkExistingAccounts}
+	if app := cookies.GetApp(c); app.IsAuthenticated {
+		linking.SignedInUserID = app.UserID
+	}
+
+	user, err := services.SignInWithOIDC(
+		c.Request().Context(),
+		o.db.Conn(),
+		claims,
+		o.workspaceID,
+		o.mapping,
+		linking,
+		time.Now(),
+	)
+	if err != nil {
+		message := err.Error()
+		if !errors.Is(err, services.ErrOIDCEmailMissing) &&
+			!errors.Is(err, services.ErrOIDCNoRole) &&
+			!errors.Is(err, services.ErrOIDCAccountConflict) &&
+			!errors.Is(err, services.ErrOIDCAccountExists) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to sign in with single sign-on",
+				"error", err,
+				"subject", claims.Subject,
+			)
+			message = "Failed to sign in, please try again"
+		}
+		return o.failed(c, flow.Next, message)
+	}
+
+	if err := cookies.CreateAuthenticatedSession(c, user.ID); err != nil {
+		return err
+	}
+
+	if err := cookies.SetCurrentWorkspace(c, o.workspaceID); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, flow.Next)
+}
+
+// failed sends the user back to the login page with the reason sign-in
+// failed.
+func (o OIDC) failed(c echo.Context, next string, message string) error {
+	if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.SessionNew.Path+"?"+url.Values{"next": {next}}.Encode())
+}
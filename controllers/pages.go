@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"log/slog"
+
 	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
 	"maragu.dev/goqite"
 
 	"github.com/mbvlabs/plyo-hackathon/views"
@@ -25,8 +28,27 @@ func newPages(
 	return Pages{db, q, cache}
 }
 
+// recentReportsCount is how many of the latest reports the home page lists.
+const recentReportsCount = 5
+
 func (p Pages) Home(c echo.Context) error {
-	return render(c, views.Home())
+	recent, err := models.PaginateReports(
+		c.Request().Context(),
+		p.db.Conn(),
+		currentWorkspace(c).ID,
+		models.ReportFilter{},
+		1,
+		recentReportsCount,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch recent reports",
+			"error", err,
+		)
+	}
+
+	return render(c, views.Home(recent.Reports))
 }
 
 func (p Pages) NotFound(c echo.Context) error {
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+type Registrations struct {
+	db database.SQLite
+}
+
+func newRegistrations(db database.SQLite) Registrations {
+	return Registrations{db}
+}
+
+func (r Registrations) New(c echo.Context) error {
+	if cookies.GetApp(c).IsAuthenticated {
+		return c.Redirect(http.StatusSeeOther, routes.HomePage.Path)
+	}
+
+	if config.OIDC.Enforce {
+		return passwordsDisabled(c)
+	}
+
+	return render(c, views.Register())
+}
+
+func (r Registrations) Create(c echo.Context) error {
+	if config.OIDC.Enforce {
+		return passwordsDisabled(c)
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	if form.Get("password") != form.Get("password_confirmation") {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "The passwords do not match"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.RegistrationNew.Path)
+	}
+
+	user, err := services.RegisterUser(
+		c.Request().Context(),
+		r.db.Conn(),
+		form.Get("email"),
+		form.Get("password"),
+	)
+	if err != nil {
+		var message string
+		switch {
+		case errors.Is(err, services.ErrEmailTaken), errors.Is(err, services.ErrPasswordTooShort):
+			message = err.Error()
+		case errors.Is(err, models.ErrDomainValidation):
+			message = "Enter a valid email address"
+		default:
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to register user",
+				"error", err,
+			)
+			message = "Failed to create your account, please try again"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.RegistrationNew.Path)
+	}
+
+	if err := cookies.CreateAuthenticatedSession(c, user.ID); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.HomePage.Path)
+}
@@ -1,21 +1,21 @@
 package controllers
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
-	"time"
+	"net/http"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/gommon/log"
-	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/config"
 	"github.com/mbvlabs/plyo-hackathon/database"
 	"github.com/mbvlabs/plyo-hackathon/models"
 	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/services"
 	"github.com/mbvlabs/plyo-hackathon/views"
 	"maragu.dev/goqite"
-	"maragu.dev/goqite/jobs"
 )
 
 type Reports struct {
@@ -30,6 +30,59 @@ func newReports(
 	return Reports{db, q}
 }
 
+const reportsPageSize = 25
+
+func (r Reports) Index(c echo.Context) error {
+	filter := models.ReportFilter{
+		Search: strings.TrimSpace(c.QueryParam("q")),
+		Status: choiceParam(c, "status", models.ReportStatusFilters),
+		Sort:   choiceParam(c, "sort", models.ReportSorts),
+	}
+
+	reports, err := models.PaginateReports(
+		c.Request().Context(),
+		r.db.Conn(),
+		currentWorkspace(c).ID,
+		filter,
+		pageParam(c),
+		reportsPageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch reports",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.ReportIndex(reports, filter))
+}
+
+func (r Reports) Search(c echo.Context) error {
+	query := strings.TrimSpace(c.QueryParam("q"))
+
+	results, err := models.SearchReports(
+		c.Request().Context(),
+		r.db.Conn(),
+		currentWorkspace(c).ID,
+		query,
+		pageParam(c),
+		reportsPageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to search reports",
+			"error", err,
+			"query", query,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.ReportSearch(results, query))
+}
+
 func (r Reports) Create(c echo.Context) error {
 	id := c.QueryParam("id")
 
@@ -47,6 +100,7 @@ func (r Reports) Create(c echo.Context) error {
 	candidate, err := models.FindCompanyCandidates(
 		c.Request().Context(),
 		r.db.Conn(),
+		currentWorkspace(c).ID,
 		companyCandidateID,
 	)
 	if err != nil {
@@ -59,28 +113,10 @@ func (r Reports) Create(c echo.Context) error {
 		return render(c, views.NotFound())
 	}
 
-	data := models.CreateReportData{
-		CompanyCandidateID:               candidate.ID.String(),
-		CompanyName:                      candidate.Name,
-		Status:                           "pending",
-		ProgressPercentage:               0,
-		PreliminaryResearchCompleted:     true, // Since we already have the preliminary research
-		CompanyIntelligenceCompleted:     false,
-		CompetitiveIntelligenceCompleted: false,
-		MarketDynamicsCompleted:          false,
-		TrendAnalysisCompleted:           false,
-		CompanyIntelligenceData:          "",
-		CompetitiveIntelligenceData:      "",
-		MarketDynamicsData:               "",
-		TrendAnalysisData:                "",
-		FinalReport:                      "",
-		CompletedAt:                      time.Time{},
-	}
-
-	report, err := models.CreateReport(
+	report, err := services.CreateReport(
 		c.Request().Context(),
 		r.db.Conn(),
-		data,
+		candidate,
 	)
 	if err != nil {
 		slog.ErrorContext(
@@ -114,6 +150,7 @@ func (r Reports) Show(c echo.Context) error {
 	report, err := models.FindReport(
 		c.Request().Context(),
 		r.db.Conn(),
+		currentWorkspace(c).ID,
 		reportUUID,
 	)
 	if err != nil {
@@ -130,91 +167,68 @@ func (r Reports) Show(c echo.Context) error {
 		company, err := models.FindCompanyCandidates(
 			c.Request().Context(),
 			r.db.Conn(),
+			currentWorkspace(c).ID,
 			uuid.MustParse(report.CompanyCandidateID),
 		)
 		if err != nil {
 			return err
 		}
 
-		if err = models.UpdateReportProgress(
+		if err := services.StartResearch(
 			c.Request().Context(),
 			r.db.Conn(),
-			reportUUID,
+			r.q,
+			report,
+			company.Domain,
 		); err != nil {
 			slog.ErrorContext(
 				c.Request().Context(),
-				"failed to update report progress",
+				"failed to start research",
 				"error", err,
 				"report_id", reportUUID,
 			)
+			return render(c, views.InternalError())
 		}
+	}
 
-		// INTEL
-		params := agents.CompanyIntelligenceJobParams{
-			ReportID:      report.ID,
-			CandidateName: report.CompanyName,
-			CompanyURL:    company.Domain,
-		}
-		data, err := json.Marshal(params)
-		if err != nil {
-			log.Info("Error marshalling job", "error", err)
-		}
-
-		if err := jobs.Create(c.Request().Context(), r.q, agents.CompanyIntelligenceJobName, data); err != nil {
-			log.Info("Error creating job", "error", err)
-		}
-
-		// COMPETITORS
-		competitiveParams := agents.CompetitiveIntelligenceJobParams{
-			ReportID:      report.ID,
-			CandidateName: report.CompanyName,
-			CompanyURL:    company.Domain,
-		}
-		competitiveData, err := json.Marshal(competitiveParams)
-		if err != nil {
-			log.Info("Error marshalling job", "error", err)
-		}
-
-		if err := jobs.Create(c.Request().Context(), r.q, agents.CompetitiveIntelligenceJobName, competitiveData); err != nil {
-			log.Info("Error creating job", "error", err)
-		}
-
-		// TREND
-		trendParams := agents.TrendAnalysisJobParams{
-			ReportID:      report.ID,
-			CandidateName: report.CompanyName,
-			CompanyURL:    company.Domain,
-		}
-		trendData, err := json.Marshal(trendParams)
-		if err != nil {
-			log.Info("Error marshalling job", "error", err)
-		}
+	return render(c, views.ReportChat(report))
+}
 
-		if err := jobs.Create(c.Request().Context(), r.q, agents.TrendAnalysisJobName, trendData); err != nil {
-			log.Info("Error creating job", "error", err)
-		}
+func (r Reports) Cancel(c echo.Context) error {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
 
-		// MARKET
-		marketParams := agents.MarketDynamicsJobParams{
-			ReportID:      report.ID,
-			CandidateName: report.CompanyName,
-			CompanyURL:    company.Domain,
-		}
-		marketData, err := json.Marshal(marketParams)
-		if err != nil {
-			log.Info("Error marshalling job", "error", err)
-		}
+	report, err := models.FindReport(c.Request().Context(), r.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
 
-		if err := jobs.Create(c.Request().Context(), r.q, agents.MarketDynamicsJobName, marketData); err != nil {
-			log.Info("Error creating job", "error", err)
+	canceled, err := services.CancelReport(
+		c.Request().Context(),
+		r.db.Conn(),
+		r.q,
+		report.ID,
+		config.App.BatchReportConcurrency,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to cancel report",
+			"error", err,
+			"report_id", reportID,
+		)
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to cancel report: %v", err)); flashErr != nil {
+			return flashErr
 		}
-
-		if err := models.UpdateReportProgressToStarted(c.Request().Context(), r.db.Conn(), report.ID); err != nil {
-			return err
+	} else if !canceled {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "The report has already finished"); flashErr != nil {
+			return flashErr
 		}
 	}
 
-	return render(c, views.ReportChat(report))
+	return getSSE(c).Redirect(fmt.Sprintf("/reports/%s", report.ID.String()))
 }
 
 func (r Reports) TrackReportProgress(c echo.Context) error {
@@ -237,34 +251,26 @@ func (r Reports) TrackReportProgress(c echo.Context) error {
 	report, err := models.FindReport(
 		c.Request().Context(),
 		r.db.Conn(),
+		currentWorkspace(c).ID,
 		reportUUID,
 	)
 	if err != nil {
 		return c.String(404, "Report not found")
 	}
 
-	if allAgentsCompleted(report) && report.FinalReport == "" {
-		company, err := models.FindCompanyCandidates(
+	if services.AllAgentsCompleted(report) && report.FinalReport == "" {
+		if err := services.EnqueueReportGeneration(
 			c.Request().Context(),
 			r.db.Conn(),
-			uuid.MustParse(report.CompanyCandidateID),
-		)
-		if err != nil {
-			return err
-		}
-
-		reportGenParams := agents.ReportGeneratorJobParams{
-			ReportID:      report.ID,
-			CandidateName: report.CompanyName,
-			CompanyURL:    company.Domain,
-		}
-		reportGenData, err := json.Marshal(reportGenParams)
-		if err != nil {
-			log.Info("Error marshalling job", "error", err)
-		}
-
-		if err := jobs.Create(c.Request().Context(), r.q, agents.ReportGeneratorJobName, reportGenData); err != nil {
-			log.Info("Error creating job", "error", err)
+			r.q,
+			report.ID,
+		); err != nil {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to enqueue report generation",
+				"error", err,
+				"report_id", reportUUID,
+			)
 		}
 
 		return sse.PatchElementTempl(views.ReportGenerationProgress(report))
@@ -299,6 +305,7 @@ func (r Reports) TrackReportGeneration(c echo.Context) error {
 	report, err := models.FindReport(
 		c.Request().Context(),
 		r.db.Conn(),
+		currentWorkspace(c).ID,
 		reportUUID,
 	)
 	if err != nil {
@@ -311,9 +318,39 @@ func (r Reports) TrackReportGeneration(c echo.Context) error {
 	return sse.PatchElementTempl(views.ReportGenerationProgress(report))
 }
 
-func allAgentsCompleted(report models.Report) bool {
-	return report.CompanyIntelligenceCompleted &&
-		report.CompetitiveIntelligenceCompleted &&
-		report.MarketDynamicsCompleted &&
-		report.TrendAnalysisCompleted
+func (r Reports) Export(c echo.Context) error {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	report, err := models.FindReport(c.Request().Context(), r.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	format := c.Param("format")
+	content, err := services.ExportReport(c.Request().Context(), r.db.Conn(), report, format)
+	switch {
+	case errors.Is(err, services.ErrUnknownExportFormat):
+		return render(c, views.NotFound())
+	case errors.Is(err, services.ErrReportNotReady):
+		return render(c, views.BadRequest())
+	case err != nil:
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to export report",
+			"error", err,
+			"report_id", reportID,
+			"format", format,
+		)
+		return render(c, views.InternalError())
+	}
+
+	c.Response().Header().Set(
+		echo.HeaderContentDisposition,
+		fmt.Sprintf("attachment; filename=%q", services.ReportExportFileName(report, format)),
+	)
+
+	return c.Blob(http.StatusOK, services.ReportExportContentType(format), content)
 }
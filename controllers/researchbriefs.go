@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"time"
+	"strings"
 
+	"github.com/a-h/templ"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/mbvlabs/plyo-hackathon/agents"
 	"github.com/mbvlabs/plyo-hackathon/database"
 	"github.com/mbvlabs/plyo-hackathon/models"
 	"github.com/mbvlabs/plyo-hackathon/router/cookies"
 	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/tools"
 	"github.com/mbvlabs/plyo-hackathon/views"
 )
 
@@ -24,50 +28,86 @@ func newResearchBriefs(agent agents.PreliminaryResearch, db database.SQLite) Res
 	return ResearchBriefs{agent, db}
 }
 
-//
-// func (r ResearchBriefs) Index(c echo.Context) error {
-// 	page := int64(1)
-// 	if p := c.QueryParam("page"); p != "" {
-// 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
-// 			page = int64(parsed)
-// 		}
-// 	}
-//
-// 	perPage := int64(25)
-// 	if pp := c.QueryParam("per_page"); pp != "" {
-// 		if parsed, err := strconv.Atoi(pp); err == nil && parsed > 0 &&
-// 			parsed <= 100 {
-// 			perPage = int64(parsed)
-// 		}
-// 	}
-//
-// 	researchbriefsList, err := models.PaginateResearchBriefs(
-// 		c.Request().Context(),
-// 		r.db.Conn(),
-// 		page,
-// 		perPage,
-// 	)
-// 	if err != nil {
-// 		return render(c, views.InternalError())
-// 	}
-//
-// 	return c.HTML(http.StatusOK, "researchbriefs index - no views implemented")
-// }
-//
-// func (r ResearchBriefs) Show(c echo.Context) error {
-// 	researchbriefID, err := uuid.Parse(c.Param("id"))
-// 	if err != nil {
-// 		return render(c, views.BadRequest())
-// 	}
-//
-// 	researchbrief, err := models.FindResearchBrief(c.Request().Context(), r.db.Conn(), researchbriefID)
-// 	if err != nil {
-// 		return render(c, views.NotFound())
-// 	}
-//
-// 	return c.HTML(http.StatusOK, "researchbrief show - no views implemented")
-// }
-//
+const researchBriefsPageSize = 25
+
+func (r ResearchBriefs) Index(c echo.Context) error {
+	workspaceID := currentWorkspace(c).ID
+
+	statuses, err := models.ResearchBriefIdentificationStatuses(
+		c.Request().Context(),
+		r.db.Conn(),
+		workspaceID,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch research brief statuses",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	filter := models.ResearchBriefFilter{
+		Search:               strings.TrimSpace(c.QueryParam("q")),
+		IdentificationStatus: choiceParam(c, "status", statuses),
+		Sort:                 choiceParam(c, "sort", models.ResearchBriefSorts),
+	}
+
+	researchBriefs, err := models.PaginateResearchBriefs(
+		c.Request().Context(),
+		r.db.Conn(),
+		workspaceID,
+		filter,
+		pageParam(c),
+		researchBriefsPageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch research briefs",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.ResearchBriefIndex(researchBriefs, filter, statuses))
+}
+
+func (r ResearchBriefs) Show(c echo.Context) error {
+	researchBriefID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	researchBrief, err := models.FindResearchBrief(
+		c.Request().Context(),
+		r.db.Conn(),
+		currentWorkspace(c).ID,
+		researchBriefID,
+	)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	reports, err := models.FindReportsByResearchBriefID(
+		c.Request().Context(),
+		r.db.Conn(),
+		currentWorkspace(c).ID,
+		researchBrief.ID,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch reports of research brief",
+			"error", err,
+			"research_brief_id", researchBrief.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.ResearchBriefShow(researchBrief, r.results(c, researchBrief), reports))
+}
+
 // func (r ResearchBriefs) New(c echo.Context) error {
 // 	return c.HTML(http.StatusOK, "researchbrief new - no views implemented")
 // }
@@ -89,29 +129,20 @@ func (r ResearchBriefs) Create(c echo.Context) error {
 		return render(c, views.NotFound())
 	}
 
-	result, err := r.agent.Research(c.Request().Context(), payload.Query)
+	result, err := r.agent.Research(
+		tools.WithCallScope(c.Request().Context(), currentWorkspace(c).ID.String(), ""),
+		payload.Query,
+	)
 	if err != nil {
 		return err
 	}
 
-	data := models.CreateResearchBriefData{
-		IdentificationStatus: result.IdentificationStatus,
-		CompanyName:          result.CompanyName,
-		OfficialDomain:       result.OfficialDomain,
-		Headquarters:         result.Headquarters,
-		Industry:             result.Industry,
-		CompanyType:          result.CompanyType,
-		Status:               result.Status,
-		GeographicScope:      result.GeographicScope,
-		ResearchDepth:        result.ResearchDepth,
-		ConfidenceScore:      result.ConfidenceScore,
-		LastUpdated:          time.Now(),
-	}
-
-	researchbrief, err := models.CreateResearchBrief(
+	researchbrief, err := services.SaveResearchBrief(
 		c.Request().Context(),
 		r.db.Conn(),
-		data,
+		result,
+		currentUserID(c),
+		currentWorkspace(c).ID.String(),
 	)
 	if err != nil {
 		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to create researchbrief: %v", err)); flashErr != nil {
@@ -120,96 +151,18 @@ func (r ResearchBriefs) Create(c echo.Context) error {
 		return c.Redirect(http.StatusSeeOther, routes.ResearchBriefNew.Path)
 	}
 
-	// Store CompanyCandidates
-	for _, candidate := range result.CompanyCandidates {
-		_, err := models.CreateCompanyCandidates(
-			c.Request().Context(),
-			r.db.Conn(),
-			models.CreateCompanyCandidatesData{
-				ResearchBriefID: researchbrief.ID.String(),
-				Name:            candidate.Name,
-				Domain:          candidate.Domain,
-				Description:     candidate.Description,
-				Industry:        candidate.Industry,
-				Location:        candidate.Location,
-			},
-		)
-		if err != nil {
-			slog.ErrorContext(
-				c.Request().Context(),
-				"failed to create company candidate",
-				"error", err,
-				"research_brief_id", researchbrief.ID,
-			)
-		}
-	}
-
-	// Store SpecialConsiderations
-	for _, consideration := range result.SpecialConsiderations {
-		_, err := models.CreateSpecialConsiderations(
-			c.Request().Context(),
-			r.db.Conn(),
-			models.CreateSpecialConsiderationsData{
-				ResearchBriefID: researchbrief.ID.String(),
-				Consideration:   consideration,
-			},
-		)
-		if err != nil {
-			slog.ErrorContext(
-				c.Request().Context(),
-				"failed to create special consideration",
-				"error", err,
-				"research_brief_id", researchbrief.ID,
-			)
-		}
-	}
-
-	// Store Sources
-	for _, source := range result.Sources {
-		_, err := models.CreateSources(
-			c.Request().Context(),
-			r.db.Conn(),
-			models.CreateSourcesData{
-				ResearchBriefID: researchbrief.ID.String(),
-				SourceUrl:       source,
-			},
-		)
-		if err != nil {
-			slog.ErrorContext(
-				c.Request().Context(),
-				"failed to create source",
-				"error", err,
-				"research_brief_id", researchbrief.ID,
-			)
-		}
-	}
-
-	// Store AgentGuidance
-	for key, value := range result.AgentGuidance {
-		_, err := models.CreateAgentGuidance(
-			c.Request().Context(),
-			r.db.Conn(),
-			models.CreateAgentGuidanceData{
-				ResearchBriefID: researchbrief.ID.String(),
-				GuidanceKey:     key,
-				GuidanceValue:   value,
-			},
-		)
-		if err != nil {
-			slog.ErrorContext(
-				c.Request().Context(),
-				"failed to create agent guidance",
-				"error", err,
-				"research_brief_id", researchbrief.ID,
-			)
-		}
-	}
-
 	// if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "ResearchBrief created successfully"); flashErr != nil {
 	// 	return render(c, views.InternalError())
 	// }
 
-	// Fetch related data
+	sse := getSSE(c)
+	return sse.PatchElementTempl(r.results(c, researchbrief))
+}
+
+// results renders a research brief with its company candidates, special
+// considerations, sources and agent guidance. Parts that fail to load are
+// left out, so the brief itself can always be shown.
+func (r ResearchBriefs) results(c echo.Context, researchbrief models.ResearchBrief) templ.Component {
 	companyCandidates, err := models.FindCompanyCandidatesByResearchBriefID(
 		c.Request().Context(),
 		r.db.Conn(),
@@ -270,15 +223,12 @@ func (r ResearchBriefs) Create(c echo.Context) error {
 		agentGuidances = []models.AgentGuidance{}
 	}
 
-	sse := getSSE(c)
-	return sse.PatchElementTempl(
-		views.PreliminaryResearchResults(
-			researchbrief,
-			companyCandidates,
-			specialConsiderations,
-			sources,
-			agentGuidances,
-		),
+	return views.PreliminaryResearchResults(
+		researchbrief,
+		companyCandidates,
+		specialConsiderations,
+		sources,
+		agentGuidances,
 	)
 }
 
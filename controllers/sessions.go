@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+type Sessions struct {
+	db database.SQLite
+}
+
+func newSessions(db database.SQLite) Sessions {
+	return Sessions{db}
+}
+
+func (s Sessions) New(c echo.Context) error {
+	if cookies.GetApp(c).IsAuthenticated {
+		return c.Redirect(http.StatusSeeOther, routes.HomePage.Path)
+	}
+
+	return render(c, views.Login(
+		safeRedirectPath(c.QueryParam("next")),
+		ssoProviderName(),
+		!config.OIDC.Enforce,
+	))
+}
+
+func (s Sessions) Create(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	next := safeRedirectPath(form.Get("next"))
+
+	if config.OIDC.Enforce {
+		return passwordsDisabled(c)
+	}
+
+	user, err := services.AuthenticateUser(
+		c.Request().Context(),
+		s.db.Conn(),
+		form.Get("email"),
+		form.Get("password"),
+	)
+	if err != nil {
+		message := services.ErrInvalidCredentials.Error()
+		if !errors.Is(err, services.ErrInvalidCredentials) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to authenticate user",
+				"error", err,
+			)
+			message = "Failed to sign in, please try again"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.SessionNew.Path+"?"+url.Values{"next": {next}}.Encode())
+	}
+
+	if err := cookies.CreateAuthenticatedSession(c, user.ID); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, next)
+}
+
+func (s Sessions) Destroy(c echo.Context) error {
+	if err := cookies.DestroyAuthenticatedSession(c); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.SessionNew.Path)
+}
+
+// safeRedirectPath returns next when it is a path on this site, so the
+// login form cannot be used to redirect to other sites.
+func safeRedirectPath(next string) string {
+	if !strings.HasPrefix(next, "/") || strings.HasPrefix(next, "//") || strings.HasPrefix(next, "/\\") {
+		return routes.HomePage.Path
+	}
+
+	return next
+}
+
+// ssoProviderName names the single sign-on identity provider on the login
+// page, or is empty while single sign-on is off.
+func ssoProviderName() string {
+	if !config.OIDC.Enabled() {
+		return ""
+	}
+
+	return config.OIDC.ProviderName
+}
+
+// passwordsDisabled turns away password sign-in and registration while
+// single sign-on is enforced.
+func passwordsDisabled(c echo.Context) error {
+	if flashErr := cookies.AddFlash(
+		c,
+		cookies.FlashError,
+		"Sign in with "+config.OIDC.ProviderName+" instead",
+	); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.SessionNew.Path)
+}
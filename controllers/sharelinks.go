@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+const shareAccessLogLimit = 50
+
+type ShareLinks struct {
+	db database.SQLite
+}
+
+func newShareLinks(db database.SQLite) ShareLinks {
+	return ShareLinks{db}
+}
+
+func (s ShareLinks) Index(c echo.Context) error {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	report, err := models.FindReport(c.Request().Context(), s.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	links, err := models.FindReportShareLinksByReportID(c.Request().Context(), s.db.Conn(), report.ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch share links",
+			"error", err,
+			"report_id", report.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	accesses, err := models.FindReportShareAccessesByReportID(
+		c.Request().Context(),
+		s.db.Conn(),
+		report.ID,
+		shareAccessLogLimit,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch share link accesses",
+			"error", err,
+			"report_id", report.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	urls := make(map[uuid.UUID]string, len(links))
+	for _, link := range links {
+		urls[link.ID] = services.ReportShareURL(link)
+	}
+
+	return render(c, views.ShareLinkIndex(report, links, urls, accesses, time.Now()))
+}
+
+func (s ShareLinks) Create(c echo.Context) error {
+	reportID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	report, err := models.FindReport(c.Request().Context(), s.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	form, err := c.FormParams()
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	// A missing or malformed expiry is rejected by ShareReport.
+	days, _ := strconv.Atoi(form.Get("expires_in_days"))
+
+	_, err = services.ShareReport(
+		c.Request().Context(),
+		s.db.Conn(),
+		report,
+		form.Get("label"),
+		time.Duration(days)*24*time.Hour,
+	)
+	if err != nil {
+		message := "Pick an expiry of at most 90 days and keep the label under 100 characters"
+		switch {
+		case errors.Is(err, services.ErrReportNotReady):
+			message = "Reports can be shared once the final report has been generated"
+		case !errors.Is(err, models.ErrDomainValidation):
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to create share link",
+				"error", err,
+				"report_id", report.ID,
+			)
+			message = "Failed to create share link"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.ShareLinkIndex.GetPath(report.ID))
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Share link created"); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.ShareLinkIndex.GetPath(report.ID))
+}
+
+func (s ShareLinks) Revoke(c echo.Context) error {
+	linkID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	link, err := models.FindReportShareLink(c.Request().Context(), s.db.Conn(), linkID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	reportID, err := uuid.Parse(link.ReportID)
+	if err != nil {
+		return render(c, views.InternalError())
+	}
+
+	report, err := models.FindReport(c.Request().Context(), s.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	if _, err := models.RevokeReportShareLink(c.Request().Context(), s.db.Conn(), link.ID); err != nil {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to revoke share link: %v", err)); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(routes.ShareLinkIndex.GetPath(report.ID))
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Share link revoked"); flashErr != nil {
+		return flashErr
+	}
+
+	return getSSE(c).Redirect(routes.ShareLinkIndex.GetPath(report.ID))
+}
+
+// Shared renders the public, read-only view of a report opened through a
+// share link.
+func (s ShareLinks) Shared(c echo.Context) error {
+	// Keep the token out of referrers and shared reports out of search
+	// engines.
+	c.Response().Header().Set("Referrer-Policy", "no-referrer")
+	c.Response().Header().Set("X-Robots-Tag", "noindex, nofollow")
+
+	link, report, err := services.OpenSharedReport(
+		c.Request().Context(),
+		s.db.Conn(),
+		c.Param("token"),
+		services.ShareVisitor{
+			IPAddress: c.RealIP(),
+			UserAgent: c.Request().UserAgent(),
+		},
+	)
+	switch {
+	case errors.Is(err, services.ErrShareLinkInvalid):
+		slog.InfoContext(
+			c.Request().Context(),
+			"rejected invalid share link",
+			"remote_addr", c.RealIP(),
+		)
+		return render(c, views.NotFound())
+	case errors.Is(err, services.ErrShareLinkExpired):
+		return render(c, views.SharedReportUnavailable("This share link has expired."))
+	case errors.Is(err, services.ErrShareLinkRevoked):
+		return render(c, views.SharedReportUnavailable("This share link has been revoked."))
+	case err != nil:
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to open share link",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	slog.InfoContext(
+		c.Request().Context(),
+		"report opened through share link",
+		"report_share_link_id", link.ID,
+		"report_id", report.ID,
+		"remote_addr", c.RealIP(),
+	)
+
+	return render(c, views.SharedReport(report, link))
+}
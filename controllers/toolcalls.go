@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+const toolCallsPageSize = 50
+
+// ToolCalls shows workspace owners every search and scrape the agents made,
+// to check vendor invoices and agent behaviour against.
+type ToolCalls struct {
+	db database.SQLite
+}
+
+func newToolCalls(db database.SQLite) ToolCalls {
+	return ToolCalls{db}
+}
+
+func (t ToolCalls) Index(c echo.Context) error {
+	workspaceID := currentWorkspace(c).ID
+
+	agents, err := models.FindToolCallAgents(c.Request().Context(), t.db.Conn(), workspaceID)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "failed to fetch tool call agents", "error", err)
+		return render(c, views.InternalError())
+	}
+
+	toolNames, err := models.FindToolCallTools(c.Request().Context(), t.db.Conn(), workspaceID)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "failed to fetch tool call tools", "error", err)
+		return render(c, views.InternalError())
+	}
+
+	filter := models.ToolCallFilter{
+		Agent:  choiceParam(c, "agent", agents),
+		Tool:   choiceParam(c, "tool", toolNames),
+		Status: choiceParam(c, "status", models.ToolCallStatuses),
+		Search: strings.TrimSpace(c.QueryParam("q")),
+	}
+	if reportID, err := uuid.Parse(strings.TrimSpace(c.QueryParam("report"))); err == nil {
+		filter.ReportID = reportID.String()
+	}
+
+	toolCalls, err := models.PaginateToolCalls(
+		c.Request().Context(),
+		t.db.Conn(),
+		workspaceID,
+		filter,
+		pageParam(c),
+		toolCallsPageSize,
+	)
+	if err != nil {
+		slog.ErrorContext(c.Request().Context(), "failed to fetch tool calls", "error", err)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.ToolCallIndex(toolCalls, filter, agents, toolNames))
+}
@@ -0,0 +1,225 @@
+package controllers
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+	"maragu.dev/goqite"
+)
+
+const recentWatchlistChangesLimit = 20
+
+type Watchlists struct {
+	db database.SQLite
+	q  *goqite.Queue
+}
+
+func newWatchlists(
+	db database.SQLite,
+	q *goqite.Queue,
+) Watchlists {
+	return Watchlists{db, q}
+}
+
+func (w Watchlists) Index(c echo.Context) error {
+	watchlists, err := models.AllWatchlists(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch watchlists",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	latestChanges := make(map[uuid.UUID]models.WatchlistChange, len(watchlists))
+	for _, watchlist := range watchlists {
+		changes, err := models.FindWatchlistChangesByWatchlistID(
+			c.Request().Context(),
+			w.db.Conn(),
+			watchlist.ID,
+		)
+		if err != nil {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to fetch watchlist changes",
+				"error", err,
+				"watchlist_id", watchlist.ID,
+			)
+			continue
+		}
+
+		if len(changes) > 0 {
+			latestChanges[watchlist.ID] = changes[0]
+		}
+	}
+
+	recentChanges, err := models.LatestWatchlistChanges(
+		c.Request().Context(),
+		w.db.Conn(),
+		recentWatchlistChangesLimit,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch recent watchlist changes",
+			"error", err,
+		)
+		recentChanges = []models.WatchlistChange{}
+	}
+
+	return render(c, views.WatchlistIndex(watchlists, latestChanges, recentChanges))
+}
+
+func (w Watchlists) Create(c echo.Context) error {
+	reportID, err := uuid.Parse(c.QueryParam("report_id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	schedule := c.QueryParam("schedule")
+	if schedule != models.WatchlistScheduleMonthly {
+		schedule = models.WatchlistScheduleWeekly
+	}
+
+	report, err := models.FindReport(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID, reportID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	now := time.Now()
+
+	existing, err := models.FindWatchlistByCompanyCandidateID(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentWorkspace(c).ID,
+		report.CompanyCandidateID,
+	)
+	switch {
+	case err == nil:
+		from := existing.LastRunAt
+		if from.IsZero() {
+			from = now
+		}
+
+		_, err = models.UpdateWatchlistSchedule(
+			c.Request().Context(),
+			w.db.Conn(),
+			models.UpdateWatchlistScheduleData{
+				ID:        existing.ID,
+				Schedule:  schedule,
+				NextRunAt: models.NextWatchlistRun(schedule, from),
+			},
+		)
+	case errors.Is(err, sql.ErrNoRows):
+		company, findErr := models.FindCompanyCandidates(
+			c.Request().Context(),
+			w.db.Conn(),
+			currentWorkspace(c).ID,
+			uuid.MustParse(report.CompanyCandidateID),
+		)
+		if findErr != nil {
+			return render(c, views.NotFound())
+		}
+
+		// A finished report becomes the baseline for the first comparison, so
+		// there is no need to re-research the company straight away.
+		data := models.CreateWatchlistData{
+			CompanyCandidateID: report.CompanyCandidateID,
+			CompanyName:        report.CompanyName,
+			CompanyURL:         company.Domain,
+			Schedule:           schedule,
+			NextRunAt:          now,
+			WorkspaceID:        currentWorkspace(c).ID.String(),
+		}
+		if report.FinalReport != "" {
+			data.LastReportID = report.ID.String()
+			data.NextRunAt = models.NextWatchlistRun(schedule, now)
+		}
+
+		_, err = models.CreateWatchlist(c.Request().Context(), w.db.Conn(), data)
+	}
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to add report to watchlist",
+			"error", err,
+			"report_id", reportID,
+		)
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to add %s to the watchlist: %v", report.CompanyName, err)); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(fmt.Sprintf("/reports/%s", report.ID.String()))
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, fmt.Sprintf("%s is re-researched %s", report.CompanyName, schedule)); flashErr != nil {
+		return flashErr
+	}
+
+	return getSSE(c).Redirect(routes.WatchlistIndex.Path)
+}
+
+func (w Watchlists) Run(c echo.Context) error {
+	watchlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	watchlist, err := models.FindWatchlist(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID, watchlistID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	report, err := services.ReResearchWatchlist(
+		c.Request().Context(),
+		w.db.Conn(),
+		w.q,
+		watchlist,
+		time.Now(),
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to re-research watchlist entry",
+			"error", err,
+			"watchlist_id", watchlistID,
+		)
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to re-research %s: %v", watchlist.CompanyName, err)); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(routes.WatchlistIndex.Path)
+	}
+
+	return getSSE(c).Redirect(fmt.Sprintf("/reports/%s", report.ID.String()))
+}
+
+func (w Watchlists) Destroy(c echo.Context) error {
+	watchlistID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	if err := models.DestroyWatchlist(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID, watchlistID); err != nil {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to remove watchlist entry: %v", err)); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(routes.WatchlistIndex.Path)
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Removed from watchlist"); flashErr != nil {
+		return flashErr
+	}
+
+	return getSSE(c).Redirect(routes.WatchlistIndex.Path)
+}
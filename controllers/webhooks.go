@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+const webhookDeliveryLogLimit = 50
+
+type Webhooks struct {
+	db database.SQLite
+}
+
+func newWebhooks(db database.SQLite) Webhooks {
+	return Webhooks{db}
+}
+
+func (w Webhooks) Index(c echo.Context) error {
+	endpoints, err := models.AllWebhookEndpoints(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch webhook endpoints",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.WebhookIndex(endpoints, models.WebhookEvents))
+}
+
+func (w Webhooks) Create(c echo.Context) error {
+	form, err := c.FormParams()
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	endpoint, err := services.CreateWebhookEndpoint(
+		c.Request().Context(),
+		w.db.Conn(),
+		models.CreateWebhookEndpointData{
+			URL:         strings.TrimSpace(form.Get("url")),
+			Description: strings.TrimSpace(form.Get("description")),
+			Events:      form["events"],
+			WorkspaceID: currentWorkspace(c).ID.String(),
+		},
+	)
+	if errors.Is(err, services.ErrWebhookURLNotPublic) {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Webhook URLs must point to a public host, not a private or local address"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.WebhookIndex.Path)
+	}
+	if err != nil {
+		if !errors.Is(err, models.ErrDomainValidation) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to create webhook endpoint",
+				"error", err,
+			)
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Enter a valid http(s) URL and pick at least one event"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.WebhookIndex.Path)
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.WebhookShow.GetPath(endpoint.ID))
+}
+
+func (w Webhooks) Show(c echo.Context) error {
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	endpoint, err := models.FindWebhookEndpoint(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID, endpointID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	deliveries, err := models.FindWebhookDeliveriesByEndpointID(
+		c.Request().Context(),
+		w.db.Conn(),
+		endpoint.ID,
+		webhookDeliveryLogLimit,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch webhook deliveries",
+			"error", err,
+			"webhook_endpoint_id", endpoint.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.WebhookShow(endpoint, deliveries))
+}
+
+func (w Webhooks) Toggle(c echo.Context) error {
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	endpoint, err := models.FindWebhookEndpoint(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID, endpointID)
+	if err != nil {
+		return render(c, views.NotFound())
+	}
+
+	if err := models.UpdateWebhookEndpointActive(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentWorkspace(c).ID,
+		endpoint.ID,
+		!endpoint.Active,
+	); err != nil {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to update webhook: %v", err)); flashErr != nil {
+			return flashErr
+		}
+	}
+
+	return getSSE(c).Redirect(routes.WebhookShow.GetPath(endpoint.ID))
+}
+
+func (w Webhooks) Destroy(c echo.Context) error {
+	endpointID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	if err := models.DestroyWebhookEndpoint(c.Request().Context(), w.db.Conn(), currentWorkspace(c).ID, endpointID); err != nil {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, fmt.Sprintf("Failed to remove webhook: %v", err)); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(routes.WebhookShow.GetPath(endpointID))
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Webhook removed"); flashErr != nil {
+		return flashErr
+	}
+
+	return getSSE(c).Redirect(routes.WebhookIndex.Path)
+}
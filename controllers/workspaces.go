@@ -0,0 +1,294 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/services"
+	"github.com/mbvlabs/plyo-hackathon/views"
+)
+
+type Workspaces struct {
+	db database.SQLite
+}
+
+func newWorkspaces(db database.SQLite) Workspaces {
+	return Workspaces{db}
+}
+
+func (w Workspaces) Index(c echo.Context) error {
+	workspaces, err := models.FindWorkspacesByUserID(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentUser(c).ID,
+	)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch workspaces",
+			"error", err,
+		)
+		return render(c, views.InternalError())
+	}
+
+	return render(c, views.WorkspaceIndex(workspaces, currentWorkspace(c).ID))
+}
+
+func (w Workspaces) Create(c echo.Context) error {
+	workspace, err := services.CreateWorkspaceWithOwner(
+		c.Request().Context(),
+		w.db.Conn(),
+		c.FormValue("name"),
+		currentUser(c).ID,
+	)
+	if err != nil {
+		if !errors.Is(err, models.ErrDomainValidation) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to create workspace",
+				"error", err,
+			)
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Enter a workspace name of at most 100 characters"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.WorkspaceIndex.Path)
+	}
+
+	if err := cookies.SetCurrentWorkspace(c, workspace.ID); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+}
+
+// Switch makes another workspace the user belongs to the current one.
+func (w Workspaces) Switch(c echo.Context) error {
+	workspaceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	if _, err := models.FindWorkspaceMembershipByUserID(
+		c.Request().Context(),
+		w.db.Conn(),
+		workspaceID,
+		currentUser(c).ID,
+	); err != nil {
+		return render(c, views.NotFound())
+	}
+
+	if err := cookies.SetCurrentWorkspace(c, workspaceID); err != nil {
+		return err
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.HomePage.Path)
+}
+
+func (w Workspaces) Show(c echo.Context) error {
+	workspace := currentWorkspace(c)
+	membership := currentMembership(c)
+	now := time.Now()
+
+	members, err := models.FindWorkspaceMembers(c.Request().Context(), w.db.Conn(), workspace.ID)
+	if err != nil {
+		slog.ErrorContext(
+			c.Request().Context(),
+			"failed to fetch workspace members",
+			"error", err,
+			"workspace_id", workspace.ID,
+		)
+		return render(c, views.InternalError())
+	}
+
+	var invitations []models.WorkspaceInvitation
+	if membership.Allows(models.WorkspaceRoleOwner) {
+		invitations, err = models.PendingWorkspaceInvitations(
+			c.Request().Context(),
+			w.db.Conn(),
+			workspace.ID,
+			now,
+		)
+		if err != nil {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to fetch workspace invitations",
+				"error", err,
+				"workspace_id", workspace.ID,
+			)
+			return render(c, views.InternalError())
+		}
+	}
+
+	return render(c, views.WorkspaceShow(workspace, membership, members, invitations, now))
+}
+
+func (w Workspaces) Update(c echo.Context) error {
+	if _, err := models.UpdateWorkspaceName(
+		c.Request().Context(),
+		w.db.Conn(),
+		models.UpdateWorkspaceNameData{
+			ID:   currentWorkspace(c).ID,
+			Name: strings.TrimSpace(c.FormValue("name")),
+		},
+	); err != nil {
+		if !errors.Is(err, models.ErrDomainValidation) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to rename workspace",
+				"error", err,
+			)
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Enter a workspace name of at most 100 characters"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Workspace renamed"); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+}
+
+func (w Workspaces) UpdateMember(c echo.Context) error {
+	membershipID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	_, err = services.ChangeWorkspaceMemberRole(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentWorkspace(c).ID,
+		membershipID,
+		c.FormValue("role"),
+	)
+	if err != nil {
+		message := "Pick one of the roles owner, editor or viewer"
+		switch {
+		case errors.Is(err, services.ErrLastOwner):
+			message = "The workspace needs at least one owner, so promote someone else first"
+		case errors.Is(err, models.ErrDomainValidation):
+		default:
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to change workspace member role",
+				"error", err,
+				"membership_id", membershipID,
+			)
+			message = "Failed to change the member's role"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Role changed"); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+}
+
+func (w Workspaces) DestroyMember(c echo.Context) error {
+	membershipID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	if err := services.RemoveWorkspaceMember(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentWorkspace(c).ID,
+		membershipID,
+	); err != nil {
+		message := "Failed to remove member"
+		if errors.Is(err, services.ErrLastOwner) {
+			message = "The workspace needs at least one owner, so promote someone else first"
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, message); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(routes.WorkspaceShow.Path)
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Member removed"); flashErr != nil {
+		return flashErr
+	}
+
+	return getSSE(c).Redirect(routes.WorkspaceShow.Path)
+}
+
+func (w Workspaces) CreateInvitation(c echo.Context) error {
+	invitation, token, err := services.InviteWorkspaceMember(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentWorkspace(c).ID,
+		currentUser(c).ID,
+		c.FormValue("email"),
+		c.FormValue("role"),
+		time.Now(),
+	)
+	if err != nil {
+		if !errors.Is(err, models.ErrDomainValidation) {
+			slog.ErrorContext(
+				c.Request().Context(),
+				"failed to create workspace invitation",
+				"error", err,
+			)
+		}
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Enter a valid email address and pick a role"); flashErr != nil {
+			return flashErr
+		}
+		return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, fmt.Sprintf(
+		"Invitation created. Send %s this link, it cannot be shown again: %s",
+		invitation.Email,
+		services.WorkspaceInvitationURL(token),
+	)); flashErr != nil {
+		return flashErr
+	}
+
+	return c.Redirect(http.StatusSeeOther, routes.WorkspaceShow.Path)
+}
+
+func (w Workspaces) RevokeInvitation(c echo.Context) error {
+	invitationID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return render(c, views.BadRequest())
+	}
+
+	revoked, err := models.RevokeWorkspaceInvitation(
+		c.Request().Context(),
+		w.db.Conn(),
+		currentWorkspace(c).ID,
+		invitationID,
+	)
+	if err != nil || !revoked {
+		if flashErr := cookies.AddFlash(c, cookies.FlashError, "Failed to revoke invitation"); flashErr != nil {
+			return flashErr
+		}
+		return getSSE(c).Redirect(routes.WorkspaceShow.Path)
+	}
+
+	if flashErr := cookies.AddFlash(c, cookies.FlashSuccess, "Invitation revoked"); flashErr != nil {
+		return flashErr
+	}
+
+	return getSSE(c).Redirect(routes.WorkspaceShow.Path)
+}
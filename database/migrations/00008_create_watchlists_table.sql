@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE watchlists (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    company_candidate_id TEXT NOT NULL,
+    company_name TEXT NOT NULL,
+    company_url TEXT NOT NULL,
+    schedule TEXT NOT NULL CHECK (schedule IN ('weekly', 'monthly')),
+
+    next_run_at DATETIME NOT NULL,
+    last_run_at DATETIME,
+    last_report_id TEXT,
+
+    FOREIGN KEY (company_candidate_id) REFERENCES companycandidates(id),
+    FOREIGN KEY (last_report_id) REFERENCES reports(id)
+);
+
+CREATE UNIQUE INDEX watchlists_company_candidate_id_idx ON watchlists (company_candidate_id);
+CREATE INDEX watchlists_next_run_at_idx ON watchlists (next_run_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS watchlists;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE watchlist_changes (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    watchlist_id TEXT NOT NULL,
+    report_id TEXT NOT NULL,
+    previous_report_id TEXT,
+
+    status TEXT NOT NULL DEFAULT 'pending',
+    has_material_changes BOOLEAN DEFAULT FALSE,
+    summary TEXT,
+
+    FOREIGN KEY (watchlist_id) REFERENCES watchlists(id) ON DELETE CASCADE,
+    FOREIGN KEY (report_id) REFERENCES reports(id),
+    FOREIGN KEY (previous_report_id) REFERENCES reports(id)
+);
+
+CREATE INDEX watchlist_changes_watchlist_id_idx ON watchlist_changes (watchlist_id);
+CREATE INDEX watchlist_changes_report_id_idx ON watchlist_changes (report_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS watchlist_changes;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE batch_rows (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    batch_id TEXT NOT NULL,
+    row_number INTEGER NOT NULL,
+    input_name TEXT NOT NULL,
+    input_url TEXT NOT NULL DEFAULT '',
+
+    status TEXT NOT NULL DEFAULT 'pending',
+    research_brief_id TEXT,
+    company_candidate_id TEXT,
+    report_id TEXT,
+    error TEXT,
+
+    FOREIGN KEY (batch_id) REFERENCES batches(id) ON DELETE CASCADE,
+    FOREIGN KEY (research_brief_id) REFERENCES researchbriefs(id),
+    FOREIGN KEY (company_candidate_id) REFERENCES companycandidates(id),
+    FOREIGN KEY (report_id) REFERENCES reports(id)
+);
+
+CREATE INDEX batch_rows_batch_id_idx ON batch_rows (batch_id, row_number);
+CREATE INDEX batch_rows_status_idx ON batch_rows (status, created_at);
+CREATE INDEX batch_rows_report_id_idx ON batch_rows (report_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS batch_rows;
+-- +goose StatementEnd
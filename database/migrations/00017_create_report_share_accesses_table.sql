@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE report_share_accesses (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    report_share_link_id TEXT NOT NULL,
+    outcome TEXT NOT NULL,
+    ip_address TEXT NOT NULL,
+    user_agent TEXT NOT NULL,
+
+    FOREIGN KEY (report_share_link_id) REFERENCES report_share_links(id) ON DELETE CASCADE
+);
+
+CREATE INDEX report_share_accesses_report_share_link_id_idx ON report_share_accesses (report_share_link_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS report_share_accesses;
+-- +goose StatementEnd
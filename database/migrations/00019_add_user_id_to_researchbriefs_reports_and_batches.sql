@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE researchbriefs ADD COLUMN user_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE reports ADD COLUMN user_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE batches ADD COLUMN user_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX researchbriefs_user_id_idx ON researchbriefs (user_id);
+CREATE INDEX reports_user_id_idx ON reports (user_id, created_at);
+CREATE INDEX batches_user_id_idx ON batches (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS batches_user_id_idx;
+DROP INDEX IF EXISTS reports_user_id_idx;
+DROP INDEX IF EXISTS researchbriefs_user_id_idx;
+ALTER TABLE batches DROP COLUMN user_id;
+ALTER TABLE reports DROP COLUMN user_id;
+ALTER TABLE researchbriefs DROP COLUMN user_id;
+-- +goose StatementEnd
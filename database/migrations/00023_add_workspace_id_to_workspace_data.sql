@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+ALTER TABLE researchbriefs ADD COLUMN workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE reports ADD COLUMN workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE batches ADD COLUMN workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE watchlists ADD COLUMN workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE api_keys ADD COLUMN workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE;
+ALTER TABLE webhook_endpoints ADD COLUMN workspace_id TEXT REFERENCES workspaces(id) ON DELETE CASCADE;
+
+-- Everything created before workspaces existed moves into the default
+-- workspace, owned by every user that already has an account.
+INSERT INTO workspaces (id, name) VALUES ('4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60', 'Default workspace');
+
+INSERT INTO workspace_memberships (id, workspace_id, user_id, role)
+SELECT
+    lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' ||
+    substr('89ab', 1 + (abs(random()) % 4), 1) || substr(lower(hex(randomblob(2))), 2) || '-' ||
+    lower(hex(randomblob(6))),
+    '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60',
+    id,
+    'owner'
+FROM users;
+
+UPDATE researchbriefs SET workspace_id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+UPDATE reports SET workspace_id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+UPDATE batches SET workspace_id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+UPDATE watchlists SET workspace_id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+UPDATE api_keys SET workspace_id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+UPDATE webhook_endpoints SET workspace_id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+
+CREATE INDEX researchbriefs_workspace_id_idx ON researchbriefs (workspace_id);
+CREATE INDEX reports_workspace_id_idx ON reports (workspace_id, created_at);
+CREATE INDEX batches_workspace_id_idx ON batches (workspace_id, created_at);
+CREATE INDEX watchlists_workspace_id_idx ON watchlists (workspace_id);
+CREATE INDEX api_keys_workspace_id_idx ON api_keys (workspace_id);
+CREATE INDEX webhook_endpoints_workspace_id_idx ON webhook_endpoints (workspace_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS webhook_endpoints_workspace_id_idx;
+DROP INDEX IF EXISTS api_keys_workspace_id_idx;
+DROP INDEX IF EXISTS watchlists_workspace_id_idx;
+DROP INDEX IF EXISTS batches_workspace_id_idx;
+DROP INDEX IF EXISTS reports_workspace_id_idx;
+DROP INDEX IF EXISTS researchbriefs_workspace_id_idx;
+ALTER TABLE webhook_endpoints DROP COLUMN workspace_id;
+ALTER TABLE api_keys DROP COLUMN workspace_id;
+ALTER TABLE watchlists DROP COLUMN workspace_id;
+ALTER TABLE batches DROP COLUMN workspace_id;
+ALTER TABLE reports DROP COLUMN workspace_id;
+ALTER TABLE researchbriefs DROP COLUMN workspace_id;
+DELETE FROM workspaces WHERE id = '4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60';
+-- +goose StatementEnd
@@ -0,0 +1,97 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- The index keeps its own copy of the text instead of pointing at reports
+-- by rowid, because reports has no integer primary key and VACUUM may
+-- renumber its rowids.
+CREATE VIRTUAL TABLE reports_fts USING fts5(
+    report_id UNINDEXED,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report,
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO reports_fts (
+    report_id,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+)
+SELECT
+    id,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+FROM reports;
+
+CREATE TRIGGER reports_fts_insert AFTER INSERT ON reports BEGIN
+    INSERT INTO reports_fts (
+        report_id,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.id,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_update AFTER UPDATE OF
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+ON reports BEGIN
+    DELETE FROM reports_fts WHERE report_id = old.id;
+    INSERT INTO reports_fts (
+        report_id,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.id,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_delete AFTER DELETE ON reports BEGIN
+    DELETE FROM reports_fts WHERE report_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TRIGGER IF EXISTS reports_fts_delete;
+DROP TRIGGER IF EXISTS reports_fts_update;
+DROP TRIGGER IF EXISTS reports_fts_insert;
+DROP TABLE IF EXISTS reports_fts;
+-- +goose StatementEnd
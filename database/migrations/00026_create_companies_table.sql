@@ -0,0 +1,115 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE companies (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    workspace_id TEXT NOT NULL,
+    domain TEXT NOT NULL,
+    name TEXT NOT NULL,
+    merged_into_id TEXT,
+
+    FOREIGN KEY (workspace_id) REFERENCES workspaces(id) ON DELETE CASCADE,
+    FOREIGN KEY (merged_into_id) REFERENCES companies(id)
+);
+
+CREATE UNIQUE INDEX companies_workspace_id_domain_idx ON companies (workspace_id, domain);
+CREATE INDEX companies_merged_into_id_idx ON companies (merged_into_id);
+
+ALTER TABLE companycandidates ADD COLUMN company_id TEXT REFERENCES companies(id);
+ALTER TABLE reports ADD COLUMN company_id TEXT REFERENCES companies(id);
+
+CREATE INDEX companycandidates_company_id_idx ON companycandidates (company_id);
+CREATE INDEX reports_company_id_idx ON reports (company_id, created_at);
+
+-- Existing candidates are keyed the way the application normalizes domains:
+-- the lower-cased host without scheme, port, path or a leading "www.".
+CREATE TEMP TABLE candidate_domains AS
+WITH
+    lowered AS (
+        SELECT
+            companycandidates.id,
+            companycandidates.name,
+            researchbriefs.workspace_id,
+            researchbriefs.last_updated,
+            lower(trim(companycandidates.domain)) AS domain
+        FROM companycandidates
+        JOIN researchbriefs ON researchbriefs.id = companycandidates.research_brief_id
+        WHERE researchbriefs.workspace_id IS NOT NULL
+    ),
+    without_scheme AS (
+        SELECT id, name, workspace_id, last_updated,
+            CASE WHEN instr(domain, '://') > 0 THEN substr(domain, instr(domain, '://') + 3) ELSE domain END AS domain
+        FROM lowered
+    ),
+    without_path AS (
+        SELECT id, name, workspace_id, last_updated,
+            CASE WHEN instr(domain, '/') > 0 THEN substr(domain, 1, instr(domain, '/') - 1) ELSE domain END AS domain
+        FROM without_scheme
+    ),
+    without_query AS (
+        SELECT id, name, workspace_id, last_updated,
+            CASE WHEN instr(domain, '?') > 0 THEN substr(domain, 1, instr(domain, '?') - 1) ELSE domain END AS domain
+        FROM without_path
+    ),
+    without_fragment AS (
+        SELECT id, name, workspace_id, last_updated,
+            CASE WHEN instr(domain, '#') > 0 THEN substr(domain, 1, instr(domain, '#') - 1) ELSE domain END AS domain
+        FROM without_query
+    ),
+    without_port AS (
+        SELECT id, name, workspace_id, last_updated,
+            CASE WHEN instr(domain, ':') > 0 THEN substr(domain, 1, instr(domain, ':') - 1) ELSE domain END AS domain
+        FROM without_fragment
+    )
+SELECT id, name, workspace_id, last_updated,
+    CASE WHEN domain LIKE 'www.%' THEN substr(domain, 5) ELSE domain END AS domain
+FROM without_port;
+
+DELETE FROM candidate_domains WHERE instr(domain, '.') = 0 OR instr(domain, ' ') > 0;
+
+-- A company is named after its most recently researched candidate.
+INSERT INTO companies (id, workspace_id, domain, name)
+SELECT
+    lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' ||
+    substr('89ab', 1 + (abs(random()) % 4), 1) || substr(lower(hex(randomblob(2))), 2) || '-' ||
+    lower(hex(randomblob(6))),
+    workspace_id,
+    domain,
+    (
+        SELECT latest.name FROM candidate_domains AS latest
+        WHERE latest.workspace_id = candidate_domains.workspace_id
+            AND latest.domain = candidate_domains.domain
+        ORDER BY latest.last_updated DESC
+        LIMIT 1
+    )
+FROM candidate_domains
+GROUP BY workspace_id, domain;
+
+UPDATE companycandidates SET company_id = (
+    SELECT companies.id FROM candidate_domains
+    JOIN companies ON companies.workspace_id = candidate_domains.workspace_id
+        AND companies.domain = candidate_domains.domain
+    WHERE candidate_domains.id = companycandidates.id
+);
+
+UPDATE reports SET company_id = (
+    SELECT companycandidates.company_id FROM companycandidates
+    WHERE companycandidates.id = reports.compay_candidate_id
+);
+
+DROP TABLE candidate_domains;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP INDEX IF EXISTS reports_company_id_idx;
+DROP INDEX IF EXISTS companycandidates_company_id_idx;
+ALTER TABLE reports DROP COLUMN company_id;
+ALTER TABLE companycandidates DROP COLUMN company_id;
+DROP TABLE IF EXISTS companies;
+-- +goose StatementEnd
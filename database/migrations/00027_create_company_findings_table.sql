@@ -0,0 +1,57 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+CREATE TABLE company_findings (
+    id TEXT PRIMARY KEY,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    company_id TEXT NOT NULL,
+    report_id TEXT,
+    prior_finding_id TEXT,
+
+    section TEXT NOT NULL,
+    data TEXT NOT NULL,
+    as_of DATETIME NOT NULL,
+
+    FOREIGN KEY (company_id) REFERENCES companies(id) ON DELETE CASCADE,
+    FOREIGN KEY (report_id) REFERENCES reports(id) ON DELETE SET NULL,
+    FOREIGN KEY (prior_finding_id) REFERENCES company_findings(id) ON DELETE SET NULL
+);
+
+CREATE INDEX company_findings_company_id_section_idx ON company_findings (company_id, section, created_at);
+CREATE INDEX company_findings_report_id_idx ON company_findings (report_id);
+
+-- Sections of completed reports are the first findings, as of the day their
+-- report completed.
+INSERT INTO company_findings (id, company_id, report_id, section, data, as_of)
+SELECT
+    lower(hex(randomblob(4))) || '-' || lower(hex(randomblob(2))) || '-4' ||
+    substr(lower(hex(randomblob(2))), 2) || '-' ||
+    substr('89ab', 1 + (abs(random()) % 4), 1) || substr(lower(hex(randomblob(2))), 2) || '-' ||
+    lower(hex(randomblob(6))),
+    company_id,
+    id,
+    section,
+    data,
+    as_of
+FROM (
+    SELECT company_id, id, 'company_intelligence' AS section, company_intelligence_data AS data, coalesce(completed_at, updated_at) AS as_of
+    FROM reports WHERE status = 'completed' AND company_id IS NOT NULL AND company_intelligence_data <> ''
+    UNION ALL
+    SELECT company_id, id, 'competitive_intelligence', competitive_intelligence_data, coalesce(completed_at, updated_at)
+    FROM reports WHERE status = 'completed' AND company_id IS NOT NULL AND competitive_intelligence_data <> ''
+    UNION ALL
+    SELECT company_id, id, 'market_dynamics', market_dynamics_data, coalesce(completed_at, updated_at)
+    FROM reports WHERE status = 'completed' AND company_id IS NOT NULL AND market_dynamics_data <> ''
+    UNION ALL
+    SELECT company_id, id, 'trend_analysis', trend_analysis_data, coalesce(completed_at, updated_at)
+    FROM reports WHERE status = 'completed' AND company_id IS NOT NULL AND trend_analysis_data <> ''
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TABLE IF EXISTS company_findings;
+-- +goose StatementEnd
@@ -0,0 +1,206 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- The index now reads its text from reports and is keyed by the reports'
+-- rowid, so the triggers replace a report's entry by rowid instead of
+-- scanning the index for its report_id. reports has no integer primary key,
+-- so a VACUUM may renumber its rowids; run
+-- INSERT INTO reports_fts(reports_fts) VALUES('rebuild') after one.
+DROP TRIGGER IF EXISTS reports_fts_delete;
+DROP TRIGGER IF EXISTS reports_fts_update;
+DROP TRIGGER IF EXISTS reports_fts_insert;
+DROP TABLE IF EXISTS reports_fts;
+
+CREATE VIRTUAL TABLE reports_fts USING fts5(
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report,
+    content = 'reports',
+    content_rowid = 'rowid',
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO reports_fts (reports_fts) VALUES ('rebuild');
+
+CREATE TRIGGER reports_fts_insert AFTER INSERT ON reports BEGIN
+    INSERT INTO reports_fts (
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.rowid,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_update AFTER UPDATE OF
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+ON reports BEGIN
+    INSERT INTO reports_fts (
+        reports_fts,
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        'delete',
+        old.rowid,
+        old.company_name,
+        old.company_intelligence_data,
+        old.competitive_intelligence_data,
+        old.market_dynamics_data,
+        old.trend_analysis_data,
+        old.final_report
+    );
+    INSERT INTO reports_fts (
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.rowid,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_delete AFTER DELETE ON reports BEGIN
+    INSERT INTO reports_fts (
+        reports_fts,
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        'delete',
+        old.rowid,
+        old.company_name,
+        old.company_intelligence_data,
+        old.competitive_intelligence_data,
+        old.market_dynamics_data,
+        old.trend_analysis_data,
+        old.final_report
+    );
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TRIGGER IF EXISTS reports_fts_delete;
+DROP TRIGGER IF EXISTS reports_fts_update;
+DROP TRIGGER IF EXISTS reports_fts_insert;
+DROP TABLE IF EXISTS reports_fts;
+
+CREATE VIRTUAL TABLE reports_fts USING fts5(
+    report_id UNINDEXED,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report,
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO reports_fts (
+    report_id,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+)
+SELECT
+    id,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+FROM reports;
+
+CREATE TRIGGER reports_fts_insert AFTER INSERT ON reports BEGIN
+    INSERT INTO reports_fts (
+        report_id,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.id,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_update AFTER UPDATE OF
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+ON reports BEGIN
+    DELETE FROM reports_fts WHERE report_id = old.id;
+    INSERT INTO reports_fts (
+        report_id,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.id,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_delete AFTER DELETE ON reports BEGIN
+    DELETE FROM reports_fts WHERE report_id = old.id;
+END;
+-- +goose StatementEnd
@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Briefs requested through the API are researched in the background, so a
+-- brief can exist before its preliminary research has finished. Every brief
+-- from before then was researched while it was requested.
+ALTER TABLE researchbriefs ADD COLUMN research_status TEXT NOT NULL DEFAULT 'completed';
+ALTER TABLE researchbriefs ADD COLUMN query TEXT NOT NULL DEFAULT '';
+ALTER TABLE researchbriefs ADD COLUMN research_error TEXT NOT NULL DEFAULT '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE researchbriefs DROP COLUMN research_error;
+ALTER TABLE researchbriefs DROP COLUMN query;
+ALTER TABLE researchbriefs DROP COLUMN research_status;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Which backend served a call, and whether it came from the cache, is what
+-- tells a free direct fetch from a billed Serper, ScrapingBee or SearXNG
+-- request when checking vendor invoices.
+ALTER TABLE tool_calls ADD COLUMN backend TEXT NOT NULL DEFAULT '';
+ALTER TABLE tool_calls ADD COLUMN cached BOOLEAN NOT NULL DEFAULT FALSE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE tool_calls DROP COLUMN cached;
+ALTER TABLE tool_calls DROP COLUMN backend;
+-- +goose StatementEnd
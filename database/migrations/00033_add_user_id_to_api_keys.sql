@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- Keys belong to the user who created them rather than to an email address,
+-- so they cannot pass to someone who later registers with that address and
+-- are removed together with their owner.
+ALTER TABLE api_keys ADD COLUMN user_id TEXT REFERENCES users(id) ON DELETE CASCADE;
+
+UPDATE api_keys
+SET user_id = (SELECT users.id FROM users WHERE lower(users.email) = lower(api_keys.owner_email));
+
+-- Keys of an email without an account were refused on every request and
+-- have nobody to belong to.
+DELETE FROM api_keys WHERE user_id IS NULL;
+
+DROP INDEX IF EXISTS api_keys_owner_email_idx;
+ALTER TABLE api_keys DROP COLUMN owner_email;
+
+CREATE INDEX api_keys_user_id_idx ON api_keys (user_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+ALTER TABLE api_keys ADD COLUMN owner_email TEXT NOT NULL DEFAULT '';
+
+UPDATE api_keys
+SET owner_email = (SELECT users.email FROM users WHERE users.id = api_keys.user_id);
+
+CREATE INDEX api_keys_owner_email_idx ON api_keys (owner_email);
+DROP INDEX IF EXISTS api_keys_user_id_idx;
+ALTER TABLE api_keys DROP COLUMN user_id;
+-- +goose StatementEnd
@@ -0,0 +1,217 @@
+-- +goose Up
+-- +goose StatementBegin
+SELECT 'up SQL query';
+-- reports has a TEXT primary key, so its rowids are not stable: VACUUM may
+-- renumber them and point the index at other reports. The index is keyed by
+-- reports_fts_keys instead, whose INTEGER PRIMARY KEY never changes, and
+-- keeps its own copy of the text. A report's entry is still replaced by
+-- rowid, found through the unique report_id, rather than by scanning the
+-- index.
+DROP TRIGGER IF EXISTS reports_fts_delete;
+DROP TRIGGER IF EXISTS reports_fts_update;
+DROP TRIGGER IF EXISTS reports_fts_insert;
+DROP TABLE IF EXISTS reports_fts;
+
+CREATE TABLE reports_fts_keys (
+    id INTEGER PRIMARY KEY,
+    report_id TEXT NOT NULL UNIQUE
+);
+
+CREATE VIRTUAL TABLE reports_fts USING fts5(
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report,
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO reports_fts_keys (report_id) SELECT id FROM reports;
+
+INSERT INTO reports_fts (
+    rowid,
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+)
+SELECT
+    reports_fts_keys.id,
+    reports.company_name,
+    reports.company_intelligence_data,
+    reports.competitive_intelligence_data,
+    reports.market_dynamics_data,
+    reports.trend_analysis_data,
+    reports.final_report
+FROM reports
+JOIN reports_fts_keys ON reports_fts_keys.report_id = reports.id;
+
+CREATE TRIGGER reports_fts_insert AFTER INSERT ON reports BEGIN
+    INSERT INTO reports_fts_keys (report_id) VALUES (new.id);
+    INSERT INTO reports_fts (
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        (SELECT id FROM reports_fts_keys WHERE report_id = new.id),
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_update AFTER UPDATE OF
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+ON reports BEGIN
+    DELETE FROM reports_fts WHERE rowid = (SELECT id FROM reports_fts_keys WHERE report_id = old.id);
+    INSERT INTO reports_fts (
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        (SELECT id FROM reports_fts_keys WHERE report_id = new.id),
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_delete AFTER DELETE ON reports BEGIN
+    DELETE FROM reports_fts WHERE rowid = (SELECT id FROM reports_fts_keys WHERE report_id = old.id);
+    DELETE FROM reports_fts_keys WHERE report_id = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+SELECT 'down SQL query';
+DROP TRIGGER IF EXISTS reports_fts_delete;
+DROP TRIGGER IF EXISTS reports_fts_update;
+DROP TRIGGER IF EXISTS reports_fts_insert;
+DROP TABLE IF EXISTS reports_fts;
+DROP TABLE IF EXISTS reports_fts_keys;
+
+CREATE VIRTUAL TABLE reports_fts USING fts5(
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report,
+    content = 'reports',
+    content_rowid = 'rowid',
+    tokenize = 'porter unicode61'
+);
+
+INSERT INTO reports_fts (reports_fts) VALUES ('rebuild');
+
+CREATE TRIGGER reports_fts_insert AFTER INSERT ON reports BEGIN
+    INSERT INTO reports_fts (
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.rowid,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_update AFTER UPDATE OF
+    company_name,
+    company_intelligence_data,
+    competitive_intelligence_data,
+    market_dynamics_data,
+    trend_analysis_data,
+    final_report
+ON reports BEGIN
+    INSERT INTO reports_fts (
+        reports_fts,
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        'delete',
+        old.rowid,
+        old.company_name,
+        old.company_intelligence_data,
+        old.competitive_intelligence_data,
+        old.market_dynamics_data,
+        old.trend_analysis_data,
+        old.final_report
+    );
+    INSERT INTO reports_fts (
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        new.rowid,
+        new.company_name,
+        new.company_intelligence_data,
+        new.competitive_intelligence_data,
+        new.market_dynamics_data,
+        new.trend_analysis_data,
+        new.final_report
+    );
+END;
+
+CREATE TRIGGER reports_fts_delete AFTER DELETE ON reports BEGIN
+    INSERT INTO reports_fts (
+        reports_fts,
+        rowid,
+        company_name,
+        company_intelligence_data,
+        competitive_intelligence_data,
+        market_dynamics_data,
+        trend_analysis_data,
+        final_report
+    ) VALUES (
+        'delete',
+        old.rowid,
+        old.company_name,
+        old.company_intelligence_data,
+        old.competitive_intelligence_data,
+        old.market_dynamics_data,
+        old.trend_analysis_data,
+        old.final_report
+    );
+END;
+-- +goose StatementEnd
@@ -0,0 +1,31 @@
+-- name: QueryAPIKeyByID :one
+select * from api_keys where id=? and workspace_id=?;
+
+-- name: QueryActiveAPIKeyByHash :one
+select * from api_keys where key_hash=? and revoked_at is null;
+
+-- name: QueryAllAPIKeys :many
+select * from api_keys where workspace_id=? order by user_id asc, created_at desc;
+
+-- name: QueryAPIKeysByUserID :many
+select * from api_keys where workspace_id=? and user_id=? order by created_at desc;
+
+-- name: InsertAPIKey :one
+insert into
+    api_keys (id, created_at, updated_at, user_id, name, prefix, key_hash, workspace_id)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?)
+returning *;
+
+-- name: UpdateAPIKeyLastUsed :exec
+update api_keys set last_used_at=? where id=?;
+
+-- name: RevokeAPIKey :execrows
+update api_keys
+    set updated_at=datetime('now'), revoked_at=datetime('now')
+where id=? and workspace_id=? and revoked_at is null;
+
+-- name: RevokeUserAPIKey :execrows
+update api_keys
+    set updated_at=datetime('now'), revoked_at=datetime('now')
+where id=? and workspace_id=? and user_id=? and revoked_at is null;
@@ -0,0 +1,18 @@
+-- name: QueryBatchByID :one
+select * from batches where id=?;
+
+-- name: QueryBatchByIDAndWorkspaceID :one
+select * from batches where id=? and workspace_id=?;
+
+-- name: QueryRecentBatches :many
+select * from batches where workspace_id=? order by created_at desc limit ?;
+
+-- name: InsertBatch :one
+insert into
+    batches (id, created_at, updated_at, name, user_id, workspace_id)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?)
+returning *;
+
+-- name: DeleteBatch :exec
+delete from batches where id=?;
@@ -0,0 +1,39 @@
+-- name: QueryBatchRowByID :one
+select * from batch_rows where id=?;
+
+-- name: QueryBatchRowByReportID :one
+select * from batch_rows where report_id=?;
+
+-- name: QueryBatchRowsByBatchID :many
+select * from batch_rows where batch_id=? order by row_number asc;
+
+-- name: QueryQueuedBatchRows :many
+select * from batch_rows
+where status = 'queued'
+order by created_at asc, row_number asc
+limit ?;
+
+-- name: CountBatchRowsByStatus :one
+select count(*) from batch_rows where status=?;
+
+-- name: InsertBatchRow :one
+insert into
+    batch_rows (id, created_at, updated_at, batch_id, row_number, input_name, input_url, status)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?)
+returning *;
+
+-- name: UpdateBatchRowStatus :exec
+update batch_rows
+    set updated_at=datetime('now'), status=?, error=?
+where id = ?;
+
+-- name: UpdateBatchRowResearchBrief :exec
+update batch_rows
+    set updated_at=datetime('now'), status=?, research_brief_id=?, company_candidate_id=?
+where id = ?;
+
+-- name: UpdateBatchRowReport :exec
+update batch_rows
+    set updated_at=datetime('now'), status=?, report_id=?
+where id = ?;
@@ -0,0 +1,56 @@
+-- name: QueryCompanyByID :one
+select * from companies where id=?;
+
+-- name: QueryCompanyByIDAndWorkspaceID :one
+select * from companies where id=? and workspace_id=?;
+
+-- name: QueryCompanyByDomain :one
+select * from companies where workspace_id=? and domain=?;
+
+-- name: UpsertCompany :one
+insert into
+    companies (id, created_at, updated_at, workspace_id, domain, name)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?)
+on conflict (workspace_id, domain) do update set updated_at=datetime('now')
+returning *;
+
+-- name: QueryPaginatedCompanies :many
+select
+    companies.*,
+    (select count(*) from reports where reports.company_id = companies.id) as report_count,
+    (select count(distinct companycandidates.research_brief_id) from companycandidates
+        where companycandidates.company_id = companies.id) as research_brief_count
+from companies
+where companies.workspace_id = sqlc.arg(workspace_id)
+    and companies.merged_into_id is null
+    and (instr(lower(companies.name), lower(cast(sqlc.arg(search) as text))) > 0
+        or instr(companies.domain, lower(cast(sqlc.arg(search) as text))) > 0)
+order by lower(companies.name) asc, companies.domain asc
+limit sqlc.arg(limit) offset sqlc.arg(offset);
+
+-- name: CountCompanies :one
+select count(*) from companies
+where workspace_id = sqlc.arg(workspace_id)
+    and merged_into_id is null
+    and (instr(lower(name), lower(cast(sqlc.arg(search) as text))) > 0
+        or instr(domain, lower(cast(sqlc.arg(search) as text))) > 0);
+
+-- name: QueryCompanyDuplicates :many
+select * from companies
+where workspace_id = sqlc.arg(workspace_id)
+    and merged_into_id is null
+    and id != sqlc.arg(id)
+    and lower(name) = lower(cast(sqlc.arg(name) as text))
+order by domain asc;
+
+-- name: QueryMergedCompanies :many
+select * from companies where merged_into_id=? order by domain asc;
+
+-- name: MergeCompany :exec
+update companies
+    set updated_at=datetime('now'), merged_into_id=sqlc.arg(target_id)
+where id = sqlc.arg(source_id) or merged_into_id = sqlc.arg(source_id);
+
+-- name: MoveCompanyCandidatesToCompany :exec
+update companycandidates set company_id=sqlc.arg(target_id) where company_id=sqlc.arg(source_id);
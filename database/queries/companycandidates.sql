@@ -1,6 +1,11 @@
 -- name: QueryCompanyCandidatesByID :one
 select * from companycandidates where id=?;
 
+-- name: QueryCompanyCandidatesByIDAndWorkspaceID :one
+select companycandidates.* from companycandidates
+join researchbriefs on researchbriefs.id = companycandidates.research_brief_id
+where companycandidates.id=? and researchbriefs.workspace_id=?;
+
 -- name: QueryCompanyCandidatesByResearchBriefID :many
 select * from companycandidates where research_brief_id=?;
 
@@ -12,9 +17,9 @@ select * from companycandidates;
 
 -- name: InsertCompanyCandidates :one
 insert into
-    companycandidates (id, research_brief_id, name, domain, description, industry, location)
+    companycandidates (id, research_brief_id, name, domain, description, industry, location, company_id)
 values
-    (?, ?, ?, ?, ?, ?, ?)
+    (?, ?, ?, ?, ?, ?, ?, ?)
 returning *;
 
 -- name: UpdateCompanyCandidates :one
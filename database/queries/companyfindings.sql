@@ -0,0 +1,29 @@
+-- name: QueryLatestCompanyFinding :one
+select * from company_findings
+where company_id=? and section=?
+order by created_at desc, rowid desc
+limit 1;
+
+-- name: QueryLatestCompanyFindings :many
+select * from company_findings
+where company_findings.company_id = sqlc.arg(company_id)
+    and company_findings.rowid = (
+        select latest.rowid from company_findings as latest
+        where latest.company_id = company_findings.company_id
+            and latest.section = company_findings.section
+        order by latest.created_at desc, latest.rowid desc
+        limit 1
+    )
+order by section asc;
+
+-- name: InsertCompanyFinding :one
+insert into
+    company_findings (id, created_at, updated_at, company_id, report_id, prior_finding_id, section, data, as_of)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?)
+returning *;
+
+-- name: MoveCompanyFindingsToCompany :exec
+update company_findings
+    set updated_at=datetime('now'), company_id=sqlc.arg(target_id)
+where company_id = sqlc.arg(source_id);
@@ -0,0 +1,13 @@
+-- name: QueryReportExport :one
+select * from report_exports where report_id=? and format=?;
+
+-- name: UpsertReportExport :one
+insert into
+    report_exports (id, created_at, updated_at, report_id, format, fingerprint, content)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?)
+on conflict (report_id, format) do update
+    set updated_at=datetime('now'),
+        fingerprint=excluded.fingerprint,
+        content=excluded.content
+returning *;
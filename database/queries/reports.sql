@@ -1,17 +1,26 @@
 -- name: QueryReportByID :one
 select * from reports where id=?;
 
+-- name: QueryReportByIDAndWorkspaceID :one
+select * from reports where id=? and workspace_id=?;
+
 -- name: QueryReports :many
 select * from reports;
 
 -- name: QueryAllReports :many
-select * from reports;
+select * from reports where workspace_id=?;
+
+-- name: QueryReportsByResearchBriefID :many
+select reports.* from reports
+join companycandidates on companycandidates.id = reports.compay_candidate_id
+where companycandidates.research_brief_id=? and reports.workspace_id=?
+order by reports.created_at desc;
 
 -- name: InsertReport :one
 insert into
-    reports (id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at)
+    reports (id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id)
 values
-    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 returning *;
 
 -- name: UpdateReport :one
@@ -24,12 +33,26 @@ returning *;
 delete from reports where id=?;
 
 -- name: QueryPaginatedReports :many
-select * from reports 
-order by created_at desc 
-limit ? offset ?;
+select reports.* from reports, (select cast(sqlc.arg(sort) as text) as sort) as params
+where workspace_id = sqlc.arg(workspace_id)
+    and (cast(sqlc.arg(status) as text) = ''
+        or (cast(sqlc.arg(status) as text) = 'active' and status not in ('completed', 'failed', 'canceled'))
+        or status = cast(sqlc.arg(status) as text))
+    and instr(lower(company_name), lower(cast(sqlc.arg(search) as text))) > 0
+order by
+    case when params.sort = 'name' then lower(company_name) end asc,
+    case when params.sort = 'oldest' then created_at end asc,
+    case when params.sort = 'progress' then progress_percentage end desc,
+    created_at desc
+limit sqlc.arg(limit) offset sqlc.arg(offset);
 
 -- name: CountReports :one
-select count(*) from reports;
+select count(*) from reports
+where workspace_id = sqlc.arg(workspace_id)
+    and (cast(sqlc.arg(status) as text) = ''
+        or (cast(sqlc.arg(status) as text) = 'active' and status not in ('completed', 'failed', 'canceled'))
+        or status = cast(sqlc.arg(status) as text))
+    and instr(lower(company_name), lower(cast(sqlc.arg(search) as text))) > 0;
 
 -- name: UpdateCompanyIntelligence :exec
 UPDATE reports
@@ -64,7 +87,8 @@ UPDATE reports
 SET progress_percentage = ?,
     status = ?,
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+    AND status NOT IN ('failed', 'canceled');
 
 -- name: UpdateFinalReport :exec
 UPDATE reports
@@ -72,5 +96,58 @@ SET final_report = ?,
     status = 'completed',
     completed_at = datetime('now'),
     updated_at = datetime('now')
-WHERE id = ?;
+WHERE id = ?
+    AND status <> 'canceled';
+
+
+-- name: UpdateReportToGenerating :execrows
+UPDATE reports
+SET status = 'generating',
+    updated_at = datetime('now')
+WHERE id = ?
+    AND status NOT IN ('generating', 'failed', 'canceled')
+    AND (final_report IS NULL OR final_report = '');
+
+-- name: UpdateReportToFailed :execrows
+UPDATE reports
+SET status = 'failed',
+    updated_at = datetime('now')
+WHERE id = ?
+    AND status NOT IN ('failed', 'canceled')
+    AND (final_report IS NULL OR final_report = '');
+
+-- name: UpdateReportToCanceled :execrows
+UPDATE reports
+SET status = 'canceled',
+    updated_at = datetime('now')
+WHERE id = ?
+    AND status NOT IN ('completed', 'failed', 'canceled')
+    AND (final_report IS NULL OR final_report = '');
+
+-- name: SearchReports :many
+select
+    reports.id,
+    reports.company_name,
+    reports.status,
+    reports.created_at,
+    cast(snippet(reports_fts, -1, char(2), char(3), '...', 16) as text) as snippet
+from reports_fts
+join reports_fts_keys on reports_fts_keys.id = reports_fts.rowid
+join reports on reports.id = reports_fts_keys.report_id,
+    (select cast(sqlc.arg(terms) as text) as terms) as params
+where reports_fts match params.terms and reports.workspace_id = sqlc.arg(workspace_id)
+order by bm25(reports_fts, 10, 1, 1, 1, 1, 1)
+limit sqlc.arg(limit) offset sqlc.arg(offset);
+
+-- name: CountSearchReports :one
+select count(*) from reports_fts
+join reports_fts_keys on reports_fts_keys.id = reports_fts.rowid
+join reports on reports.id = reports_fts_keys.report_id,
+    (select cast(sqlc.arg(terms) as text) as terms) as params
+where reports_fts match params.terms and reports.workspace_id = sqlc.arg(workspace_id);
+
+-- name: QueryReportsByCompanyID :many
+select * from reports where company_id=? and workspace_id=? order by created_at asc;
 
+-- name: MoveReportsToCompany :exec
+update reports set company_id=sqlc.arg(target_id) where company_id=sqlc.arg(source_id);
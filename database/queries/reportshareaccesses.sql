@@ -0,0 +1,13 @@
+-- name: QueryReportShareAccessesByReportID :many
+select report_share_accesses.* from report_share_accesses
+    join report_share_links on report_share_links.id = report_share_accesses.report_share_link_id
+where report_share_links.report_id=?
+order by report_share_accesses.created_at desc
+limit ?;
+
+-- name: InsertReportShareAccess :one
+insert into
+    report_share_accesses (id, created_at, updated_at, report_share_link_id, outcome, ip_address, user_agent)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?)
+returning *;
@@ -0,0 +1,17 @@
+-- name: QueryReportShareLinkByID :one
+select * from report_share_links where id=?;
+
+-- name: QueryReportShareLinksByReportID :many
+select * from report_share_links where report_id=? order by created_at desc;
+
+-- name: InsertReportShareLink :one
+insert into
+    report_share_links (id, created_at, updated_at, report_id, label, expires_at)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?)
+returning *;
+
+-- name: RevokeReportShareLink :execrows
+update report_share_links
+    set updated_at=datetime('now'), revoked_at=datetime('now')
+where id=? and revoked_at is null;
@@ -1,17 +1,20 @@
 -- name: QueryResearchBriefByID :one
 select * from researchbriefs where id=?;
 
+-- name: QueryResearchBriefByIDAndWorkspaceID :one
+select * from researchbriefs where id=? and workspace_id=?;
+
 -- name: QueryResearchBriefs :many
 select * from researchbriefs;
 
 -- name: QueryAllResearchBriefs :many
-select * from researchbriefs;
+select * from researchbriefs where workspace_id=?;
 
 -- name: InsertResearchBrief :one
 insert into
-    researchbriefs (id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated)
+    researchbriefs (id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, query)
 values
-    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 returning *;
 
 -- name: UpdateResearchBrief :one
@@ -20,14 +23,43 @@ update researchbriefs
 where id = ?
 returning *;
 
+-- name: UpdateResearchBriefResearchStatus :exec
+update researchbriefs
+    set research_status=?, research_error=?
+where id = ?;
+
 -- name: DeleteResearchBrief :exec
 delete from researchbriefs where id=?;
 
 -- name: QueryPaginatedResearchBriefs :many
-select * from researchbriefs 
-order by created_at desc 
-limit ? offset ?;
+select researchbriefs.* from researchbriefs, (select cast(sqlc.arg(sort) as text) as sort) as params
+where workspace_id = sqlc.arg(workspace_id)
+    and (cast(sqlc.arg(identification_status) as text) = ''
+        or lower(identification_status) = lower(cast(sqlc.arg(identification_status) as text)))
+    and instr(lower(company_name), lower(cast(sqlc.arg(search) as text))) > 0
+order by
+    case when params.sort = 'name' then lower(company_name) end asc,
+    case when params.sort = 'oldest' then last_updated end asc,
+    case when params.sort = 'confidence' then confidence_score end desc,
+    last_updated desc
+limit sqlc.arg(limit) offset sqlc.arg(offset);
 
 -- name: CountResearchBriefs :one
-select count(*) from researchbriefs;
+select count(*) from researchbriefs
+where workspace_id = sqlc.arg(workspace_id)
+    and (cast(sqlc.arg(identification_status) as text) = ''
+        or lower(identification_status) = lower(cast(sqlc.arg(identification_status) as text)))
+    and instr(lower(company_name), lower(cast(sqlc.arg(search) as text))) > 0;
+
+-- name: QueryResearchBriefIdentificationStatuses :many
+select distinct identification_status from researchbriefs
+where workspace_id=?
+order by identification_status collate nocase;
+
 
+-- name: QueryResearchBriefsByCompanyID :many
+select * from researchbriefs
+where workspace_id = sqlc.arg(workspace_id) and id in (
+    select research_brief_id from companycandidates where company_id = sqlc.arg(company_id)
+)
+order by last_updated desc;
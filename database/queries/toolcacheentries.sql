@@ -0,0 +1,16 @@
+-- name: QueryToolCacheEntry :one
+select * from tool_cache_entries
+where tool=? and cache_key=? and expires_at > sqlc.arg(now);
+
+-- name: UpsertToolCacheEntry :one
+insert into
+    tool_cache_entries (id, created_at, updated_at, tool, cache_key, value, expires_at)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?)
+on conflict (tool, cache_key) do update
+    set updated_at=datetime('now'), value=excluded.value, expires_at=excluded.expires_at
+returning *;
+
+-- name: DeleteExpiredToolCacheEntries :exec
+delete from tool_cache_entries
+where expires_at <= sqlc.arg(now);
@@ -0,0 +1,32 @@
+-- name: InsertToolCall :one
+insert into
+    tool_calls (id, created_at, updated_at, workspace_id, report_id, agent, tool, arguments, status, latency_ms, response_size, error_message, backend, cached)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+returning *;
+
+-- name: QueryPaginatedToolCalls :many
+select * from tool_calls
+where workspace_id = sqlc.arg(workspace_id)
+    and (cast(sqlc.arg(report_id) as text) = '' or report_id = cast(sqlc.arg(report_id) as text))
+    and (cast(sqlc.arg(agent) as text) = '' or agent = cast(sqlc.arg(agent) as text))
+    and (cast(sqlc.arg(tool) as text) = '' or tool = cast(sqlc.arg(tool) as text))
+    and (cast(sqlc.arg(status) as text) = '' or status = cast(sqlc.arg(status) as text))
+    and instr(lower(arguments), lower(cast(sqlc.arg(search) as text))) > 0
+order by created_at desc, rowid desc
+limit sqlc.arg(limit) offset sqlc.arg(offset);
+
+-- name: CountToolCalls :one
+select count(*) from tool_calls
+where workspace_id = sqlc.arg(workspace_id)
+    and (cast(sqlc.arg(report_id) as text) = '' or report_id = cast(sqlc.arg(report_id) as text))
+    and (cast(sqlc.arg(agent) as text) = '' or agent = cast(sqlc.arg(agent) as text))
+    and (cast(sqlc.arg(tool) as text) = '' or tool = cast(sqlc.arg(tool) as text))
+    and (cast(sqlc.arg(status) as text) = '' or status = cast(sqlc.arg(status) as text))
+    and instr(lower(arguments), lower(cast(sqlc.arg(search) as text))) > 0;
+
+-- name: QueryToolCallAgents :many
+select distinct agent from tool_calls where workspace_id=? order by agent asc;
+
+-- name: QueryToolCallTools :many
+select distinct tool from tool_calls where workspace_id=? order by tool asc;
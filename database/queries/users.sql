@@ -0,0 +1,21 @@
+-- name: QueryUserByID :one
+select * from users where id=?;
+
+-- name: QueryUserByEmail :one
+select * from users where email=?;
+
+-- name: QueryUserByOIDCIdentity :one
+select * from users where oidc_issuer=? and oidc_subject=?;
+
+-- name: InsertUser :one
+insert into
+    users (id, created_at, updated_at, email, password_hash, oidc_issuer, oidc_subject)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?)
+returning *;
+
+-- name: UpdateUserLastLogin :exec
+update users set last_login_at=? where id=?;
+
+-- name: UpdateUserOIDCIdentity :exec
+update users set oidc_issuer=?, oidc_subject=?, updated_at=datetime('now') where id=?;
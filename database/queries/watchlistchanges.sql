@@ -0,0 +1,26 @@
+-- name: QueryWatchlistChangeByID :one
+select * from watchlist_changes where id=?;
+
+-- name: QueryWatchlistChangeByReportID :one
+select * from watchlist_changes where report_id=?;
+
+-- name: QueryWatchlistChangesByWatchlistID :many
+select * from watchlist_changes where watchlist_id=? order by created_at desc;
+
+-- name: QueryLatestWatchlistChanges :many
+select * from watchlist_changes
+where status = 'completed'
+order by updated_at desc
+limit ?;
+
+-- name: InsertWatchlistChange :one
+insert into
+    watchlist_changes (id, created_at, updated_at, watchlist_id, report_id, previous_report_id, status, has_material_changes, summary)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?)
+returning *;
+
+-- name: UpdateWatchlistChangeSummary :exec
+update watchlist_changes
+    set updated_at=datetime('now'), status=?, has_material_changes=?, summary=?
+where id = ?;
@@ -0,0 +1,37 @@
+-- name: QueryWatchlistByID :one
+select * from watchlists where id=? and workspace_id=?;
+
+-- name: QueryWatchlistByCompanyCandidateID :one
+select * from watchlists where company_candidate_id=? and workspace_id=?;
+
+-- name: QueryAllWatchlists :many
+select * from watchlists where workspace_id=? order by company_name asc;
+
+-- name: QueryDueWatchlists :many
+select * from watchlists where next_run_at <= ? order by next_run_at asc;
+
+-- name: InsertWatchlist :one
+insert into
+    watchlists (id, created_at, updated_at, company_candidate_id, company_name, company_url, schedule, next_run_at, last_run_at, last_report_id, workspace_id)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?)
+returning *;
+
+-- name: UpdateWatchlistSchedule :one
+update watchlists
+    set updated_at=datetime('now'), schedule=?, next_run_at=?
+where id = ?
+returning *;
+
+-- name: UpdateWatchlistRun :exec
+update watchlists
+    set updated_at=datetime('now'), last_run_at=?, next_run_at=?
+where id = ?;
+
+-- name: UpdateWatchlistLastReport :exec
+update watchlists
+    set updated_at=datetime('now'), last_report_id=?
+where id = ?;
+
+-- name: DeleteWatchlist :exec
+delete from watchlists where id=? and workspace_id=?;
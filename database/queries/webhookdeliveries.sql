@@ -0,0 +1,29 @@
+-- name: QueryWebhookDeliveryByID :one
+select * from webhook_deliveries where id=?;
+
+-- name: QueryWebhookDeliveriesByEndpointID :many
+select * from webhook_deliveries
+where webhook_endpoint_id=?
+order by created_at desc
+limit ?;
+
+-- name: InsertWebhookDelivery :one
+insert into
+    webhook_deliveries (id, created_at, updated_at, webhook_endpoint_id, event, payload, status)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, 'pending')
+returning *;
+
+-- name: UpdateWebhookDeliveryAttempt :exec
+update webhook_deliveries
+    set updated_at=datetime('now'),
+        status=?,
+        attempts=?,
+        last_status_code=?,
+        last_error=?,
+        next_attempt_at=?,
+        delivered_at=?
+where id=?;
+
+-- name: DeleteWebhookDeliveriesByEndpointID :exec
+delete from webhook_deliveries where webhook_endpoint_id=?;
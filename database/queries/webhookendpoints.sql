@@ -0,0 +1,26 @@
+-- name: QueryWebhookEndpointByID :one
+select * from webhook_endpoints where id=?;
+
+-- name: QueryWebhookEndpointByIDAndWorkspaceID :one
+select * from webhook_endpoints where id=? and workspace_id=?;
+
+-- name: QueryAllWebhookEndpoints :many
+select * from webhook_endpoints where workspace_id=? order by created_at desc;
+
+-- name: QueryActiveWebhookEndpoints :many
+select * from webhook_endpoints where workspace_id=? and active = true order by created_at asc;
+
+-- name: InsertWebhookEndpoint :one
+insert into
+    webhook_endpoints (id, created_at, updated_at, url, description, secret, events, active, workspace_id)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, true, ?)
+returning *;
+
+-- name: UpdateWebhookEndpointActive :exec
+update webhook_endpoints
+    set updated_at=datetime('now'), active=?
+where id=? and workspace_id=?;
+
+-- name: DeleteWebhookEndpoint :exec
+delete from webhook_endpoints where id=? and workspace_id=?;
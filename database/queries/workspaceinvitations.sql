@@ -0,0 +1,24 @@
+-- name: QueryWorkspaceInvitationByTokenHash :one
+select * from workspace_invitations where token_hash=?;
+
+-- name: QueryPendingWorkspaceInvitations :many
+select * from workspace_invitations
+where workspace_id=? and accepted_at is null and revoked_at is null and expires_at > ?
+order by created_at desc;
+
+-- name: InsertWorkspaceInvitation :one
+insert into
+    workspace_invitations (id, created_at, updated_at, workspace_id, email, role, token_hash, invited_by_user_id, expires_at)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?)
+returning *;
+
+-- name: AcceptWorkspaceInvitation :execrows
+update workspace_invitations
+    set updated_at=datetime('now'), accepted_at=?
+where id=? and accepted_at is null and revoked_at is null;
+
+-- name: RevokeWorkspaceInvitation :execrows
+update workspace_invitations
+    set updated_at=datetime('now'), revoked_at=datetime('now')
+where id=? and workspace_id=? and accepted_at is null and revoked_at is null;
@@ -0,0 +1,34 @@
+-- name: QueryWorkspaceMembershipByID :one
+select * from workspace_memberships where id=? and workspace_id=?;
+
+-- name: QueryWorkspaceMembershipByWorkspaceIDAndUserID :one
+select * from workspace_memberships where workspace_id=? and user_id=?;
+
+-- name: QueryFirstWorkspaceMembershipByUserID :one
+select * from workspace_memberships where user_id=? order by created_at asc limit 1;
+
+-- name: QueryWorkspaceMembers :many
+select sqlc.embed(workspace_memberships), users.email
+from workspace_memberships
+join users on users.id = workspace_memberships.user_id
+where workspace_memberships.workspace_id=?
+order by users.email asc;
+
+-- name: CountWorkspaceOwners :one
+select count(*) from workspace_memberships where workspace_id=? and role='owner';
+
+-- name: InsertWorkspaceMembership :one
+insert into
+    workspace_memberships (id, created_at, updated_at, workspace_id, user_id, role)
+values
+    (?, datetime('now'), datetime('now'), ?, ?, ?)
+returning *;
+
+-- name: UpdateWorkspaceMembershipRole :one
+update workspace_memberships
+    set updated_at=datetime('now'), role=?
+where id=? and workspace_id=?
+returning *;
+
+-- name: DeleteWorkspaceMembership :exec
+delete from workspace_memberships where id=? and workspace_id=?;
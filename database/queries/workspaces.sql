@@ -0,0 +1,21 @@
+-- name: QueryWorkspaceByID :one
+select * from workspaces where id=?;
+
+-- name: QueryWorkspacesByUserID :many
+select workspaces.* from workspaces
+join workspace_memberships on workspace_memberships.workspace_id = workspaces.id
+where workspace_memberships.user_id=?
+order by workspaces.name asc;
+
+-- name: InsertWorkspace :one
+insert into
+    workspaces (id, created_at, updated_at, name)
+values
+    (?, datetime('now'), datetime('now'), ?)
+returning *;
+
+-- name: UpdateWorkspaceName :one
+update workspaces
+    set updated_at=datetime('now'), name=?
+where id=?
+returning *;
@@ -0,0 +1,238 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// APIKeyTokenPrefix marks plaintext API keys so they are recognisable in
+// configuration files and secret scanners.
+const APIKeyTokenPrefix = "plyo_"
+
+const apiKeyDisplayPrefixLength = 8
+
+type APIKey struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// UserID is the owner of the key, whose role in the workspace the key
+	// acts with.
+	UserID string
+	Name   string
+	// Prefix holds the first characters of the key so it can be recognised
+	// in listings without storing the key itself.
+	Prefix      string
+	LastUsedAt  time.Time
+	RevokedAt   time.Time
+	WorkspaceID string
+}
+
+func (k APIKey) Revoked() bool {
+	return !k.RevokedAt.IsZero()
+}
+
+// HashAPIKey returns the hex encoded SHA-256 of a plaintext key. Keys are
+// random, so a plain hash is enough to make a leaked table useless.
+func HashAPIKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func FindAPIKey(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (APIKey, error) {
+	row, err := db.New().QueryAPIKeyByID(ctx, dbtx, db.NewQueryAPIKeyByIDParams(
+		id.String(),
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return rowToAPIKey(row)
+}
+
+// FindActiveAPIKeyByToken looks up a non-revoked key by its plaintext value.
+// The key decides the workspace of the request, so it is not scoped to one.
+func FindActiveAPIKeyByToken(
+	ctx context.Context,
+	dbtx db.DBTX,
+	token string,
+) (APIKey, error) {
+	row, err := db.New().QueryActiveAPIKeyByHash(ctx, dbtx, HashAPIKey(token))
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return rowToAPIKey(row)
+}
+
+// AllUserAPIKeys returns the keys a user owns in a workspace, newest first.
+func AllUserAPIKeys(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	userID uuid.UUID,
+) ([]APIKey, error) {
+	rows, err := db.New().QueryAPIKeysByUserID(ctx, dbtx, db.NewQueryAPIKeysByUserIDParams(
+		workspaceParam(workspaceID),
+		sql.NullString{String: userID.String(), Valid: true},
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToAPIKeys(rows)
+}
+
+func AllAPIKeys(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]APIKey, error) {
+	rows, err := db.New().QueryAllAPIKeys(ctx, dbtx, workspaceParam(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToAPIKeys(rows)
+}
+
+func rowsToAPIKeys(rows []db.ApiKey) ([]APIKey, error) {
+	keys := make([]APIKey, len(rows))
+	for i, row := range rows {
+		result, err := rowToAPIKey(row)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = result
+	}
+
+	return keys, nil
+}
+
+type CreateAPIKeyData struct {
+	UserID      uuid.UUID `validate:"required"`
+	Name        string    `validate:"required,max=100"`
+	WorkspaceID uuid.UUID `validate:"required"`
+}
+
+// CreateAPIKey generates a new key for the owner and stores its hash. The
+// returned plaintext key is only available here and cannot be recovered.
+func CreateAPIKey(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateAPIKeyData,
+) (APIKey, string, error) {
+	if err := validate.Struct(data); err != nil {
+		return APIKey{}, "", errors.Join(ErrDomainValidation, err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return APIKey{}, "", err
+	}
+	token := APIKeyTokenPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	row, err := db.New().InsertAPIKey(ctx, dbtx, db.NewInsertAPIKeyParams(
+		sql.NullString{String: data.UserID.String(), Valid: true},
+		data.Name,
+		token[:len(APIKeyTokenPrefix)+apiKeyDisplayPrefixLength],
+		HashAPIKey(token),
+		workspaceParam(data.WorkspaceID),
+	))
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	key, err := rowToAPIKey(row)
+	if err != nil {
+		return APIKey{}, "", err
+	}
+
+	return key, token, nil
+}
+
+func TouchAPIKey(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	usedAt time.Time,
+) error {
+	return db.New().UpdateAPIKeyLastUsed(ctx, dbtx, db.NewUpdateAPIKeyLastUsedParams(
+		id.String(),
+		usedAt,
+	))
+}
+
+// RevokeAPIKey revokes a key of the workspace. It returns false when the key
+// does not exist in the workspace or was already revoked.
+func RevokeAPIKey(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (bool, error) {
+	revoked, err := db.New().RevokeAPIKey(ctx, dbtx, db.NewRevokeAPIKeyParams(
+		id.String(),
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return false, err
+	}
+
+	return revoked > 0, nil
+}
+
+// RevokeUserAPIKey revokes a key the user owns in the workspace. It returns
+// false when the user has no such key or it was already revoked.
+func RevokeUserAPIKey(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	userID uuid.UUID,
+	id uuid.UUID,
+) (bool, error) {
+	revoked, err := db.New().RevokeUserAPIKey(ctx, dbtx, db.NewRevokeUserAPIKeyParams(
+		id.String(),
+		workspaceParam(workspaceID),
+		sql.NullString{String: userID.String(), Valid: true},
+	))
+	if err != nil {
+		return false, err
+	}
+
+	return revoked > 0, nil
+}
+
+func rowToAPIKey(row db.ApiKey) (APIKey, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return APIKey{}, err
+	}
+
+	return APIKey{
+		ID:          id,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		UserID:      row.UserID.String,
+		Name:        row.Name,
+		Prefix:      row.Prefix,
+		LastUsedAt:  row.LastUsedAt.Time,
+		RevokedAt:   row.RevokedAt.Time,
+		WorkspaceID: row.WorkspaceID.String,
+	}, nil
+}
@@ -0,0 +1,126 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+type Batch struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+	// UserID is the user who uploaded the batch. It is empty for batches
+	// created before there were user accounts.
+	UserID      string
+	WorkspaceID string
+}
+
+// FindBatch looks up a batch within a workspace.
+func FindBatch(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (Batch, error) {
+	row, err := db.New().QueryBatchByIDAndWorkspaceID(ctx, dbtx, db.NewQueryBatchByIDAndWorkspaceIDParams(
+		id.String(),
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return rowToBatch(row)
+}
+
+// FindBatchByID looks up a batch in any workspace, for background jobs.
+func FindBatchByID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (Batch, error) {
+	row, err := db.New().QueryBatchByID(ctx, dbtx, id.String())
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return rowToBatch(row)
+}
+
+// RecentBatches returns the most recent batches of a workspace, newest
+// first.
+func RecentBatches(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	limit int64,
+) ([]Batch, error) {
+	rows, err := db.New().QueryRecentBatches(ctx, dbtx, db.NewQueryRecentBatchesParams(
+		workspaceParam(workspaceID),
+		limit,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	batches := make([]Batch, len(rows))
+	for i, row := range rows {
+		result, err := rowToBatch(row)
+		if err != nil {
+			return nil, err
+		}
+		batches[i] = result
+	}
+
+	return batches, nil
+}
+
+type CreateBatchData struct {
+	Name        string `validate:"required"`
+	UserID      string `validate:"omitempty,uuid"`
+	WorkspaceID string `validate:"omitempty,uuid"`
+}
+
+func CreateBatch(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateBatchData,
+) (Batch, error) {
+	if err := validate.Struct(data); err != nil {
+		return Batch{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertBatch(ctx, dbtx, db.NewInsertBatchParams(
+		data.Name,
+		sql.NullString{String: data.UserID, Valid: data.UserID != ""},
+		optionalWorkspaceParam(data.WorkspaceID),
+	))
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return rowToBatch(row)
+}
+
+func rowToBatch(row db.Batch) (Batch, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return Batch{}, err
+	}
+
+	return Batch{
+		ID:          id,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		Name:        row.Name,
+		UserID:      row.UserID.String,
+		WorkspaceID: row.WorkspaceID.String,
+	}, nil
+}
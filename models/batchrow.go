@@ -0,0 +1,218 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	// BatchRowPending rows are waiting for preliminary research.
+	BatchRowPending = "pending"
+	// BatchRowNeedsReview rows could not be matched to a single company
+	// with enough confidence and wait for a human to pick a candidate.
+	BatchRowNeedsReview = "needs_review"
+	// BatchRowQueued rows have a selected candidate and wait for a report
+	// slot to free up.
+	BatchRowQueued    = "queued"
+	BatchRowRunning   = "running"
+	BatchRowCompleted = "completed"
+	BatchRowFailed    = "failed"
+)
+
+type BatchRow struct {
+	ID                 uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	BatchID            string
+	RowNumber          int64
+	InputName          string
+	InputURL           string
+	Status             string
+	ResearchBriefID    string
+	CompanyCandidateID string
+	ReportID           string
+	Error              string
+}
+
+func FindBatchRow(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (BatchRow, error) {
+	row, err := db.New().QueryBatchRowByID(ctx, dbtx, id.String())
+	if err != nil {
+		return BatchRow{}, err
+	}
+
+	return rowToBatchRow(row)
+}
+
+func FindBatchRowByReportID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+) (BatchRow, error) {
+	row, err := db.New().QueryBatchRowByReportID(
+		ctx,
+		dbtx,
+		sql.NullString{String: reportID.String(), Valid: true},
+	)
+	if err != nil {
+		return BatchRow{}, err
+	}
+
+	return rowToBatchRow(row)
+}
+
+func FindBatchRowsByBatchID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	batchID uuid.UUID,
+) ([]BatchRow, error) {
+	rows, err := db.New().QueryBatchRowsByBatchID(ctx, dbtx, batchID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToBatchRows(rows)
+}
+
+// QueuedBatchRows returns up to limit rows that are ready for a full report,
+// oldest first across all batches.
+func QueuedBatchRows(
+	ctx context.Context,
+	dbtx db.DBTX,
+	limit int64,
+) ([]BatchRow, error) {
+	rows, err := db.New().QueryQueuedBatchRows(ctx, dbtx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToBatchRows(rows)
+}
+
+func CountBatchRowsByStatus(
+	ctx context.Context,
+	dbtx db.DBTX,
+	status string,
+) (int64, error) {
+	return db.New().CountBatchRowsByStatus(ctx, dbtx, status)
+}
+
+type CreateBatchRowData struct {
+	BatchID   string `validate:"required,uuid"`
+	RowNumber int64
+	InputName string `validate:"required"`
+	InputURL  string
+}
+
+func CreateBatchRow(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateBatchRowData,
+) (BatchRow, error) {
+	if err := validate.Struct(data); err != nil {
+		return BatchRow{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertBatchRow(ctx, dbtx, db.NewInsertBatchRowParams(
+		data.BatchID,
+		data.RowNumber,
+		data.InputName,
+		data.InputURL,
+		BatchRowPending,
+	))
+	if err != nil {
+		return BatchRow{}, err
+	}
+
+	return rowToBatchRow(row)
+}
+
+func UpdateBatchRowStatus(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	status string,
+	errorMessage string,
+) error {
+	return db.New().UpdateBatchRowStatus(ctx, dbtx, db.NewUpdateBatchRowStatusParams(
+		id.String(),
+		status,
+		sql.NullString{String: errorMessage, Valid: errorMessage != ""},
+	))
+}
+
+// SetBatchRowResearchBrief stores the preliminary research for a row along
+// with the selected candidate, if any, and moves the row to the given status.
+func SetBatchRowResearchBrief(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	status string,
+	researchBriefID string,
+	companyCandidateID string,
+) error {
+	return db.New().UpdateBatchRowResearchBrief(ctx, dbtx, db.NewUpdateBatchRowResearchBriefParams(
+		id.String(),
+		status,
+		sql.NullString{String: researchBriefID, Valid: researchBriefID != ""},
+		sql.NullString{String: companyCandidateID, Valid: companyCandidateID != ""},
+	))
+}
+
+func SetBatchRowReport(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	status string,
+	reportID uuid.UUID,
+) error {
+	return db.New().UpdateBatchRowReport(ctx, dbtx, db.NewUpdateBatchRowReportParams(
+		id.String(),
+		status,
+		sql.NullString{String: reportID.String(), Valid: true},
+	))
+}
+
+func rowsToBatchRows(rows []db.BatchRow) ([]BatchRow, error) {
+	batchRows := make([]BatchRow, len(rows))
+	for i, row := range rows {
+		result, err := rowToBatchRow(row)
+		if err != nil {
+			return nil, err
+		}
+		batchRows[i] = result
+	}
+
+	return batchRows, nil
+}
+
+func rowToBatchRow(row db.BatchRow) (BatchRow, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return BatchRow{}, err
+	}
+
+	return BatchRow{
+		ID:                 id,
+		CreatedAt:          row.CreatedAt,
+		UpdatedAt:          row.UpdatedAt,
+		BatchID:            row.BatchID,
+		RowNumber:          row.RowNumber,
+		InputName:          row.InputName,
+		InputURL:           row.InputUrl,
+		Status:             row.Status,
+		ResearchBriefID:    row.ResearchBriefID.String,
+		CompanyCandidateID: row.CompanyCandidateID.String,
+		ReportID:           row.ReportID.String,
+		Error:              row.Error.String,
+	}, nil
+}
@@ -0,0 +1,312 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// Company is the canonical record of a company researched in a workspace,
+// keyed by its normalized domain. Every candidate and report with that
+// domain links to it, however many research briefs found it.
+type Company struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	WorkspaceID uuid.UUID
+	Domain      string
+	Name        string
+	// MergedIntoID is set once the company was merged into another one as a
+	// duplicate. Its domain keeps resolving to that company.
+	MergedIntoID string
+}
+
+func (c Company) Merged() bool {
+	return c.MergedIntoID != ""
+}
+
+// FindCompany looks up a company of the workspace. Merged companies are
+// returned as they are; callers follow MergedIntoID when they need the
+// company that is still in use.
+func FindCompany(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (Company, error) {
+	row, err := db.New().QueryCompanyByIDAndWorkspaceID(ctx, dbtx, db.NewQueryCompanyByIDAndWorkspaceIDParams(
+		id.String(),
+		workspaceID.String(),
+	))
+	if err != nil {
+		return Company{}, err
+	}
+
+	return rowToCompany(row)
+}
+
+// FindCompanyByDomain looks up the company of the workspace keyed by an
+// already normalized domain, following a merge to the company it was merged
+// into.
+func FindCompanyByDomain(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	domain string,
+) (Company, error) {
+	row, err := db.New().QueryCompanyByDomain(ctx, dbtx, db.NewQueryCompanyByDomainParams(
+		workspaceID.String(),
+		domain,
+	))
+	if err != nil {
+		return Company{}, err
+	}
+
+	company, err := rowToCompany(row)
+	if err != nil {
+		return Company{}, err
+	}
+
+	return followMerge(ctx, dbtx, company)
+}
+
+type FindOrCreateCompanyData struct {
+	WorkspaceID uuid.UUID `validate:"required"`
+	// Domain must already be normalized, see services.CompanyDomain.
+	Domain string `validate:"required,max=253"`
+	Name   string `validate:"required"`
+}
+
+// FindOrCreateCompany returns the company of the workspace keyed by domain,
+// creating it with name the first time the domain is seen. A domain that
+// was merged into another company resolves to that company.
+func FindOrCreateCompany(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data FindOrCreateCompanyData,
+) (Company, error) {
+	if err := validate.Struct(data); err != nil {
+		return Company{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().UpsertCompany(ctx, dbtx, db.NewUpsertCompanyParams(
+		data.WorkspaceID.String(),
+		data.Domain,
+		data.Name,
+	))
+	if err != nil {
+		return Company{}, err
+	}
+
+	company, err := rowToCompany(row)
+	if err != nil {
+		return Company{}, err
+	}
+
+	return followMerge(ctx, dbtx, company)
+}
+
+// CompanyListing is a company with how much research was done on it.
+type CompanyListing struct {
+	Company
+	ReportCount        int64
+	ResearchBriefCount int64
+}
+
+type PaginatedCompanies struct {
+	Companies  []CompanyListing
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+// PaginateCompanies lists the companies of the workspace that were not
+// merged into another one, by name. search matches the name or domain,
+// ignoring case.
+func PaginateCompanies(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	search string,
+	page int64,
+	pageSize int64,
+) (PaginatedCompanies, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := (page - 1) * pageSize
+
+	totalCount, err := db.New().CountCompanies(ctx, dbtx, db.NewCountCompaniesParams(
+		workspaceID.String(),
+		search,
+	))
+	if err != nil {
+		return PaginatedCompanies{}, err
+	}
+
+	rows, err := db.New().QueryPaginatedCompanies(ctx, dbtx, db.NewQueryPaginatedCompaniesParams(
+		workspaceID.String(),
+		search,
+		pageSize,
+		offset,
+	))
+	if err != nil {
+		return PaginatedCompanies{}, err
+	}
+
+	companies := make([]CompanyListing, len(rows))
+	for i, row := range rows {
+		company, err := rowToCompany(db.Company{
+			ID:           row.ID,
+			CreatedAt:    row.CreatedAt,
+			UpdatedAt:    row.UpdatedAt,
+			WorkspaceID:  row.WorkspaceID,
+			Domain:       row.Domain,
+			Name:         row.Name,
+			MergedIntoID: row.MergedIntoID,
+		})
+		if err != nil {
+			return PaginatedCompanies{}, err
+		}
+
+		companies[i] = CompanyListing{
+			Company:            company,
+			ReportCount:        row.ReportCount,
+			ResearchBriefCount: row.ResearchBriefCount,
+		}
+	}
+
+	return PaginatedCompanies{
+		Companies:  companies,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (totalCount + pageSize - 1) / pageSize,
+	}, nil
+}
+
+// FindCompanyDuplicates returns the other companies of the workspace with
+// the same name, ignoring case, which are likely the same company found
+// under another domain.
+func FindCompanyDuplicates(ctx context.Context, dbtx db.DBTX, company Company) ([]Company, error) {
+	rows, err := db.New().QueryCompanyDuplicates(ctx, dbtx, db.NewQueryCompanyDuplicatesParams(
+		company.WorkspaceID.String(),
+		company.ID.String(),
+		company.Name,
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToCompanies(rows)
+}
+
+// FindMergedCompanies returns the companies that were merged into company.
+func FindMergedCompanies(ctx context.Context, dbtx db.DBTX, companyID uuid.UUID) ([]Company, error) {
+	rows, err := db.New().QueryMergedCompanies(ctx, dbtx, sql.NullString{String: companyID.String(), Valid: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToCompanies(rows)
+}
+
+// MergeCompany moves the candidates, reports and findings of source to
+// target and marks source, and every company merged into it before, as
+// merged into target. It does not check that both belong to the same
+// workspace, and should run in a transaction.
+func MergeCompany(
+	ctx context.Context,
+	dbtx db.DBTX,
+	sourceID uuid.UUID,
+	targetID uuid.UUID,
+) error {
+	queries := db.New()
+
+	if err := queries.MoveCompanyCandidatesToCompany(ctx, dbtx, db.NewMoveCompanyCandidatesToCompanyParams(
+		sourceID.String(),
+		targetID.String(),
+	)); err != nil {
+		return err
+	}
+
+	if err := queries.MoveReportsToCompany(ctx, dbtx, db.NewMoveReportsToCompanyParams(
+		sourceID.String(),
+		targetID.String(),
+	)); err != nil {
+		return err
+	}
+
+	if err := queries.MoveCompanyFindingsToCompany(ctx, dbtx, db.NewMoveCompanyFindingsToCompanyParams(
+		sourceID.String(),
+		targetID.String(),
+	)); err != nil {
+		return err
+	}
+
+	return queries.MergeCompany(ctx, dbtx, db.NewMergeCompanyParams(sourceID.String(), targetID.String()))
+}
+
+// followMerge returns the company that company was merged into, or company
+// itself. Merges always point at a company that is still in use, so one
+// step is enough.
+func followMerge(ctx context.Context, dbtx db.DBTX, company Company) (Company, error) {
+	if !company.Merged() {
+		return company, nil
+	}
+
+	row, err := db.New().QueryCompanyByID(ctx, dbtx, company.MergedIntoID)
+	if err != nil {
+		return Company{}, err
+	}
+
+	return rowToCompany(row)
+}
+
+func rowsToCompanies(rows []db.Company) ([]Company, error) {
+	companies := make([]Company, len(rows))
+	for i, row := range rows {
+		company, err := rowToCompany(row)
+		if err != nil {
+			return nil, err
+		}
+		companies[i] = company
+	}
+
+	return companies, nil
+}
+
+func rowToCompany(row db.Company) (Company, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return Company{}, err
+	}
+
+	workspaceID, err := uuid.Parse(row.WorkspaceID)
+	if err != nil {
+		return Company{}, err
+	}
+
+	return Company{
+		ID:           id,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+		WorkspaceID:  workspaceID,
+		Domain:       row.Domain,
+		Name:         row.Name,
+		MergedIntoID: row.MergedIntoID.String,
+	}, nil
+}
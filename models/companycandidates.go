@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 
 	"github.com/google/uuid"
@@ -17,9 +18,38 @@ type CompanyCandidates struct {
 	Description     string
 	Industry        string
 	Location        string
+	// CompanyID is the canonical company behind the candidate's domain. It is
+	// empty when the candidate has no usable domain.
+	CompanyID string
 }
 
+// FindCompanyCandidates looks up a company candidate found by a research
+// brief of the workspace.
 func FindCompanyCandidates(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (CompanyCandidates, error) {
+	row, err := db.New().QueryCompanyCandidatesByIDAndWorkspaceID(
+		ctx,
+		dbtx,
+		db.NewQueryCompanyCandidatesByIDAndWorkspaceIDParams(id.String(), workspaceParam(workspaceID)),
+	)
+	if err != nil {
+		return CompanyCandidates{}, err
+	}
+
+	result, err := rowToCompanyCandidates(row)
+	if err != nil {
+		return CompanyCandidates{}, err
+	}
+	return result, nil
+}
+
+// FindCompanyCandidatesByID looks up a company candidate in any workspace,
+// for background jobs.
+func FindCompanyCandidatesByID(
 	ctx context.Context,
 	dbtx db.DBTX,
 	id uuid.UUID,
@@ -65,6 +95,7 @@ type CreateCompanyCandidatesData struct {
 	Description     string
 	Industry        string
 	Location        string
+	CompanyID       string `validate:"omitempty,uuid"`
 }
 
 func CreateCompanyCandidates(
@@ -83,6 +114,7 @@ func CreateCompanyCandidates(
 		data.Description,
 		data.Industry,
 		data.Location,
+		sql.NullString{String: data.CompanyID, Valid: data.CompanyID != ""},
 	)
 	row, err := db.New().InsertCompanyCandidates(ctx, dbtx, params)
 	if err != nil {
@@ -275,5 +307,6 @@ func rowToCompanyCandidates(row db.Companycandidate) (CompanyCandidates, error)
 		Description:     row.Description,
 		Industry:        row.Industry,
 		Location:        row.Location,
+		CompanyID:       row.CompanyID.String,
 	}, nil
 }
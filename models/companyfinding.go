@@ -0,0 +1,137 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// CompanyFinding is the validated text of one report section on a company,
+// kept so later reports on the company can build on it instead of starting
+// over.
+type CompanyFinding struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	CompanyID uuid.UUID
+	// ReportID is the report the section was researched for.
+	ReportID string
+	// PriorFindingID is the finding the research was offered as prior
+	// context, if any.
+	PriorFindingID string
+	Section        string
+	Data           string
+	// AsOf is when the oldest information in Data was researched: the time
+	// of a research from scratch, carried over by every finding built on it.
+	AsOf time.Time
+}
+
+// Fresh reports whether the finding is younger than maxAge at now.
+func (f CompanyFinding) Fresh(now time.Time, maxAge time.Duration) bool {
+	return maxAge > 0 && now.Sub(f.AsOf) < maxAge
+}
+
+// FindLatestCompanyFinding returns the most recent finding of the company for
+// section.
+func FindLatestCompanyFinding(
+	ctx context.Context,
+	dbtx db.DBTX,
+	companyID uuid.UUID,
+	section string,
+) (CompanyFinding, error) {
+	row, err := db.New().QueryLatestCompanyFinding(ctx, dbtx, db.NewQueryLatestCompanyFindingParams(
+		companyID.String(),
+		section,
+	))
+	if err != nil {
+		return CompanyFinding{}, err
+	}
+
+	return rowToCompanyFinding(row)
+}
+
+// FindLatestCompanyFindings returns the most recent finding of the company
+// for each section it was researched on.
+func FindLatestCompanyFindings(
+	ctx context.Context,
+	dbtx db.DBTX,
+	companyID uuid.UUID,
+) ([]CompanyFinding, error) {
+	rows, err := db.New().QueryLatestCompanyFindings(ctx, dbtx, companyID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	findings := make([]CompanyFinding, len(rows))
+	for i, row := range rows {
+		finding, err := rowToCompanyFinding(row)
+		if err != nil {
+			return nil, err
+		}
+		findings[i] = finding
+	}
+
+	return findings, nil
+}
+
+type CreateCompanyFindingData struct {
+	CompanyID      uuid.UUID `validate:"required"`
+	ReportID       string    `validate:"omitempty,uuid"`
+	PriorFindingID string    `validate:"omitempty,uuid"`
+	Section        string    `validate:"required"`
+	Data           string    `validate:"required"`
+	AsOf           time.Time `validate:"required"`
+}
+
+func CreateCompanyFinding(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateCompanyFindingData,
+) (CompanyFinding, error) {
+	if err := validate.Struct(data); err != nil {
+		return CompanyFinding{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertCompanyFinding(ctx, dbtx, db.NewInsertCompanyFindingParams(
+		data.CompanyID.String(),
+		sql.NullString{String: data.ReportID, Valid: data.ReportID != ""},
+		sql.NullString{String: data.PriorFindingID, Valid: data.PriorFindingID != ""},
+		data.Section,
+		data.Data,
+		data.AsOf,
+	))
+	if err != nil {
+		return CompanyFinding{}, err
+	}
+
+	return rowToCompanyFinding(row)
+}
+
+func rowToCompanyFinding(row db.CompanyFinding) (CompanyFinding, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return CompanyFinding{}, err
+	}
+
+	companyID, err := uuid.Parse(row.CompanyID)
+	if err != nil {
+		return CompanyFinding{}, err
+	}
+
+	return CompanyFinding{
+		ID:             id,
+		CreatedAt:      row.CreatedAt,
+		UpdatedAt:      row.UpdatedAt,
+		CompanyID:      companyID,
+		ReportID:       row.ReportID.String,
+		PriorFindingID: row.PriorFindingID.String,
+		Section:        row.Section,
+		Data:           row.Data,
+		AsOf:           row.AsOf,
+	}, nil
+}
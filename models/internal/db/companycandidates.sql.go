@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 )
 
 const countCompanyCandidatess = `-- name: CountCompanyCandidatess :one
@@ -37,10 +38,10 @@ func (q *Queries) DeleteCompanyCandidates(ctx context.Context, db DBTX, id strin
 
 const insertCompanyCandidates = `-- name: InsertCompanyCandidates :one
 insert into
-    companycandidates (id, research_brief_id, name, domain, description, industry, location)
+    companycandidates (id, research_brief_id, name, domain, description, industry, location, company_id)
 values
-    (?, ?, ?, ?, ?, ?, ?)
-returning id, research_brief_id, name, domain, description, industry, location
+    (?, ?, ?, ?, ?, ?, ?, ?)
+returning id, research_brief_id, name, domain, description, industry, location, company_id
 `
 
 type InsertCompanyCandidatesParams struct {
@@ -51,15 +52,16 @@ type InsertCompanyCandidatesParams struct {
 	Description     string
 	Industry        string
 	Location        string
+	CompanyID       sql.NullString
 }
 
 // InsertCompanyCandidates
 //
 //	insert into
-//	    companycandidates (id, research_brief_id, name, domain, description, industry, location)
+//	    companycandidates (id, research_brief_id, name, domain, description, industry, location, company_id)
 //	values
-//	    (?, ?, ?, ?, ?, ?, ?)
-//	returning id, research_brief_id, name, domain, description, industry, location
+//	    (?, ?, ?, ?, ?, ?, ?, ?)
+//	returning id, research_brief_id, name, domain, description, industry, location, company_id
 func (q *Queries) InsertCompanyCandidates(ctx context.Context, db DBTX, arg InsertCompanyCandidatesParams) (Companycandidate, error) {
 	row := db.QueryRowContext(ctx, insertCompanyCandidates,
 		arg.ID,
@@ -69,6 +71,7 @@ func (q *Queries) InsertCompanyCandidates(ctx context.Context, db DBTX, arg Inse
 		arg.Description,
 		arg.Industry,
 		arg.Location,
+		arg.CompanyID,
 	)
 	var i Companycandidate
 	err := row.Scan(
@@ -79,17 +82,18 @@ func (q *Queries) InsertCompanyCandidates(ctx context.Context, db DBTX, arg Inse
 		&i.Description,
 		&i.Industry,
 		&i.Location,
+		&i.CompanyID,
 	)
 	return i, err
 }
 
 const queryAllCompanyCandidatess = `-- name: QueryAllCompanyCandidatess :many
-select id, research_brief_id, name, domain, description, industry, location from companycandidates
+select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates
 `
 
 // QueryAllCompanyCandidatess
 //
-//	select id, research_brief_id, name, domain, description, industry, location from companycandidates
+//	select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates
 func (q *Queries) QueryAllCompanyCandidatess(ctx context.Context, db DBTX) ([]Companycandidate, error) {
 	rows, err := db.QueryContext(ctx, queryAllCompanyCandidatess)
 	if err != nil {
@@ -107,6 +111,7 @@ func (q *Queries) QueryAllCompanyCandidatess(ctx context.Context, db DBTX) ([]Co
 			&i.Description,
 			&i.Industry,
 			&i.Location,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -122,12 +127,12 @@ func (q *Queries) QueryAllCompanyCandidatess(ctx context.Context, db DBTX) ([]Co
 }
 
 const queryCompanyCandidatesByID = `-- name: QueryCompanyCandidatesByID :one
-select id, research_brief_id, name, domain, description, industry, location from companycandidates where id=?
+select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates where id=?
 `
 
 // QueryCompanyCandidatesByID
 //
-//	select id, research_brief_id, name, domain, description, industry, location from companycandidates where id=?
+//	select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates where id=?
 func (q *Queries) QueryCompanyCandidatesByID(ctx context.Context, db DBTX, id string) (Companycandidate, error) {
 	row := db.QueryRowContext(ctx, queryCompanyCandidatesByID, id)
 	var i Companycandidate
@@ -139,17 +144,50 @@ func (q *Queries) QueryCompanyCandidatesByID(ctx context.Context, db DBTX, id st
 		&i.Description,
 		&i.Industry,
 		&i.Location,
+		&i.CompanyID,
+	)
+	return i, err
+}
+
+const queryCompanyCandidatesByIDAndWorkspaceID = `-- name: QueryCompanyCandidatesByIDAndWorkspaceID :one
+select companycandidates.id, companycandidates.research_brief_id, companycandidates.name, companycandidates.domain, companycandidates.description, companycandidates.industry, companycandidates.location, companycandidates.company_id from companycandidates
+join researchbriefs on researchbriefs.id = companycandidates.research_brief_id
+where companycandidates.id=? and researchbriefs.workspace_id=?
+`
+
+type QueryCompanyCandidatesByIDAndWorkspaceIDParams struct {
+	ID          string
+	WorkspaceID sql.NullString
+}
+
+// QueryCompanyCandidatesByIDAndWorkspaceID
+//
+//	select companycandidates.id, companycandidates.research_brief_id, companycandidates.name, companycandidates.domain, companycandidates.description, companycandidates.industry, companycandidates.location, companycandidates.company_id from companycandidates
+//	join researchbriefs on researchbriefs.id = companycandidates.research_brief_id
+//	where companycandidates.id=? and researchbriefs.workspace_id=?
+func (q *Queries) QueryCompanyCandidatesByIDAndWorkspaceID(ctx context.Context, db DBTX, arg QueryCompanyCandidatesByIDAndWorkspaceIDParams) (Companycandidate, error) {
+	row := db.QueryRowContext(ctx, queryCompanyCandidatesByIDAndWorkspaceID, arg.ID, arg.WorkspaceID)
+	var i Companycandidate
+	err := row.Scan(
+		&i.ID,
+		&i.ResearchBriefID,
+		&i.Name,
+		&i.Domain,
+		&i.Description,
+		&i.Industry,
+		&i.Location,
+		&i.CompanyID,
 	)
 	return i, err
 }
 
 const queryCompanyCandidatesByResearchBriefID = `-- name: QueryCompanyCandidatesByResearchBriefID :many
-select id, research_brief_id, name, domain, description, industry, location from companycandidates where research_brief_id=?
+select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates where research_brief_id=?
 `
 
 // QueryCompanyCandidatesByResearchBriefID
 //
-//	select id, research_brief_id, name, domain, description, industry, location from companycandidates where research_brief_id=?
+//	select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates where research_brief_id=?
 func (q *Queries) QueryCompanyCandidatesByResearchBriefID(ctx context.Context, db DBTX, researchBriefID string) ([]Companycandidate, error) {
 	rows, err := db.QueryContext(ctx, queryCompanyCandidatesByResearchBriefID, researchBriefID)
 	if err != nil {
@@ -167,6 +205,7 @@ func (q *Queries) QueryCompanyCandidatesByResearchBriefID(ctx context.Context, d
 			&i.Description,
 			&i.Industry,
 			&i.Location,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -182,12 +221,12 @@ func (q *Queries) QueryCompanyCandidatesByResearchBriefID(ctx context.Context, d
 }
 
 const queryCompanyCandidatess = `-- name: QueryCompanyCandidatess :many
-select id, research_brief_id, name, domain, description, industry, location from companycandidates
+select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates
 `
 
 // QueryCompanyCandidatess
 //
-//	select id, research_brief_id, name, domain, description, industry, location from companycandidates
+//	select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates
 func (q *Queries) QueryCompanyCandidatess(ctx context.Context, db DBTX) ([]Companycandidate, error) {
 	rows, err := db.QueryContext(ctx, queryCompanyCandidatess)
 	if err != nil {
@@ -205,6 +244,7 @@ func (q *Queries) QueryCompanyCandidatess(ctx context.Context, db DBTX) ([]Compa
 			&i.Description,
 			&i.Industry,
 			&i.Location,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -220,7 +260,7 @@ func (q *Queries) QueryCompanyCandidatess(ctx context.Context, db DBTX) ([]Compa
 }
 
 const queryPaginatedCompanyCandidatess = `-- name: QueryPaginatedCompanyCandidatess :many
-select id, research_brief_id, name, domain, description, industry, location from companycandidates 
+select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates 
 order by created_at desc 
 limit ? offset ?
 `
@@ -232,7 +272,7 @@ type QueryPaginatedCompanyCandidatessParams struct {
 
 // QueryPaginatedCompanyCandidatess
 //
-//	select id, research_brief_id, name, domain, description, industry, location from companycandidates
+//	select id, research_brief_id, name, domain, description, industry, location, company_id from companycandidates
 //	order by created_at desc
 //	limit ? offset ?
 func (q *Queries) QueryPaginatedCompanyCandidatess(ctx context.Context, db DBTX, arg QueryPaginatedCompanyCandidatessParams) ([]Companycandidate, error) {
@@ -252,6 +292,7 @@ func (q *Queries) QueryPaginatedCompanyCandidatess(ctx context.Context, db DBTX,
 			&i.Description,
 			&i.Industry,
 			&i.Location,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -270,7 +311,7 @@ const updateCompanyCandidates = `-- name: UpdateCompanyCandidates :one
 update companycandidates
     set research_brief_id=?, name=?, domain=?, description=?, industry=?, location=?
 where id = ?
-returning id, research_brief_id, name, domain, description, industry, location
+returning id, research_brief_id, name, domain, description, industry, location, company_id
 `
 
 type UpdateCompanyCandidatesParams struct {
@@ -288,7 +329,7 @@ type UpdateCompanyCandidatesParams struct {
 //	update companycandidates
 //	    set research_brief_id=?, name=?, domain=?, description=?, industry=?, location=?
 //	where id = ?
-//	returning id, research_brief_id, name, domain, description, industry, location
+//	returning id, research_brief_id, name, domain, description, industry, location, company_id
 func (q *Queries) UpdateCompanyCandidates(ctx context.Context, db DBTX, arg UpdateCompanyCandidatesParams) (Companycandidate, error) {
 	row := db.QueryRowContext(ctx, updateCompanyCandidates,
 		arg.ResearchBriefID,
@@ -308,6 +349,7 @@ func (q *Queries) UpdateCompanyCandidates(ctx context.Context, db DBTX, arg Upda
 		&i.Description,
 		&i.Industry,
 		&i.Location,
+		&i.CompanyID,
 	)
 	return i, err
 }
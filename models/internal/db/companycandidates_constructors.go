@@ -5,6 +5,8 @@
 package db
 
 import (
+	"database/sql"
+
 	"github.com/google/uuid"
 )
 
@@ -18,6 +20,7 @@ func NewInsertCompanyCandidatesParams(
 	description string,
 	industry string,
 	location string,
+	companyid sql.NullString,
 ) InsertCompanyCandidatesParams {
 	return InsertCompanyCandidatesParams{
 		ID:              uuid.New().String(),
@@ -27,6 +30,7 @@ func NewInsertCompanyCandidatesParams(
 		Description:     description,
 		Industry:        industry,
 		Location:        location,
+		CompanyID:       companyid,
 	}
 }
 
@@ -56,3 +60,13 @@ func NewQueryPaginatedCompanyCandidatessParams(limit, offset int64) QueryPaginat
 		Offset: offset,
 	}
 }
+
+func NewQueryCompanyCandidatesByIDAndWorkspaceIDParams(
+	id string,
+	workspaceid sql.NullString,
+) QueryCompanyCandidatesByIDAndWorkspaceIDParams {
+	return QueryCompanyCandidatesByIDAndWorkspaceIDParams{
+		ID:          id,
+		WorkspaceID: workspaceid,
+	}
+}
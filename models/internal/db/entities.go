@@ -16,6 +16,65 @@ type Agentguidance struct {
 	GuidanceValue   string
 }
 
+type ApiKey struct {
+	ID          string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	Prefix      string
+	KeyHash     string
+	LastUsedAt  sql.NullTime
+	RevokedAt   sql.NullTime
+	WorkspaceID sql.NullString
+	UserID      sql.NullString
+}
+
+type Batch struct {
+	ID          string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Name        string
+	UserID      sql.NullString
+	WorkspaceID sql.NullString
+}
+
+type BatchRow struct {
+	ID                 string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	BatchID            string
+	RowNumber          int64
+	InputName          string
+	InputUrl           string
+	Status             string
+	ResearchBriefID    sql.NullString
+	CompanyCandidateID sql.NullString
+	ReportID           sql.NullString
+	Error              sql.NullString
+}
+
+type Company struct {
+	ID           string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	WorkspaceID  string
+	Domain       string
+	Name         string
+	MergedIntoID sql.NullString
+}
+
+type CompanyFinding struct {
+	ID             string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	CompanyID      string
+	ReportID       sql.NullString
+	PriorFindingID sql.NullString
+	Section        string
+	Data           string
+	AsOf           time.Time
+}
+
 type Companycandidate struct {
 	ID              string
 	ResearchBriefID string
@@ -24,6 +83,17 @@ type Companycandidate struct {
 	Description     string
 	Industry        string
 	Location        string
+	CompanyID       sql.NullString
+}
+
+type Goqite struct {
+	ID       string
+	Created  string
+	Updated  string
+	Queue    string
+	Body     []byte
+	Timeout  string
+	Received int64
 }
 
 type Report struct {
@@ -45,6 +115,53 @@ type Report struct {
 	TrendAnalysisData                sql.NullString
 	FinalReport                      sql.NullString
 	CompletedAt                      sql.NullTime
+	UserID                           sql.NullString
+	WorkspaceID                      sql.NullString
+	CompanyID                        sql.NullString
+}
+
+type ReportExport struct {
+	ID          string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	ReportID    string
+	Format      string
+	Fingerprint string
+	Content     []byte
+}
+
+type ReportShareAccess struct {
+	ID                string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ReportShareLinkID string
+	Outcome           string
+	IpAddress         string
+	UserAgent         string
+}
+
+type ReportShareLink struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ReportID  string
+	Label     string
+	ExpiresAt time.Time
+	RevokedAt sql.NullTime
+}
+
+type ReportsFt struct {
+	CompanyName                 string
+	CompanyIntelligenceData     string
+	CompetitiveIntelligenceData string
+	MarketDynamicsData          string
+	TrendAnalysisData           string
+	FinalReport                 string
+}
+
+type ReportsFtsKey struct {
+	ID       int64
+	ReportID string
 }
 
 type Researchbrief struct {
@@ -60,6 +177,11 @@ type Researchbrief struct {
 	ResearchDepth        string
 	ConfidenceScore      float64
 	LastUpdated          time.Time
+	UserID               sql.NullString
+	WorkspaceID          sql.NullString
+	ResearchStatus       string
+	Query                string
+	ResearchError        string
 }
 
 type Source struct {
@@ -73,3 +195,124 @@ type Specialconsideration struct {
 	ResearchBriefID string
 	Consideration   string
 }
+
+type ToolCacheEntry struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Tool      string
+	CacheKey  string
+	Value     string
+	ExpiresAt time.Time
+}
+
+type ToolCall struct {
+	ID           string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	WorkspaceID  sql.NullString
+	ReportID     sql.NullString
+	Agent        string
+	Tool         string
+	Arguments    string
+	Status       string
+	LatencyMs    int64
+	ResponseSize int64
+	ErrorMessage string
+	Backend      string
+	Cached       bool
+}
+
+type User struct {
+	ID           string
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Email        string
+	PasswordHash string
+	LastLoginAt  sql.NullTime
+	OidcIssuer   sql.NullString
+	OidcSubject  sql.NullString
+}
+
+type Watchlist struct {
+	ID                 string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	CompanyCandidateID string
+	CompanyName        string
+	CompanyUrl         string
+	Schedule           string
+	NextRunAt          time.Time
+	LastRunAt          sql.NullTime
+	LastReportID       sql.NullString
+	WorkspaceID        sql.NullString
+}
+
+type WatchlistChange struct {
+	ID                 string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	WatchlistID        string
+	ReportID           string
+	PreviousReportID   sql.NullString
+	Status             string
+	HasMaterialChanges sql.NullBool
+	Summary            sql.NullString
+}
+
+type WebhookDelivery struct {
+	ID                string
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	WebhookEndpointID string
+	Event             string
+	Payload           string
+	Status            string
+	Attempts          int64
+	LastStatusCode    sql.NullInt64
+	LastError         sql.NullString
+	NextAttemptAt     sql.NullTime
+	DeliveredAt       sql.NullTime
+}
+
+type WebhookEndpoint struct {
+	ID          string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Url         string
+	Description string
+	Secret      string
+	Events      string
+	Active      bool
+	WorkspaceID sql.NullString
+}
+
+type Workspace struct {
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+}
+
+type WorkspaceInvitation struct {
+	ID              string
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	WorkspaceID     string
+	Email           string
+	Role            string
+	TokenHash       string
+	InvitedByUserID sql.NullString
+	ExpiresAt       time.Time
+	AcceptedAt      sql.NullTime
+	RevokedAt       sql.NullTime
+}
+
+type WorkspaceMembership struct {
+	ID          string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	WorkspaceID string
+	UserID      string
+	Role        string
+}
@@ -29,6 +29,9 @@ func NewInsertReportParams(
 	trendanalysisdata sql.NullString,
 	finalreport sql.NullString,
 	completedat sql.NullTime,
+	userid sql.NullString,
+	workspaceid sql.NullString,
+	companyid sql.NullString,
 ) InsertReportParams {
 	return InsertReportParams{
 		ID:                               uuid.New().String(),
@@ -47,6 +50,9 @@ func NewInsertReportParams(
 		TrendAnalysisData:                trendanalysisdata,
 		FinalReport:                      finalreport,
 		CompletedAt:                      completedat,
+		UserID:                           userid,
+		WorkspaceID:                      workspaceid,
+		CompanyID:                        companyid,
 	}
 }
 
@@ -88,9 +94,84 @@ func NewUpdateReportParams(
 	}
 }
 
-func NewQueryPaginatedReportsParams(limit, offset int64) QueryPaginatedReportsParams {
+func NewQueryPaginatedReportsParams(
+	workspaceid sql.NullString,
+	sort string,
+	status string,
+	search string,
+	limit, offset int64,
+) QueryPaginatedReportsParams {
 	return QueryPaginatedReportsParams{
-		Limit:  limit,
-		Offset: offset,
+		Sort:        sort,
+		WorkspaceID: workspaceid,
+		Status:      status,
+		Search:      search,
+		Limit:       limit,
+		Offset:      offset,
+	}
+}
+
+func NewQueryReportsByResearchBriefIDParams(
+	researchbriefid string,
+	workspaceid sql.NullString,
+) QueryReportsByResearchBriefIDParams {
+	return QueryReportsByResearchBriefIDParams{
+		ResearchBriefID: researchbriefid,
+		WorkspaceID:     workspaceid,
+	}
+}
+
+func NewCountReportsParams(workspaceid sql.NullString, status, search string) CountReportsParams {
+	return CountReportsParams{
+		WorkspaceID: workspaceid,
+		Status:      status,
+		Search:      search,
+	}
+}
+
+func NewQueryReportByIDAndWorkspaceIDParams(
+	id string,
+	workspaceid sql.NullString,
+) QueryReportByIDAndWorkspaceIDParams {
+	return QueryReportByIDAndWorkspaceIDParams{
+		ID:          id,
+		WorkspaceID: workspaceid,
+	}
+}
+
+func NewSearchReportsParams(
+	terms string,
+	workspaceid sql.NullString,
+	limit, offset int64,
+) SearchReportsParams {
+	return SearchReportsParams{
+		Terms:       terms,
+		WorkspaceID: workspaceid,
+		Limit:       limit,
+		Offset:      offset,
+	}
+}
+
+func NewCountSearchReportsParams(terms string, workspaceid sql.NullString) CountSearchReportsParams {
+	return CountSearchReportsParams{
+		Terms:       terms,
+		WorkspaceID: workspaceid,
+	}
+}
+
+func NewQueryReportsByCompanyIDParams(
+	companyid string,
+	workspaceid sql.NullString,
+) QueryReportsByCompanyIDParams {
+	return QueryReportsByCompanyIDParams{
+		CompanyID:   sql.NullString{String: companyid, Valid: true},
+		WorkspaceID: workspaceid,
+	}
+}
+
+func NewMoveReportsToCompanyParams(sourceid string, targetid string) MoveReportsToCompanyParams {
+	return MoveReportsToCompanyParams{
+		SourceID: sql.NullString{String: sourceid, Valid: true},
+		TargetID: sql.NullString{String: targetid, Valid: true},
 	}
 }
@@ -8,17 +8,61 @@ package db
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const countReports = `-- name: CountReports :one
 select count(*) from reports
+where workspace_id = ?1
+    and (cast(?2 as text) = ''
+        or (cast(?2 as text) = 'active' and status not in ('completed', 'failed', 'canceled'))
+        or status = cast(?2 as text))
+    and instr(lower(company_name), lower(cast(?3 as text))) > 0
 `
 
+type CountReportsParams struct {
+	WorkspaceID sql.NullString
+	Status      string
+	Search      string
+}
+
 // CountReports
 //
 //	select count(*) from reports
-func (q *Queries) CountReports(ctx context.Context, db DBTX) (int64, error) {
-	row := db.QueryRowContext(ctx, countReports)
+//	where workspace_id = ?1
+//	    and (cast(?2 as text) = ''
+//	        or (cast(?2 as text) = 'active' and status not in ('completed', 'failed', 'canceled'))
+//	        or status = cast(?2 as text))
+//	    and instr(lower(company_name), lower(cast(?3 as text))) > 0
+func (q *Queries) CountReports(ctx context.Context, db DBTX, arg CountReportsParams) (int64, error) {
+	row := db.QueryRowContext(ctx, countReports, arg.WorkspaceID, arg.Status, arg.Search)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchReports = `-- name: CountSearchReports :one
+select count(*) from reports_fts
+join reports_fts_keys on reports_fts_keys.id = reports_fts.rowid
+join reports on reports.id = reports_fts_keys.report_id,
+    (select cast(?1 as text) as terms) as params
+where reports_fts match params.terms and reports.workspace_id = ?2
+`
+
+type CountSearchReportsParams struct {
+	Terms       string
+	WorkspaceID sql.NullString
+}
+
+// CountSearchReports
+//
+//	select count(*) from reports_fts
+//	join reports_fts_keys on reports_fts_keys.id = reports_fts.rowid
+//	join reports on reports.id = reports_fts_keys.report_id,
+//	    (select cast(?1 as text) as terms) as params
+//	where reports_fts match params.terms and reports.workspace_id = ?2
+func (q *Queries) CountSearchReports(ctx context.Context, db DBTX, arg CountSearchReportsParams) (int64, error) {
+	row := db.QueryRowContext(ctx, countSearchReports, arg.Terms, arg.WorkspaceID)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -38,10 +82,10 @@ func (q *Queries) DeleteReport(ctx context.Context, db DBTX, id string) error {
 
 const insertReport = `-- name: InsertReport :one
 insert into
-    reports (id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at)
+    reports (id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id)
 values
-    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at
+    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id
 `
 
 type InsertReportParams struct {
@@ -61,15 +105,18 @@ type InsertReportParams struct {
 	TrendAnalysisData                sql.NullString
 	FinalReport                      sql.NullString
 	CompletedAt                      sql.NullTime
+	UserID                           sql.NullString
+	WorkspaceID                      sql.NullString
+	CompanyID                        sql.NullString
 }
 
 // InsertReport
 //
 //	insert into
-//	    reports (id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at)
+//	    reports (id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id)
 //	values
-//	    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-//	returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at
+//	    (?, datetime('now'), datetime('now'), ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+//	returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id
 func (q *Queries) InsertReport(ctx context.Context, db DBTX, arg InsertReportParams) (Report, error) {
 	row := db.QueryRowContext(ctx, insertReport,
 		arg.ID,
@@ -88,6 +135,9 @@ func (q *Queries) InsertReport(ctx context.Context, db DBTX, arg InsertReportPar
 		arg.TrendAnalysisData,
 		arg.FinalReport,
 		arg.CompletedAt,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.CompanyID,
 	)
 	var i Report
 	err := row.Scan(
@@ -109,19 +159,39 @@ func (q *Queries) InsertReport(ctx context.Context, db DBTX, arg InsertReportPar
 		&i.TrendAnalysisData,
 		&i.FinalReport,
 		&i.CompletedAt,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.CompanyID,
 	)
 	return i, err
 }
 
+const moveReportsToCompany = `-- name: MoveReportsToCompany :exec
+update reports set company_id=?1 where company_id=?2
+`
+
+type MoveReportsToCompanyParams struct {
+	TargetID sql.NullString
+	SourceID sql.NullString
+}
+
+// MoveReportsToCompany
+//
+//	update reports set company_id=?1 where company_id=?2
+func (q *Queries) MoveReportsToCompany(ctx context.Context, db DBTX, arg MoveReportsToCompanyParams) error {
+	_, err := db.ExecContext(ctx, moveReportsToCompany, arg.TargetID, arg.SourceID)
+	return err
+}
+
 const queryAllReports = `-- name: QueryAllReports :many
-select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports
+select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where workspace_id=?
 `
 
 // QueryAllReports
 //
-//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports
-func (q *Queries) QueryAllReports(ctx context.Context, db DBTX) ([]Report, error) {
-	rows, err := db.QueryContext(ctx, queryAllReports)
+//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where workspace_id=?
+func (q *Queries) QueryAllReports(ctx context.Context, db DBTX, workspaceID sql.NullString) ([]Report, error) {
+	rows, err := db.QueryContext(ctx, queryAllReports, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +218,9 @@ func (q *Queries) QueryAllReports(ctx context.Context, db DBTX) ([]Report, error
 			&i.TrendAnalysisData,
 			&i.FinalReport,
 			&i.CompletedAt,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -163,23 +236,52 @@ func (q *Queries) QueryAllReports(ctx context.Context, db DBTX) ([]Report, error
 }
 
 const queryPaginatedReports = `-- name: QueryPaginatedReports :many
-select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports 
-order by created_at desc 
-limit ? offset ?
+select reports.id, reports.created_at, reports.updated_at, reports.compay_candidate_id, reports.company_name, reports.status, reports.progress_percentage, reports.preliminary_research_completed, reports.company_intelligence_completed, reports.competitive_intelligence_completed, reports.market_dynamics_completed, reports.trend_analysis_completed, reports.company_intelligence_data, reports.competitive_intelligence_data, reports.market_dynamics_data, reports.trend_analysis_data, reports.final_report, reports.completed_at, reports.user_id, reports.workspace_id, reports.company_id from reports, (select cast(?1 as text) as sort) as params
+where workspace_id = ?2
+    and (cast(?3 as text) = ''
+        or (cast(?3 as text) = 'active' and status not in ('completed', 'failed', 'canceled'))
+        or status = cast(?3 as text))
+    and instr(lower(company_name), lower(cast(?4 as text))) > 0
+order by
+    case when params.sort = 'name' then lower(company_name) end asc,
+    case when params.sort = 'oldest' then created_at end asc,
+    case when params.sort = 'progress' then progress_percentage end desc,
+    created_at desc
+limit ?6 offset ?5
 `
 
 type QueryPaginatedReportsParams struct {
-	Limit  int64
-	Offset int64
+	Sort        string
+	WorkspaceID sql.NullString
+	Status      string
+	Search      string
+	Offset      int64
+	Limit       int64
 }
 
 // QueryPaginatedReports
 //
-//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports
-//	order by created_at desc
-//	limit ? offset ?
+//	select reports.id, reports.created_at, reports.updated_at, reports.compay_candidate_id, reports.company_name, reports.status, reports.progress_percentage, reports.preliminary_research_completed, reports.company_intelligence_completed, reports.competitive_intelligence_completed, reports.market_dynamics_completed, reports.trend_analysis_completed, reports.company_intelligence_data, reports.competitive_intelligence_data, reports.market_dynamics_data, reports.trend_analysis_data, reports.final_report, reports.completed_at, reports.user_id, reports.workspace_id, reports.company_id from reports, (select cast(?1 as text) as sort) as params
+//	where workspace_id = ?2
+//	    and (cast(?3 as text) = ''
+//	        or (cast(?3 as text) = 'active' and status not in ('completed', 'failed', 'canceled'))
+//	        or status = cast(?3 as text))
+//	    and instr(lower(company_name), lower(cast(?4 as text))) > 0
+//	order by
+//	    case when params.sort = 'name' then lower(company_name) end asc,
+//	    case when params.sort = 'oldest' then created_at end asc,
+//	    case when params.sort = 'progress' then progress_percentage end desc,
+//	    created_at desc
+//	limit ?6 offset ?5
 func (q *Queries) QueryPaginatedReports(ctx context.Context, db DBTX, arg QueryPaginatedReportsParams) ([]Report, error) {
-	rows, err := db.QueryContext(ctx, queryPaginatedReports, arg.Limit, arg.Offset)
+	rows, err := db.QueryContext(ctx, queryPaginatedReports,
+		arg.Sort,
+		arg.WorkspaceID,
+		arg.Status,
+		arg.Search,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -206,6 +308,9 @@ func (q *Queries) QueryPaginatedReports(ctx context.Context, db DBTX, arg QueryP
 			&i.TrendAnalysisData,
 			&i.FinalReport,
 			&i.CompletedAt,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.CompanyID,
 		); err != nil {
 			return nil, err
 		}
@@ -221,12 +326,12 @@ func (q *Queries) QueryPaginatedReports(ctx context.Context, db DBTX, arg QueryP
 }
 
 const queryReportByID = `-- name: QueryReportByID :one
-select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports where id=?
+select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where id=?
 `
 
 // QueryReportByID
 //
-//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports where id=?
+//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where id=?
 func (q *Queries) QueryReportByID(ctx context.Context, db DBTX, id string) (Report, error) {
 	row := db.QueryRowContext(ctx, queryReportByID, id)
 	var i Report
@@ -249,17 +354,61 @@ func (q *Queries) QueryReportByID(ctx context.Context, db DBTX, id string) (Repo
 		&i.TrendAnalysisData,
 		&i.FinalReport,
 		&i.CompletedAt,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.CompanyID,
+	)
+	return i, err
+}
+
+const queryReportByIDAndWorkspaceID = `-- name: QueryReportByIDAndWorkspaceID :one
+select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where id=? and workspace_id=?
+`
+
+type QueryReportByIDAndWorkspaceIDParams struct {
+	ID          string
+	WorkspaceID sql.NullString
+}
+
+// QueryReportByIDAndWorkspaceID
+//
+//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where id=? and workspace_id=?
+func (q *Queries) QueryReportByIDAndWorkspaceID(ctx context.Context, db DBTX, arg QueryReportByIDAndWorkspaceIDParams) (Report, error) {
+	row := db.QueryRowContext(ctx, queryReportByIDAndWorkspaceID, arg.ID, arg.WorkspaceID)
+	var i Report
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.CompayCandidateID,
+		&i.CompanyName,
+		&i.Status,
+		&i.ProgressPercentage,
+		&i.PreliminaryResearchCompleted,
+		&i.CompanyIntelligenceCompleted,
+		&i.CompetitiveIntelligenceCompleted,
+		&i.MarketDynamicsCompleted,
+		&i.TrendAnalysisCompleted,
+		&i.CompanyIntelligenceData,
+		&i.CompetitiveIntelligenceData,
+		&i.MarketDynamicsData,
+		&i.TrendAnalysisData,
+		&i.FinalReport,
+		&i.CompletedAt,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.CompanyID,
 	)
 	return i, err
 }
 
 const queryReports = `-- name: QueryReports :many
-select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports
+select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports
 `
 
 // QueryReports
 //
-//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at from reports
+//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports
 func (q *Queries) QueryReports(ctx context.Context, db DBTX) ([]Report, error) {
 	rows, err := db.QueryContext(ctx, queryReports)
 	if err != nil {
@@ -288,6 +437,209 @@ func (q *Queries) QueryReports(ctx context.Context, db DBTX) ([]Report, error) {
 			&i.TrendAnalysisData,
 			&i.FinalReport,
 			&i.CompletedAt,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.CompanyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryReportsByCompanyID = `-- name: QueryReportsByCompanyID :many
+select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where company_id=? and workspace_id=? order by created_at asc
+`
+
+type QueryReportsByCompanyIDParams struct {
+	CompanyID   sql.NullString
+	WorkspaceID sql.NullString
+}
+
+// QueryReportsByCompanyID
+//
+//	select id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id from reports where company_id=? and workspace_id=? order by created_at asc
+func (q *Queries) QueryReportsByCompanyID(ctx context.Context, db DBTX, arg QueryReportsByCompanyIDParams) ([]Report, error) {
+	rows, err := db.QueryContext(ctx, queryReportsByCompanyID, arg.CompanyID, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Report
+	for rows.Next() {
+		var i Report
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompayCandidateID,
+			&i.CompanyName,
+			&i.Status,
+			&i.ProgressPercentage,
+			&i.PreliminaryResearchCompleted,
+			&i.CompanyIntelligenceCompleted,
+			&i.CompetitiveIntelligenceCompleted,
+			&i.MarketDynamicsCompleted,
+			&i.TrendAnalysisCompleted,
+			&i.CompanyIntelligenceData,
+			&i.CompetitiveIntelligenceData,
+			&i.MarketDynamicsData,
+			&i.TrendAnalysisData,
+			&i.FinalReport,
+			&i.CompletedAt,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.CompanyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryReportsByResearchBriefID = `-- name: QueryReportsByResearchBriefID :many
+select reports.id, reports.created_at, reports.updated_at, reports.compay_candidate_id, reports.company_name, reports.status, reports.progress_percentage, reports.preliminary_research_completed, reports.company_intelligence_completed, reports.competitive_intelligence_completed, reports.market_dynamics_completed, reports.trend_analysis_completed, reports.company_intelligence_data, reports.competitive_intelligence_data, reports.market_dynamics_data, reports.trend_analysis_data, reports.final_report, reports.completed_at, reports.user_id, reports.workspace_id, reports.company_id from reports
+join companycandidates on companycandidates.id = reports.compay_candidate_id
+where companycandidates.research_brief_id=? and reports.workspace_id=?
+order by reports.created_at desc
+`
+
+type QueryReportsByResearchBriefIDParams struct {
+	ResearchBriefID string
+	WorkspaceID     sql.NullString
+}
+
+// QueryReportsByResearchBriefID
+//
+//	select reports.id, reports.created_at, reports.updated_at, reports.compay_candidate_id, reports.company_name, reports.status, reports.progress_percentage, reports.preliminary_research_completed, reports.company_intelligence_completed, reports.competitive_intelligence_completed, reports.market_dynamics_completed, reports.trend_analysis_completed, reports.company_intelligence_data, reports.competitive_intelligence_data, reports.market_dynamics_data, reports.trend_analysis_data, reports.final_report, reports.completed_at, reports.user_id, reports.workspace_id, reports.company_id from reports
+//	join companycandidates on companycandidates.id = reports.compay_candidate_id
+//	where companycandidates.research_brief_id=? and reports.workspace_id=?
+//	order by reports.created_at desc
+func (q *Queries) QueryReportsByResearchBriefID(ctx context.Context, db DBTX, arg QueryReportsByResearchBriefIDParams) ([]Report, error) {
+	rows, err := db.QueryContext(ctx, queryReportsByResearchBriefID, arg.ResearchBriefID, arg.WorkspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Report
+	for rows.Next() {
+		var i Report
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.CompayCandidateID,
+			&i.CompanyName,
+			&i.Status,
+			&i.ProgressPercentage,
+			&i.PreliminaryResearchCompleted,
+			&i.CompanyIntelligenceCompleted,
+			&i.CompetitiveIntelligenceCompleted,
+			&i.MarketDynamicsCompleted,
+			&i.TrendAnalysisCompleted,
+			&i.CompanyIntelligenceData,
+			&i.CompetitiveIntelligenceData,
+			&i.MarketDynamicsData,
+			&i.TrendAnalysisData,
+			&i.FinalReport,
+			&i.CompletedAt,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.CompanyID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchReports = `-- name: SearchReports :many
+select
+    reports.id,
+    reports.company_name,
+    reports.status,
+    reports.created_at,
+    cast(snippet(reports_fts, -1, char(2), char(3), '...', 16) as text) as snippet
+from reports_fts
+join reports_fts_keys on reports_fts_keys.id = reports_fts.rowid
+join reports on reports.id = reports_fts_keys.report_id,
+    (select cast(?1 as text) as terms) as params
+where reports_fts match params.terms and reports.workspace_id = ?2
+order by bm25(reports_fts, 10, 1, 1, 1, 1, 1)
+limit ?4 offset ?3
+`
+
+type SearchReportsParams struct {
+	Terms       string
+	WorkspaceID sql.NullString
+	Offset      int64
+	Limit       int64
+}
+
+type SearchReportsRow struct {
+	ID          string
+	CompanyName string
+	Status      string
+	CreatedAt   time.Time
+	Snippet     string
+}
+
+// SearchReports
+//
+//	select
+//	    reports.id,
+//	    reports.company_name,
+//	    reports.status,
+//	    reports.created_at,
+//	    cast(snippet(reports_fts, -1, char(2), char(3), '...', 16) as text) as snippet
+//	from reports_fts
+//	join reports_fts_keys on reports_fts_keys.id = reports_fts.rowid
+//	join reports on reports.id = reports_fts_keys.report_id,
+//	    (select cast(?1 as text) as terms) as params
+//	where reports_fts match params.terms and reports.workspace_id = ?2
+//	order by bm25(reports_fts, 10, 1, 1, 1, 1, 1)
+//	limit ?4 offset ?3
+func (q *Queries) SearchReports(ctx context.Context, db DBTX, arg SearchReportsParams) ([]SearchReportsRow, error) {
+	rows, err := db.QueryContext(ctx, searchReports,
+		arg.Terms,
+		arg.WorkspaceID,
+		arg.Offset,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchReportsRow
+	for rows.Next() {
+		var i SearchReportsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CompanyName,
+			&i.Status,
+			&i.CreatedAt,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -361,6 +713,7 @@ SET final_report = ?,
     completed_at = datetime('now'),
     updated_at = datetime('now')
 WHERE id = ?
+    AND status <> 'canceled'
 `
 
 type UpdateFinalReportParams struct {
@@ -376,6 +729,7 @@ type UpdateFinalReportParams struct {
 //	    completed_at = datetime('now'),
 //	    updated_at = datetime('now')
 //	WHERE id = ?
+//	    AND status <> 'canceled'
 func (q *Queries) UpdateFinalReport(ctx context.Context, db DBTX, arg UpdateFinalReportParams) error {
 	_, err := db.ExecContext(ctx, updateFinalReport, arg.FinalReport, arg.ID)
 	return err
@@ -411,7 +765,7 @@ const updateReport = `-- name: UpdateReport :one
 update reports
     set updated_at=datetime('now'), compay_candidate_id=?, company_name=?, status=?, progress_percentage=?, preliminary_research_completed=?, company_intelligence_completed=?, competitive_intelligence_completed=?, market_dynamics_completed=?, trend_analysis_completed=?, company_intelligence_data=?, competitive_intelligence_data=?, market_dynamics_data=?, trend_analysis_data=?, final_report=?, completed_at=?
 where id = ?
-returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at
+returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id
 `
 
 type UpdateReportParams struct {
@@ -438,7 +792,7 @@ type UpdateReportParams struct {
 //	update reports
 //	    set updated_at=datetime('now'), compay_candidate_id=?, company_name=?, status=?, progress_percentage=?, preliminary_research_completed=?, company_intelligence_completed=?, competitive_intelligence_completed=?, market_dynamics_completed=?, trend_analysis_completed=?, company_intelligence_data=?, competitive_intelligence_data=?, market_dynamics_data=?, trend_analysis_data=?, final_report=?, completed_at=?
 //	where id = ?
-//	returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at
+//	returning id, created_at, updated_at, compay_candidate_id, company_name, status, progress_percentage, preliminary_research_completed, company_intelligence_completed, competitive_intelligence_completed, market_dynamics_completed, trend_analysis_completed, company_intelligence_data, competitive_intelligence_data, market_dynamics_data, trend_analysis_data, final_report, completed_at, user_id, workspace_id, company_id
 func (q *Queries) UpdateReport(ctx context.Context, db DBTX, arg UpdateReportParams) (Report, error) {
 	row := db.QueryRowContext(ctx, updateReport,
 		arg.CompayCandidateID,
@@ -478,6 +832,9 @@ func (q *Queries) UpdateReport(ctx context.Context, db DBTX, arg UpdateReportPar
 		&i.TrendAnalysisData,
 		&i.FinalReport,
 		&i.CompletedAt,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.CompanyID,
 	)
 	return i, err
 }
@@ -488,6 +845,7 @@ SET progress_percentage = ?,
     status = ?,
     updated_at = datetime('now')
 WHERE id = ?
+    AND status NOT IN ('failed', 'canceled')
 `
 
 type UpdateReportProgressParams struct {
@@ -503,11 +861,87 @@ type UpdateReportProgressParams struct {
 //	    status = ?,
 //	    updated_at = datetime('now')
 //	WHERE id = ?
+//	    AND status NOT IN ('failed', 'canceled')
 func (q *Queries) UpdateReportProgress(ctx context.Context, db DBTX, arg UpdateReportProgressParams) error {
 	_, err := db.ExecContext(ctx, updateReportProgress, arg.ProgressPercentage, arg.Status, arg.ID)
 	return err
 }
 
+const updateReportToCanceled = `-- name: UpdateReportToCanceled :execrows
+UPDATE reports
+SET status = 'canceled',
+    updated_at = datetime('now')
+WHERE id = ?
+    AND status NOT IN ('completed', 'failed', 'canceled')
+    AND (final_report IS NULL OR final_report = '')
+`
+
+// UpdateReportToCanceled
+//
+//	UPDATE reports
+//	SET status = 'canceled',
+//	    updated_at = datetime('now')
+//	WHERE id = ?
+//	    AND status NOT IN ('completed', 'failed', 'canceled')
+//	    AND (final_report IS NULL OR final_report = '')
+func (q *Queries) UpdateReportToCanceled(ctx context.Context, db DBTX, id string) (int64, error) {
+	result, err := db.ExecContext(ctx, updateReportToCanceled, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateReportToFailed = `-- name: UpdateReportToFailed :execrows
+UPDATE reports
+SET status = 'failed',
+    updated_at = datetime('now')
+WHERE id = ?
+    AND status NOT IN ('failed', 'canceled')
+    AND (final_report IS NULL OR final_report = '')
+`
+
+// UpdateReportToFailed
+//
+//	UPDATE reports
+//	SET status = 'failed',
+//	    updated_at = datetime('now')
+//	WHERE id = ?
+//	    AND status NOT IN ('failed', 'canceled')
+//	    AND (final_report IS NULL OR final_report = '')
+func (q *Queries) UpdateReportToFailed(ctx context.Context, db DBTX, id string) (int64, error) {
+	result, err := db.ExecContext(ctx, updateReportToFailed, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateReportToGenerating = `-- name: UpdateReportToGenerating :execrows
+UPDATE reports
+SET status = 'generating',
+    updated_at = datetime('now')
+WHERE id = ?
+    AND status NOT IN ('generating', 'failed', 'canceled')
+    AND (final_report IS NULL OR final_report = '')
+`
+
+// UpdateReportToGenerating
+//
+//	UPDATE reports
+//	SET status = 'generating',
+//	    updated_at = datetime('now')
+//	WHERE id = ?
+//	    AND status NOT IN ('generating', 'failed', 'canceled')
+//	    AND (final_report IS NULL OR final_report = '')
+func (q *Queries) UpdateReportToGenerating(ctx context.Context, db DBTX, id string) (int64, error) {
+	result, err := db.ExecContext(ctx, updateReportToGenerating, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateTrendAnalysis = `-- name: UpdateTrendAnalysis :exec
 UPDATE reports
 SET trend_analysis_data = ?,
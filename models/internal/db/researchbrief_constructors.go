@@ -5,6 +5,7 @@
 package db
 
 import (
+	"database/sql"
 	"github.com/google/uuid"
 	"time"
 )
@@ -24,6 +25,10 @@ func NewInsertResearchBriefParams(
 	researchdepth string,
 	confidencescore float64,
 	lastupdated time.Time,
+	userid sql.NullString,
+	workspaceid sql.NullString,
+	researchstatus string,
+	query string,
 ) InsertResearchBriefParams {
 	return InsertResearchBriefParams{
 		ID:                   uuid.New().String(),
@@ -38,6 +43,10 @@ func NewInsertResearchBriefParams(
 		ResearchDepth:        researchdepth,
 		ConfidenceScore:      confidencescore,
 		LastUpdated:          lastupdated,
+		UserID:               userid,
+		WorkspaceID:          workspaceid,
+		ResearchStatus:       researchstatus,
+		Query:                query,
 	}
 }
 
@@ -71,9 +80,63 @@ func NewUpdateResearchBriefParams(
 	}
 }
 
-func NewQueryPaginatedResearchBriefsParams(limit, offset int64) QueryPaginatedResearchBriefsParams {
+func NewQueryPaginatedResearchBriefsParams(
+	workspaceid sql.NullString,
+	sort string,
+	identificationstatus string,
+	search string,
+	limit, offset int64,
+) QueryPaginatedResearchBriefsParams {
 	return QueryPaginatedResearchBriefsParams{
-		Limit:  limit,
-		Offset: offset,
+		Sort:                 sort,
+		WorkspaceID:          workspaceid,
+		IdentificationStatus: identificationstatus,
+		Search:               search,
+		Limit:                limit,
+		Offset:               offset,
+	}
+}
+
+func NewCountResearchBriefsParams(
+	workspaceid sql.NullString,
+	identificationstatus string,
+	search string,
+) CountResearchBriefsParams {
+	return CountResearchBriefsParams{
+		WorkspaceID:          workspaceid,
+		IdentificationStatus: identificationstatus,
+		Search:               search,
+	}
+}
+
+func NewQueryResearchBriefByIDAndWorkspaceIDParams(
+	id string,
+	workspaceid sql.NullString,
+) QueryResearchBriefByIDAndWorkspaceIDParams {
+	return QueryResearchBriefByIDAndWorkspaceIDParams{
+		ID:          id,
+		WorkspaceID: workspaceid,
+	}
+}
+
+func NewQueryResearchBriefsByCompanyIDParams(
+	workspaceid sql.NullString,
+	companyid string,
+) QueryResearchBriefsByCompanyIDParams {
+	return QueryResearchBriefsByCompanyIDParams{
+		WorkspaceID: workspaceid,
+		CompanyID:   sql.NullString{String: companyid, Valid: true},
+	}
+}
+
+func NewUpdateResearchBriefResearchStatusParams(
+	id string,
+	researchstatus string,
+	researcherror string,
+) UpdateResearchBriefResearchStatusParams {
+	return UpdateResearchBriefResearchStatusParams{
+		ID:             id,
+		ResearchStatus: researchstatus,
+		ResearchError:  researcherror,
 	}
 }
@@ -7,18 +7,33 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 )
 
 const countResearchBriefs = `-- name: CountResearchBriefs :one
 select count(*) from researchbriefs
+where workspace_id = ?1
+    and (cast(?2 as text) = ''
+        or lower(identification_status) = lower(cast(?2 as text)))
+    and instr(lower(company_name), lower(cast(?3 as text))) > 0
 `
 
+type CountResearchBriefsParams struct {
+	WorkspaceID          sql.NullString
+	IdentificationStatus string
+	Search               string
+}
+
 // CountResearchBriefs
 //
 //	select count(*) from researchbriefs
-func (q *Queries) CountResearchBriefs(ctx context.Context, db DBTX) (int64, error) {
-	row := db.QueryRowContext(ctx, countResearchBriefs)
+//	where workspace_id = ?1
+//	    and (cast(?2 as text) = ''
+//	        or lower(identification_status) = lower(cast(?2 as text)))
+//	    and instr(lower(company_name), lower(cast(?3 as text))) > 0
+func (q *Queries) CountResearchBriefs(ctx context.Context, db DBTX, arg CountResearchBriefsParams) (int64, error) {
+	row := db.QueryRowContext(ctx, countResearchBriefs, arg.WorkspaceID, arg.IdentificationStatus, arg.Search)
 	var count int64
 	err := row.Scan(&count)
 	return count, err
@@ -38,10 +53,10 @@ func (q *Queries) DeleteResearchBrief(ctx context.Context, db DBTX, id string) e
 
 const insertResearchBrief = `-- name: InsertResearchBrief :one
 insert into
-    researchbriefs (id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated)
+    researchbriefs (id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, query)
 values
-    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated
+    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error
 `
 
 type InsertResearchBriefParams struct {
@@ -57,15 +72,19 @@ type InsertResearchBriefParams struct {
 	ResearchDepth        string
 	ConfidenceScore      float64
 	LastUpdated          time.Time
+	UserID               sql.NullString
+	WorkspaceID          sql.NullString
+	ResearchStatus       string
+	Query                string
 }
 
 // InsertResearchBrief
 //
 //	insert into
-//	    researchbriefs (id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated)
+//	    researchbriefs (id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, query)
 //	values
-//	    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-//	returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated
+//	    (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+//	returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error
 func (q *Queries) InsertResearchBrief(ctx context.Context, db DBTX, arg InsertResearchBriefParams) (Researchbrief, error) {
 	row := db.QueryRowContext(ctx, insertResearchBrief,
 		arg.ID,
@@ -80,6 +99,10 @@ func (q *Queries) InsertResearchBrief(ctx context.Context, db DBTX, arg InsertRe
 		arg.ResearchDepth,
 		arg.ConfidenceScore,
 		arg.LastUpdated,
+		arg.UserID,
+		arg.WorkspaceID,
+		arg.ResearchStatus,
+		arg.Query,
 	)
 	var i Researchbrief
 	err := row.Scan(
@@ -95,19 +118,24 @@ func (q *Queries) InsertResearchBrief(ctx context.Context, db DBTX, arg InsertRe
 		&i.ResearchDepth,
 		&i.ConfidenceScore,
 		&i.LastUpdated,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.ResearchStatus,
+		&i.Query,
+		&i.ResearchError,
 	)
 	return i, err
 }
 
 const queryAllResearchBriefs = `-- name: QueryAllResearchBriefs :many
-select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs
+select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs where workspace_id=?
 `
 
 // QueryAllResearchBriefs
 //
-//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs
-func (q *Queries) QueryAllResearchBriefs(ctx context.Context, db DBTX) ([]Researchbrief, error) {
-	rows, err := db.QueryContext(ctx, queryAllResearchBriefs)
+//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs where workspace_id=?
+func (q *Queries) QueryAllResearchBriefs(ctx context.Context, db DBTX, workspaceID sql.NullString) ([]Researchbrief, error) {
+	rows, err := db.QueryContext(ctx, queryAllResearchBriefs, workspaceID)
 	if err != nil {
 		return nil, err
 	}
@@ -128,6 +156,11 @@ func (q *Queries) QueryAllResearchBriefs(ctx context.Context, db DBTX) ([]Resear
 			&i.ResearchDepth,
 			&i.ConfidenceScore,
 			&i.LastUpdated,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.ResearchStatus,
+			&i.Query,
+			&i.ResearchError,
 		); err != nil {
 			return nil, err
 		}
@@ -143,23 +176,50 @@ func (q *Queries) QueryAllResearchBriefs(ctx context.Context, db DBTX) ([]Resear
 }
 
 const queryPaginatedResearchBriefs = `-- name: QueryPaginatedResearchBriefs :many
-select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs 
-order by created_at desc 
-limit ? offset ?
+select researchbriefs.id, researchbriefs.identification_status, researchbriefs.company_name, researchbriefs.official_domain, researchbriefs.headquarters, researchbriefs.industry, researchbriefs.company_type, researchbriefs.status, researchbriefs.geographic_scope, researchbriefs.research_depth, researchbriefs.confidence_score, researchbriefs.last_updated, researchbriefs.user_id, researchbriefs.workspace_id, researchbriefs.research_status, researchbriefs."query", researchbriefs.research_error from researchbriefs, (select cast(?1 as text) as sort) as params
+where workspace_id = ?2
+    and (cast(?3 as text) = ''
+        or lower(identification_status) = lower(cast(?3 as text)))
+    and instr(lower(company_name), lower(cast(?4 as text))) > 0
+order by
+    case when params.sort = 'name' then lower(company_name) end asc,
+    case when params.sort = 'oldest' then last_updated end asc,
+    case when params.sort = 'confidence' then confidence_score end desc,
+    last_updated desc
+limit ?6 offset ?5
 `
 
 type QueryPaginatedResearchBriefsParams struct {
-	Limit  int64
-	Offset int64
+	Sort                 string
+	WorkspaceID          sql.NullString
+	IdentificationStatus string
+	Search               string
+	Offset               int64
+	Limit                int64
 }
 
 // QueryPaginatedResearchBriefs
 //
-//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs
-//	order by created_at desc
-//	limit ? offset ?
+//	select researchbriefs.id, researchbriefs.identification_status, researchbriefs.company_name, researchbriefs.official_domain, researchbriefs.headquarters, researchbriefs.industry, researchbriefs.company_type, researchbriefs.status, researchbriefs.geographic_scope, researchbriefs.research_depth, researchbriefs.confidence_score, researchbriefs.last_updated, researchbriefs.user_id, researchbriefs.workspace_id, researchbriefs.research_status, researchbriefs."query", researchbriefs.research_error from researchbriefs, (select cast(?1 as text) as sort) as params
+//	where workspace_id = ?2
+//	    and (cast(?3 as text) = ''
+//	        or lower(identification_status) = lower(cast(?3 as text)))
+//	    and instr(lower(company_name), lower(cast(?4 as text))) > 0
+//	order by
+//	    case when params.sort = 'name' then lower(company_name) end asc,
+//	    case when params.sort = 'oldest' then last_updated end asc,
+//	    case when params.sort = 'confidence' then confidence_score end desc,
+//	    last_updated desc
+//	limit ?6 offset ?5
 func (q *Queries) QueryPaginatedResearchBriefs(ctx context.Context, db DBTX, arg QueryPaginatedResearchBriefsParams) ([]Researchbrief, error) {
-	rows, err := db.QueryContext(ctx, queryPaginatedResearchBriefs, arg.Limit, arg.Offset)
+	rows, err := db.QueryContext(ctx, queryPaginatedResearchBriefs,
+		arg.Sort,
+		arg.WorkspaceID,
+		arg.IdentificationStatus,
+		arg.Search,
+		arg.Offset,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -180,6 +240,11 @@ func (q *Queries) QueryPaginatedResearchBriefs(ctx context.Context, db DBTX, arg
 			&i.ResearchDepth,
 			&i.ConfidenceScore,
 			&i.LastUpdated,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.ResearchStatus,
+			&i.Query,
+			&i.ResearchError,
 		); err != nil {
 			return nil, err
 		}
@@ -195,12 +260,12 @@ func (q *Queries) QueryPaginatedResearchBriefs(ctx context.Context, db DBTX, arg
 }
 
 const queryResearchBriefByID = `-- name: QueryResearchBriefByID :one
-select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs where id=?
+select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs where id=?
 `
 
 // QueryResearchBriefByID
 //
-//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs where id=?
+//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs where id=?
 func (q *Queries) QueryResearchBriefByID(ctx context.Context, db DBTX, id string) (Researchbrief, error) {
 	row := db.QueryRowContext(ctx, queryResearchBriefByID, id)
 	var i Researchbrief
@@ -217,17 +282,93 @@ func (q *Queries) QueryResearchBriefByID(ctx context.Context, db DBTX, id string
 		&i.ResearchDepth,
 		&i.ConfidenceScore,
 		&i.LastUpdated,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.ResearchStatus,
+		&i.Query,
+		&i.ResearchError,
 	)
 	return i, err
 }
 
+const queryResearchBriefByIDAndWorkspaceID = `-- name: QueryResearchBriefByIDAndWorkspaceID :one
+select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs where id=? and workspace_id=?
+`
+
+type QueryResearchBriefByIDAndWorkspaceIDParams struct {
+	ID          string
+	WorkspaceID sql.NullString
+}
+
+// QueryResearchBriefByIDAndWorkspaceID
+//
+//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs where id=? and workspace_id=?
+func (q *Queries) QueryResearchBriefByIDAndWorkspaceID(ctx context.Context, db DBTX, arg QueryResearchBriefByIDAndWorkspaceIDParams) (Researchbrief, error) {
+	row := db.QueryRowContext(ctx, queryResearchBriefByIDAndWorkspaceID, arg.ID, arg.WorkspaceID)
+	var i Researchbrief
+	err := row.Scan(
+		&i.ID,
+		&i.IdentificationStatus,
+		&i.CompanyName,
+		&i.OfficialDomain,
+		&i.Headquarters,
+		&i.Industry,
+		&i.CompanyType,
+		&i.Status,
+		&i.GeographicScope,
+		&i.ResearchDepth,
+		&i.ConfidenceScore,
+		&i.LastUpdated,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.ResearchStatus,
+		&i.Query,
+		&i.ResearchError,
+	)
+	return i, err
+}
+
+const queryResearchBriefIdentificationStatuses = `-- name: QueryResearchBriefIdentificationStatuses :many
+select distinct identification_status from researchbriefs
+where workspace_id=?
+order by identification_status collate nocase
+`
+
+// QueryResearchBriefIdentificationStatuses
+//
+//	select distinct identification_status from researchbriefs
+//	where workspace_id=?
+//	order by identification_status collate nocase
+func (q *Queries) QueryResearchBriefIdentificationStatuses(ctx context.Context, db DBTX, workspaceID sql.NullString) ([]string, error) {
+	rows, err := db.QueryContext(ctx, queryResearchBriefIdentificationStatuses, workspaceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var identification_status string
+		if err := rows.Scan(&identification_status); err != nil {
+			return nil, err
+		}
+		items = append(items, identification_status)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const queryResearchBriefs = `-- name: QueryResearchBriefs :many
-select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs
+select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs
 `
 
 // QueryResearchBriefs
 //
-//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated from researchbriefs
+//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs
 func (q *Queries) QueryResearchBriefs(ctx context.Context, db DBTX) ([]Researchbrief, error) {
 	rows, err := db.QueryContext(ctx, queryResearchBriefs)
 	if err != nil {
@@ -250,6 +391,72 @@ func (q *Queries) QueryResearchBriefs(ctx context.Context, db DBTX) ([]Researchb
 			&i.ResearchDepth,
 			&i.ConfidenceScore,
 			&i.LastUpdated,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.ResearchStatus,
+			&i.Query,
+			&i.ResearchError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const queryResearchBriefsByCompanyID = `-- name: QueryResearchBriefsByCompanyID :many
+select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs
+where workspace_id = ?1 and id in (
+    select research_brief_id from companycandidates where company_id = ?2
+)
+order by last_updated desc
+`
+
+type QueryResearchBriefsByCompanyIDParams struct {
+	WorkspaceID sql.NullString
+	CompanyID   sql.NullString
+}
+
+// QueryResearchBriefsByCompanyID
+//
+//	select id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error from researchbriefs
+//	where workspace_id = ?1 and id in (
+//	    select research_brief_id from companycandidates where company_id = ?2
+//	)
+//	order by last_updated desc
+func (q *Queries) QueryResearchBriefsByCompanyID(ctx context.Context, db DBTX, arg QueryResearchBriefsByCompanyIDParams) ([]Researchbrief, error) {
+	rows, err := db.QueryContext(ctx, queryResearchBriefsByCompanyID, arg.WorkspaceID, arg.CompanyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Researchbrief
+	for rows.Next() {
+		var i Researchbrief
+		if err := rows.Scan(
+			&i.ID,
+			&i.IdentificationStatus,
+			&i.CompanyName,
+			&i.OfficialDomain,
+			&i.Headquarters,
+			&i.Industry,
+			&i.CompanyType,
+			&i.Status,
+			&i.GeographicScope,
+			&i.ResearchDepth,
+			&i.ConfidenceScore,
+			&i.LastUpdated,
+			&i.UserID,
+			&i.WorkspaceID,
+			&i.ResearchStatus,
+			&i.Query,
+			&i.ResearchError,
 		); err != nil {
 			return nil, err
 		}
@@ -268,7 +475,7 @@ const updateResearchBrief = `-- name: UpdateResearchBrief :one
 update researchbriefs
     set identification_status=?, company_name=?, official_domain=?, headquarters=?, industry=?, company_type=?, status=?, geographic_scope=?, research_depth=?, confidence_score=?, last_updated=?
 where id = ?
-returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated
+returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error
 `
 
 type UpdateResearchBriefParams struct {
@@ -291,7 +498,7 @@ type UpdateResearchBriefParams struct {
 //	update researchbriefs
 //	    set identification_status=?, company_name=?, official_domain=?, headquarters=?, industry=?, company_type=?, status=?, geographic_scope=?, research_depth=?, confidence_score=?, last_updated=?
 //	where id = ?
-//	returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated
+//	returning id, identification_status, company_name, official_domain, headquarters, industry, company_type, status, geographic_scope, research_depth, confidence_score, last_updated, user_id, workspace_id, research_status, "query", research_error
 func (q *Queries) UpdateResearchBrief(ctx context.Context, db DBTX, arg UpdateResearchBriefParams) (Researchbrief, error) {
 	row := db.QueryRowContext(ctx, updateResearchBrief,
 		arg.IdentificationStatus,
@@ -321,6 +528,33 @@ func (q *Queries) UpdateResearchBrief(ctx context.Context, db DBTX, arg UpdateRe
 		&i.ResearchDepth,
 		&i.ConfidenceScore,
 		&i.LastUpdated,
+		&i.UserID,
+		&i.WorkspaceID,
+		&i.ResearchStatus,
+		&i.Query,
+		&i.ResearchError,
 	)
 	return i, err
 }
+
+const updateResearchBriefResearchStatus = `-- name: UpdateResearchBriefResearchStatus :exec
+update researchbriefs
+    set research_status=?, research_error=?
+where id = ?
+`
+
+type UpdateResearchBriefResearchStatusParams struct {
+	ResearchStatus string
+	ResearchError  string
+	ID             string
+}
+
+// UpdateResearchBriefResearchStatus
+//
+//	update researchbriefs
+//	    set research_status=?, research_error=?
+//	where id = ?
+func (q *Queries) UpdateResearchBriefResearchStatus(ctx context.Context, db DBTX, arg UpdateResearchBriefResearchStatusParams) error {
+	_, err := db.ExecContext(ctx, updateResearchBriefResearchStatus, arg.ResearchStatus, arg.ResearchError, arg.ID)
+	return err
+}
@@ -30,9 +30,41 @@ type Report struct {
 	TrendAnalysisData                string
 	FinalReport                      string
 	CompletedAt                      time.Time
+	// UserID is the user who started the report. It is empty for reports
+	// created before there were user accounts.
+	UserID      string
+	WorkspaceID string
+	// CompanyID is the canonical company the report is about. It is empty
+	// when the candidate it was started from has no usable domain.
+	CompanyID string
 }
 
+// FindReport looks up a report within a workspace.
 func FindReport(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (Report, error) {
+	row, err := db.New().QueryReportByIDAndWorkspaceID(ctx, dbtx, db.NewQueryReportByIDAndWorkspaceIDParams(
+		id.String(),
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return Report{}, err
+	}
+
+	result, err := rowToReport(row)
+	if err != nil {
+		return Report{}, err
+	}
+	return result, nil
+}
+
+// FindReportByID looks up a report in any workspace. It is meant for
+// background jobs and share links, which are authorised by whoever started
+// them; request handlers use FindReport.
+func FindReportByID(
 	ctx context.Context,
 	dbtx db.DBTX,
 	id uuid.UUID,
@@ -65,6 +97,9 @@ type CreateReportData struct {
 	TrendAnalysisData                string
 	FinalReport                      string
 	CompletedAt                      time.Time
+	UserID                           string `validate:"omitempty,uuid"`
+	WorkspaceID                      string `validate:"omitempty,uuid"`
+	CompanyID                        string `validate:"omitempty,uuid"`
 }
 
 func CreateReport(
@@ -92,6 +127,9 @@ func CreateReport(
 		sql.NullString{String: data.TrendAnalysisData, Valid: true},
 		sql.NullString{String: data.FinalReport, Valid: true},
 		sql.NullTime{Time: data.CompletedAt, Valid: true},
+		sql.NullString{String: data.UserID, Valid: data.UserID != ""},
+		optionalWorkspaceParam(data.WorkspaceID),
+		sql.NullString{String: data.CompanyID, Valid: data.CompanyID != ""},
 	)
 	row, err := db.New().InsertReport(ctx, dbtx, params)
 	if err != nil {
@@ -116,8 +154,37 @@ func DestroyReport(
 func AllReports(
 	ctx context.Context,
 	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]Report, error) {
+	rows, err := db.New().QueryAllReports(ctx, dbtx, workspaceParam(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]Report, len(rows))
+	for i, row := range rows {
+		result, err := rowToReport(row)
+		if err != nil {
+			return nil, err
+		}
+		reports[i] = result
+	}
+
+	return reports, nil
+}
+
+// FindReportsByResearchBriefID returns the reports started from the company
+// candidates of a research brief, newest first.
+func FindReportsByResearchBriefID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	researchBriefID uuid.UUID,
 ) ([]Report, error) {
-	rows, err := db.New().QueryAllReports(ctx, dbtx)
+	rows, err := db.New().QueryReportsByResearchBriefID(ctx, dbtx, db.NewQueryReportsByResearchBriefIDParams(
+		researchBriefID.String(),
+		workspaceParam(workspaceID),
+	))
 	if err != nil {
 		return nil, err
 	}
@@ -134,6 +201,80 @@ func AllReports(
 	return reports, nil
 }
 
+// FindReportsByCompanyID returns every report on a company, oldest first,
+// so they read as successive versions of the research.
+func FindReportsByCompanyID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	companyID uuid.UUID,
+) ([]Report, error) {
+	rows, err := db.New().QueryReportsByCompanyID(ctx, dbtx, db.NewQueryReportsByCompanyIDParams(
+		companyID.String(),
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return nil, err
+	}
+
+	reports := make([]Report, len(rows))
+	for i, row := range rows {
+		result, err := rowToReport(row)
+		if err != nil {
+			return nil, err
+		}
+		reports[i] = result
+	}
+
+	return reports, nil
+}
+
+// ReportStatusCanceled is the status of a report whose research was stopped
+// by a user. Its jobs finish without touching it again.
+const ReportStatusCanceled = "canceled"
+
+// Statuses a list of reports can be filtered by. ReportFilterActive matches
+// every report that has neither completed, failed nor been canceled yet.
+const (
+	ReportFilterActive    = "active"
+	ReportFilterCompleted = "completed"
+	ReportFilterFailed    = "failed"
+	ReportFilterCanceled  = ReportStatusCanceled
+)
+
+var ReportStatusFilters = []string{
+	ReportFilterActive,
+	ReportFilterCompleted,
+	ReportFilterFailed,
+	ReportFilterCanceled,
+}
+
+// Orders a list of reports can be sorted in.
+const (
+	ReportSortNewest   = "newest"
+	ReportSortOldest   = "oldest"
+	ReportSortName     = "name"
+	ReportSortProgress = "progress"
+)
+
+var ReportSorts = []string{
+	ReportSortNewest,
+	ReportSortOldest,
+	ReportSortName,
+	ReportSortProgress,
+}
+
+// ReportFilter narrows down and orders a list of reports. The zero value
+// lists every report, newest first.
+type ReportFilter struct {
+	// Search matches reports whose company name contains it, ignoring case.
+	Search string
+	// Status is one of ReportStatusFilters, or empty for any status.
+	Status string
+	// Sort is one of ReportSorts, newest first when empty.
+	Sort string
+}
+
 type PaginatedReports struct {
 	Reports    []Report
 	TotalCount int64
@@ -145,6 +286,8 @@ type PaginatedReports struct {
 func PaginateReports(
 	ctx context.Context,
 	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	filter ReportFilter,
 	page int64,
 	pageSize int64,
 ) (PaginatedReports, error) {
@@ -160,7 +303,11 @@ func PaginateReports(
 
 	offset := (page - 1) * pageSize
 
-	totalCount, err := db.New().CountReports(ctx, dbtx)
+	totalCount, err := db.New().CountReports(ctx, dbtx, db.NewCountReportsParams(
+		workspaceParam(workspaceID),
+		filter.Status,
+		filter.Search,
+	))
 	if err != nil {
 		return PaginatedReports{}, err
 	}
@@ -168,7 +315,14 @@ func PaginateReports(
 	rows, err := db.New().QueryPaginatedReports(
 		ctx,
 		dbtx,
-		db.NewQueryPaginatedReportsParams(pageSize, offset),
+		db.NewQueryPaginatedReportsParams(
+			workspaceParam(workspaceID),
+			filter.Sort,
+			filter.Status,
+			filter.Search,
+			pageSize,
+			offset,
+		),
 	)
 	if err != nil {
 		return PaginatedReports{}, err
@@ -294,7 +448,7 @@ func UpdateReportProgress(
 	dbtx db.DBTX,
 	reportID uuid.UUID,
 ) error {
-	report, err := FindReport(ctx, dbtx, reportID)
+	report, err := FindReportByID(ctx, dbtx, reportID)
 	if err != nil {
 		return err
 	}
@@ -330,6 +484,55 @@ func UpdateFinalReport(
 	})
 }
 
+// MarkReportGenerating moves a report into the generating state. It returns
+// false when generation was already started or the final report exists, so
+// only one caller ends up enqueueing the report generator.
+func MarkReportGenerating(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+) (bool, error) {
+	affected, err := db.New().UpdateReportToGenerating(ctx, dbtx, reportID.String())
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// MarkReportFailed moves a report into the failed state. It returns false
+// when the report already failed, was canceled or was completed, so the
+// failure is only reported once. Failed reports no longer receive progress
+// updates.
+func MarkReportFailed(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+) (bool, error) {
+	affected, err := db.New().UpdateReportToFailed(ctx, dbtx, reportID.String())
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// MarkReportCanceled moves a report into the canceled state. It returns false
+// when the report already finished, failed or was canceled. Canceled reports
+// are no longer updated by their jobs.
+func MarkReportCanceled(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+) (bool, error) {
+	affected, err := db.New().UpdateReportToCanceled(ctx, dbtx, reportID.String())
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
 func rowToReport(row db.Report) (Report, error) {
 	id, err := uuid.Parse(row.ID)
 	if err != nil {
@@ -355,5 +558,8 @@ func rowToReport(row db.Report) (Report, error) {
 		TrendAnalysisData:                row.TrendAnalysisData.String,
 		FinalReport:                      row.FinalReport.String,
 		CompletedAt:                      row.CompletedAt.Time,
+		UserID:                           row.UserID.String,
+		WorkspaceID:                      row.WorkspaceID.String,
+		CompanyID:                        row.CompanyID.String,
 	}, nil
 }
@@ -0,0 +1,90 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// ReportExport is a rendered download of a report, kept until the report it
+// was rendered from changes.
+type ReportExport struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ReportID  string
+	Format    string
+	// Fingerprint identifies the report content the export was rendered
+	// from.
+	Fingerprint string
+	Content     []byte
+}
+
+func FindReportExport(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+	format string,
+) (ReportExport, error) {
+	row, err := db.New().QueryReportExport(ctx, dbtx, db.NewQueryReportExportParams(
+		reportID.String(),
+		format,
+	))
+	if err != nil {
+		return ReportExport{}, err
+	}
+
+	return rowToReportExport(row)
+}
+
+type StoreReportExportData struct {
+	ReportID    uuid.UUID `validate:"required"`
+	Format      string    `validate:"required,max=20"`
+	Fingerprint string    `validate:"required"`
+	Content     []byte    `validate:"required"`
+}
+
+// StoreReportExport saves an export, replacing the previous one of the same
+// report and format.
+func StoreReportExport(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data StoreReportExportData,
+) (ReportExport, error) {
+	if err := validate.Struct(data); err != nil {
+		return ReportExport{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().UpsertReportExport(ctx, dbtx, db.NewUpsertReportExportParams(
+		data.ReportID.String(),
+		data.Format,
+		data.Fingerprint,
+		data.Content,
+	))
+	if err != nil {
+		return ReportExport{}, err
+	}
+
+	return rowToReportExport(row)
+}
+
+func rowToReportExport(row db.ReportExport) (ReportExport, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return ReportExport{}, err
+	}
+
+	return ReportExport{
+		ID:          id,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		ReportID:    row.ReportID,
+		Format:      row.Format,
+		Fingerprint: row.Fingerprint,
+		Content:     row.Content,
+	}, nil
+}
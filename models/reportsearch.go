@@ -0,0 +1,220 @@
+package models
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// The search query asks SQLite to wrap matched terms in these control
+// characters, which never show up in report text, so the snippet can be
+// split into plain and highlighted parts without trusting any markup.
+const (
+	snippetMatchStart = "\x02"
+	snippetMatchEnd   = "\x03"
+)
+
+// SnippetPart is a piece of a search snippet. Match is set on the pieces
+// that matched the search terms.
+type SnippetPart struct {
+	Text  string
+	Match bool
+}
+
+// ReportSearchResult is a report whose sections or final report matched a
+// full-text search.
+type ReportSearchResult struct {
+	ReportID    uuid.UUID
+	CompanyName string
+	Status      string
+	CreatedAt   time.Time
+	// Snippet is the best matching excerpt of the report, taken from the
+	// company name, one of the sections or the final report.
+	Snippet []SnippetPart
+}
+
+type ReportSearchResults struct {
+	Results    []ReportSearchResult
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+// SearchReports runs a full-text search over the company name, sections and
+// final report of every report in the workspace, best matches first. Words
+// in query must all appear, in any order and any grammatical form; double
+// quotes match an exact phrase, OR between two terms matches either, and a
+// trailing * matches any word starting with the term.
+func SearchReports(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	query string,
+	page int64,
+	pageSize int64,
+) (ReportSearchResults, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	terms := reportSearchTerms(query)
+	if terms == "" {
+		return ReportSearchResults{Page: page, PageSize: pageSize}, nil
+	}
+
+	offset := (page - 1) * pageSize
+
+	totalCount, err := db.New().CountSearchReports(ctx, dbtx, db.NewCountSearchReportsParams(
+		terms,
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return ReportSearchResults{}, err
+	}
+
+	rows, err := db.New().SearchReports(ctx, dbtx, db.NewSearchReportsParams(
+		terms,
+		workspaceParam(workspaceID),
+		pageSize,
+		offset,
+	))
+	if err != nil {
+		return ReportSearchResults{}, err
+	}
+
+	results := make([]ReportSearchResult, len(rows))
+	for i, row := range rows {
+		reportID, err := uuid.Parse(row.ID)
+		if err != nil {
+			return ReportSearchResults{}, err
+		}
+
+		results[i] = ReportSearchResult{
+			ReportID:    reportID,
+			CompanyName: row.CompanyName,
+			Status:      row.Status,
+			CreatedAt:   row.CreatedAt,
+			Snippet:     parseSnippet(row.Snippet),
+		}
+	}
+
+	return ReportSearchResults{
+		Results:    results,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (totalCount + pageSize - 1) / pageSize,
+	}, nil
+}
+
+// reportSearchTerms turns what a user typed into an FTS5 query. Every word
+// and quoted phrase is quoted again so that punctuation such as "SOC-2" or
+// an unbalanced quote can never be read as FTS5 syntax. It returns an empty
+// string when nothing searchable is left.
+func reportSearchTerms(query string) string {
+	var (
+		terms   []string
+		pending string
+	)
+	for _, term := range splitSearchQuery(query) {
+		if term.operator {
+			// An OR only joins two terms, so one at the start or end of the
+			// query, or next to another OR, is dropped.
+			if len(terms) > 0 {
+				pending = "OR"
+			}
+			continue
+		}
+
+		text, prefix := term.text, false
+		if !term.phrase && strings.HasSuffix(text, "*") {
+			text, prefix = strings.TrimRight(text, "*"), true
+		}
+		if !strings.ContainsFunc(text, func(r rune) bool {
+			return unicode.IsLetter(r) || unicode.IsNumber(r)
+		}) {
+			continue
+		}
+
+		quoted := `"` + strings.ReplaceAll(text, `"`, `""`) + `"`
+		if prefix {
+			quoted += "*"
+		}
+		if pending != "" {
+			terms = append(terms, pending)
+			pending = ""
+		}
+		terms = append(terms, quoted)
+	}
+
+	return strings.Join(terms, " ")
+}
+
+type searchTerm struct {
+	text     string
+	phrase   bool
+	operator bool
+}
+
+// splitSearchQuery splits a query into words, double quoted phrases and OR
+// operators. An unterminated quote runs to the end of the query.
+func splitSearchQuery(query string) []searchTerm {
+	var terms []searchTerm
+	for {
+		query = strings.TrimSpace(query)
+		if query == "" {
+			return terms
+		}
+
+		if rest, ok := strings.CutPrefix(query, `"`); ok {
+			phrase, after, _ := strings.Cut(rest, `"`)
+			terms = append(terms, searchTerm{text: phrase, phrase: true})
+			query = after
+			continue
+		}
+
+		end := strings.IndexFunc(query, func(r rune) bool {
+			return unicode.IsSpace(r) || r == '"'
+		})
+		if end == -1 {
+			end = len(query)
+		}
+
+		word := query[:end]
+		terms = append(terms, searchTerm{text: word, operator: word == "OR"})
+		query = query[end:]
+	}
+}
+
+func parseSnippet(snippet string) []SnippetPart {
+	var parts []SnippetPart
+	for snippet != "" {
+		before, rest, found := strings.Cut(snippet, snippetMatchStart)
+		if before != "" {
+			parts = append(parts, SnippetPart{Text: before})
+		}
+		if !found {
+			break
+		}
+
+		match, after, _ := strings.Cut(rest, snippetMatchEnd)
+		if match != "" {
+			parts = append(parts, SnippetPart{Text: match, Match: true})
+		}
+		snippet = after
+	}
+
+	return parts
+}
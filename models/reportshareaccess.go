@@ -0,0 +1,105 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	ReportShareAccessGranted = "granted"
+	// ReportShareAccessExpired and ReportShareAccessRevoked record requests
+	// with a valid token for a link that no longer grants access.
+	ReportShareAccessExpired = "expired"
+	ReportShareAccessRevoked = "revoked"
+)
+
+// ReportShareAccess is a single request made through a share link.
+type ReportShareAccess struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	ReportShareLinkID string
+	Outcome           string
+	IPAddress         string
+	UserAgent         string
+}
+
+// FindReportShareAccessesByReportID returns the most recent accesses through
+// any share link of a report, newest first.
+func FindReportShareAccessesByReportID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+	limit int64,
+) ([]ReportShareAccess, error) {
+	rows, err := db.New().QueryReportShareAccessesByReportID(
+		ctx,
+		dbtx,
+		db.NewQueryReportShareAccessesByReportIDParams(reportID.String(), limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	accesses := make([]ReportShareAccess, len(rows))
+	for i, row := range rows {
+		result, err := rowToReportShareAccess(row)
+		if err != nil {
+			return nil, err
+		}
+		accesses[i] = result
+	}
+
+	return accesses, nil
+}
+
+type CreateReportShareAccessData struct {
+	ReportShareLinkID uuid.UUID `validate:"required"`
+	Outcome           string    `validate:"required,oneof=granted expired revoked"`
+	IPAddress         string
+	UserAgent         string
+}
+
+func CreateReportShareAccess(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateReportShareAccessData,
+) (ReportShareAccess, error) {
+	if err := validate.Struct(data); err != nil {
+		return ReportShareAccess{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertReportShareAccess(ctx, dbtx, db.NewInsertReportShareAccessParams(
+		data.ReportShareLinkID.String(),
+		data.Outcome,
+		data.IPAddress,
+		data.UserAgent,
+	))
+	if err != nil {
+		return ReportShareAccess{}, err
+	}
+
+	return rowToReportShareAccess(row)
+}
+
+func rowToReportShareAccess(row db.ReportShareAccess) (ReportShareAccess, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return ReportShareAccess{}, err
+	}
+
+	return ReportShareAccess{
+		ID:                id,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		ReportShareLinkID: row.ReportShareLinkID,
+		Outcome:           row.Outcome,
+		IPAddress:         row.IpAddress,
+		UserAgent:         row.UserAgent,
+	}, nil
+}
@@ -0,0 +1,132 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// ReportShareLink grants read-only access to a report to anyone holding its
+// signed token until it expires or is revoked.
+type ReportShareLink struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ReportID  string
+	Label     string
+	ExpiresAt time.Time
+	RevokedAt time.Time
+}
+
+func (l ReportShareLink) Revoked() bool {
+	return !l.RevokedAt.IsZero()
+}
+
+func (l ReportShareLink) Expired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
+
+// Active reports whether the link still grants access at the given time.
+func (l ReportShareLink) Active(now time.Time) bool {
+	return !l.Revoked() && !l.Expired(now)
+}
+
+func FindReportShareLink(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (ReportShareLink, error) {
+	row, err := db.New().QueryReportShareLinkByID(ctx, dbtx, id.String())
+	if err != nil {
+		return ReportShareLink{}, err
+	}
+
+	return rowToReportShareLink(row)
+}
+
+// FindReportShareLinksByReportID returns the share links of a report, newest
+// first.
+func FindReportShareLinksByReportID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+) ([]ReportShareLink, error) {
+	rows, err := db.New().QueryReportShareLinksByReportID(ctx, dbtx, reportID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	links := make([]ReportShareLink, len(rows))
+	for i, row := range rows {
+		result, err := rowToReportShareLink(row)
+		if err != nil {
+			return nil, err
+		}
+		links[i] = result
+	}
+
+	return links, nil
+}
+
+type CreateReportShareLinkData struct {
+	ReportID  uuid.UUID `validate:"required"`
+	Label     string    `validate:"max=100"`
+	ExpiresAt time.Time `validate:"required"`
+}
+
+func CreateReportShareLink(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateReportShareLinkData,
+) (ReportShareLink, error) {
+	if err := validate.Struct(data); err != nil {
+		return ReportShareLink{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertReportShareLink(ctx, dbtx, db.NewInsertReportShareLinkParams(
+		data.ReportID.String(),
+		data.Label,
+		data.ExpiresAt.UTC(),
+	))
+	if err != nil {
+		return ReportShareLink{}, err
+	}
+
+	return rowToReportShareLink(row)
+}
+
+// RevokeReportShareLink revokes a link. It returns false when the link does
+// not exist or was already revoked.
+func RevokeReportShareLink(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (bool, error) {
+	revoked, err := db.New().RevokeReportShareLink(ctx, dbtx, id.String())
+	if err != nil {
+		return false, err
+	}
+
+	return revoked > 0, nil
+}
+
+func rowToReportShareLink(row db.ReportShareLink) (ReportShareLink, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return ReportShareLink{}, err
+	}
+
+	return ReportShareLink{
+		ID:        id,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		ReportID:  row.ReportID,
+		Label:     row.Label,
+		ExpiresAt: row.ExpiresAt,
+		RevokedAt: row.RevokedAt.Time,
+	}, nil
+}
@@ -2,6 +2,7 @@ package models
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"time"
 
@@ -10,6 +11,18 @@ import (
 	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
 )
 
+const (
+	// ResearchBriefResearchPending is a brief whose preliminary research
+	// runs in the background and has not finished yet.
+	ResearchBriefResearchPending = "pending"
+	// ResearchBriefResearchCompleted is a brief whose preliminary research
+	// has finished and whose candidates are stored.
+	ResearchBriefResearchCompleted = "completed"
+	// ResearchBriefResearchFailed is a brief whose preliminary research
+	// failed; ResearchError says why.
+	ResearchBriefResearchFailed = "failed"
+)
+
 type ResearchBrief struct {
 	ID                   uuid.UUID
 	IdentificationStatus string
@@ -23,9 +36,44 @@ type ResearchBrief struct {
 	ResearchDepth        string
 	ConfidenceScore      float64
 	LastUpdated          time.Time
+	// UserID is the user who ran the research. It is empty for briefs
+	// created before there were user accounts.
+	UserID      string
+	WorkspaceID string
+	// ResearchStatus is one of the ResearchBriefResearch statuses.
+	ResearchStatus string
+	// Query is what the research was asked to identify. It is empty for
+	// briefs researched while they were requested.
+	Query         string
+	ResearchError string
 }
 
+// FindResearchBrief looks up a research brief within a workspace.
 func FindResearchBrief(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (ResearchBrief, error) {
+	row, err := db.New().QueryResearchBriefByIDAndWorkspaceID(
+		ctx,
+		dbtx,
+		db.NewQueryResearchBriefByIDAndWorkspaceIDParams(id.String(), workspaceParam(workspaceID)),
+	)
+	if err != nil {
+		return ResearchBrief{}, err
+	}
+
+	result, err := rowToResearchBrief(row)
+	if err != nil {
+		return ResearchBrief{}, err
+	}
+	return result, nil
+}
+
+// FindResearchBriefByID looks up a research brief in any workspace, for
+// background jobs.
+func FindResearchBriefByID(
 	ctx context.Context,
 	dbtx db.DBTX,
 	id uuid.UUID,
@@ -54,6 +102,11 @@ type CreateResearchBriefData struct {
 	ResearchDepth        string
 	ConfidenceScore      float64
 	LastUpdated          time.Time
+	UserID               string `validate:"omitempty,uuid"`
+	WorkspaceID          string `validate:"omitempty,uuid"`
+	// ResearchStatus defaults to ResearchBriefResearchCompleted.
+	ResearchStatus string `validate:"omitempty,oneof=pending completed failed"`
+	Query          string
 }
 
 func CreateResearchBrief(
@@ -65,6 +118,11 @@ func CreateResearchBrief(
 		return ResearchBrief{}, errors.Join(ErrDomainValidation, err)
 	}
 
+	researchStatus := data.ResearchStatus
+	if researchStatus == "" {
+		researchStatus = ResearchBriefResearchCompleted
+	}
+
 	params := db.NewInsertResearchBriefParams(
 		data.IdentificationStatus,
 		data.CompanyName,
@@ -77,6 +135,10 @@ func CreateResearchBrief(
 		data.ResearchDepth,
 		data.ConfidenceScore,
 		data.LastUpdated,
+		sql.NullString{String: data.UserID, Valid: data.UserID != ""},
+		optionalWorkspaceParam(data.WorkspaceID),
+		researchStatus,
+		data.Query,
 	)
 	row, err := db.New().InsertResearchBrief(ctx, dbtx, params)
 	if err != nil {
@@ -201,6 +263,22 @@ func UpdateResearchBrief(
 	return result, nil
 }
 
+// UpdateResearchBriefResearchStatus records how the background research of
+// a brief went; researchError is empty unless it failed.
+func UpdateResearchBriefResearchStatus(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	status string,
+	researchError string,
+) error {
+	return db.New().UpdateResearchBriefResearchStatus(ctx, dbtx, db.NewUpdateResearchBriefResearchStatusParams(
+		id.String(),
+		status,
+		researchError,
+	))
+}
+
 func DestroyResearchBrief(
 	ctx context.Context,
 	dbtx db.DBTX,
@@ -212,8 +290,37 @@ func DestroyResearchBrief(
 func AllResearchBriefs(
 	ctx context.Context,
 	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]ResearchBrief, error) {
+	rows, err := db.New().QueryAllResearchBriefs(ctx, dbtx, workspaceParam(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	researchbriefs := make([]ResearchBrief, len(rows))
+	for i, row := range rows {
+		result, err := rowToResearchBrief(row)
+		if err != nil {
+			return nil, err
+		}
+		researchbriefs[i] = result
+	}
+
+	return researchbriefs, nil
+}
+
+// FindResearchBriefsByCompanyID returns the research briefs that found the
+// company among their candidates, most recently updated first.
+func FindResearchBriefsByCompanyID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	companyID uuid.UUID,
 ) ([]ResearchBrief, error) {
-	rows, err := db.New().QueryAllResearchBriefs(ctx, dbtx)
+	rows, err := db.New().QueryResearchBriefsByCompanyID(ctx, dbtx, db.NewQueryResearchBriefsByCompanyIDParams(
+		workspaceParam(workspaceID),
+		companyID.String(),
+	))
 	if err != nil {
 		return nil, err
 	}
@@ -230,6 +337,44 @@ func AllResearchBriefs(
 	return researchbriefs, nil
 }
 
+// Orders a list of research briefs can be sorted in.
+const (
+	ResearchBriefSortNewest     = "newest"
+	ResearchBriefSortOldest     = "oldest"
+	ResearchBriefSortName       = "name"
+	ResearchBriefSortConfidence = "confidence"
+)
+
+var ResearchBriefSorts = []string{
+	ResearchBriefSortNewest,
+	ResearchBriefSortOldest,
+	ResearchBriefSortName,
+	ResearchBriefSortConfidence,
+}
+
+// ResearchBriefFilter narrows down and orders a list of research briefs.
+// The zero value lists every brief, most recently updated first.
+type ResearchBriefFilter struct {
+	// Search matches briefs whose company name contains it, ignoring case.
+	Search string
+	// IdentificationStatus matches briefs with that identification status,
+	// ignoring case, or any status when empty.
+	IdentificationStatus string
+	// Sort is one of ResearchBriefSorts, newest first when empty.
+	Sort string
+}
+
+// ResearchBriefIdentificationStatuses returns the distinct identification
+// statuses of the research briefs in a workspace. The statuses are written
+// by the research agent, so they are read back instead of being fixed.
+func ResearchBriefIdentificationStatuses(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]string, error) {
+	return db.New().QueryResearchBriefIdentificationStatuses(ctx, dbtx, workspaceParam(workspaceID))
+}
+
 type PaginatedResearchBriefs struct {
 	ResearchBriefs []ResearchBrief
 	TotalCount     int64
@@ -241,6 +386,8 @@ type PaginatedResearchBriefs struct {
 func PaginateResearchBriefs(
 	ctx context.Context,
 	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	filter ResearchBriefFilter,
 	page int64,
 	pageSize int64,
 ) (PaginatedResearchBriefs, error) {
@@ -256,7 +403,11 @@ func PaginateResearchBriefs(
 
 	offset := (page - 1) * pageSize
 
-	totalCount, err := db.New().CountResearchBriefs(ctx, dbtx)
+	totalCount, err := db.New().CountResearchBriefs(ctx, dbtx, db.NewCountResearchBriefsParams(
+		workspaceParam(workspaceID),
+		filter.IdentificationStatus,
+		filter.Search,
+	))
 	if err != nil {
 		return PaginatedResearchBriefs{}, err
 	}
@@ -264,7 +415,14 @@ func PaginateResearchBriefs(
 	rows, err := db.New().QueryPaginatedResearchBriefs(
 		ctx,
 		dbtx,
-		db.NewQueryPaginatedResearchBriefsParams(pageSize, offset),
+		db.NewQueryPaginatedResearchBriefsParams(
+			workspaceParam(workspaceID),
+			filter.Sort,
+			filter.IdentificationStatus,
+			filter.Search,
+			pageSize,
+			offset,
+		),
 	)
 	if err != nil {
 		return PaginatedResearchBriefs{}, err
@@ -309,5 +467,10 @@ func rowToResearchBrief(row db.Researchbrief) (ResearchBrief, error) {
 		ResearchDepth:        row.ResearchDepth,
 		ConfidenceScore:      row.ConfidenceScore,
 		LastUpdated:          row.LastUpdated,
+		UserID:               row.UserID.String,
+		WorkspaceID:          row.WorkspaceID.String,
+		ResearchStatus:       row.ResearchStatus,
+		Query:                row.Query,
+		ResearchError:        row.ResearchError,
 	}, nil
 }
@@ -0,0 +1,98 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// ToolCacheEntry is a result of a research tool kept so repeated calls with
+// the same input do not hit the paid APIs again.
+type ToolCacheEntry struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	// UpdatedAt is when Value was last fetched.
+	UpdatedAt time.Time
+	Tool      string
+	CacheKey  string
+	Value     string
+	ExpiresAt time.Time
+}
+
+// FindToolCacheEntry returns the entry of the tool for the key, if it has
+// not expired at now.
+func FindToolCacheEntry(
+	ctx context.Context,
+	dbtx db.DBTX,
+	tool string,
+	cacheKey string,
+	now time.Time,
+) (ToolCacheEntry, error) {
+	row, err := db.New().QueryToolCacheEntry(ctx, dbtx, db.NewQueryToolCacheEntryParams(
+		tool,
+		cacheKey,
+		now,
+	))
+	if err != nil {
+		return ToolCacheEntry{}, err
+	}
+
+	return rowToToolCacheEntry(row)
+}
+
+type StoreToolCacheEntryData struct {
+	Tool      string    `validate:"required"`
+	CacheKey  string    `validate:"required"`
+	Value     string    `validate:"required"`
+	ExpiresAt time.Time `validate:"required"`
+}
+
+// StoreToolCacheEntry saves an entry, replacing the previous one of the same
+// tool and key.
+func StoreToolCacheEntry(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data StoreToolCacheEntryData,
+) (ToolCacheEntry, error) {
+	if err := validate.Struct(data); err != nil {
+		return ToolCacheEntry{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().UpsertToolCacheEntry(ctx, dbtx, db.NewUpsertToolCacheEntryParams(
+		data.Tool,
+		data.CacheKey,
+		data.Value,
+		data.ExpiresAt,
+	))
+	if err != nil {
+		return ToolCacheEntry{}, err
+	}
+
+	return rowToToolCacheEntry(row)
+}
+
+// DestroyExpiredToolCacheEntries deletes the entries expired at now.
+func DestroyExpiredToolCacheEntries(ctx context.Context, dbtx db.DBTX, now time.Time) error {
+	return db.New().DeleteExpiredToolCacheEntries(ctx, dbtx, now)
+}
+
+func rowToToolCacheEntry(row db.ToolCacheEntry) (ToolCacheEntry, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return ToolCacheEntry{}, err
+	}
+
+	return ToolCacheEntry{
+		ID:        id,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		Tool:      row.Tool,
+		CacheKey:  row.CacheKey,
+		Value:     row.Value,
+		ExpiresAt: row.ExpiresAt,
+	}, nil
+}
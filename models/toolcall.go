@@ -0,0 +1,225 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	// ToolCallStatusSucceeded is a call whose result reached the model.
+	ToolCallStatusSucceeded = "succeeded"
+	// ToolCallStatusFailed is a call that failed in a way the model was
+	// told about, such as a page that could not be fetched.
+	ToolCallStatusFailed = "failed"
+	// ToolCallStatusRefused is a call that was not run, because the agent
+	// may not use the tool or had used up its quota.
+	ToolCallStatusRefused = "refused"
+	// ToolCallStatusErrored is a call whose error ended the agent's run.
+	ToolCallStatusErrored = "errored"
+	// ToolCallStatusCanceled is a call stopped by the report's
+	// cancellation or shutdown.
+	ToolCallStatusCanceled = "canceled"
+)
+
+// ToolCallStatuses lists the statuses a tool call can have.
+var ToolCallStatuses = []string{
+	ToolCallStatusSucceeded,
+	ToolCallStatusFailed,
+	ToolCallStatusRefused,
+	ToolCallStatusErrored,
+	ToolCallStatusCanceled,
+}
+
+// ToolCall is the record of an agent calling a tool: what it asked for and
+// how the call went, kept to check vendor invoices and agent behaviour.
+type ToolCall struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	WorkspaceID string
+	// ReportID is empty for calls made before a report existed, such as
+	// those of the preliminary research.
+	ReportID  string
+	Agent     string
+	Tool      string
+	Arguments string
+	Status    string
+	Latency   time.Duration
+	// ResponseSize is the size of the result in bytes.
+	ResponseSize int64
+	ErrorMessage string
+	// Backend names the backends that served the call, such as web_fetch,
+	// serper_scrape or searxng, comma-separated when several did. It is
+	// empty for calls that reached none.
+	Backend string
+	// Cached is set when the result came from the tool cache, so no
+	// backend was billed for it.
+	Cached bool
+}
+
+type CreateToolCallData struct {
+	WorkspaceID  string        `validate:"omitempty,uuid"`
+	ReportID     string        `validate:"omitempty,uuid"`
+	Agent        string        `validate:"required"`
+	Tool         string        `validate:"required"`
+	Arguments    string        `validate:"omitempty"`
+	Status       string        `validate:"required,oneof=succeeded failed refused errored canceled"`
+	Latency      time.Duration `validate:"gte=0"`
+	ResponseSize int64         `validate:"gte=0"`
+	ErrorMessage string
+	Backend      string
+	Cached       bool
+}
+
+func CreateToolCall(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateToolCallData,
+) (ToolCall, error) {
+	if err := validate.Struct(data); err != nil {
+		return ToolCall{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertToolCall(ctx, dbtx, db.NewInsertToolCallParams(
+		optionalWorkspaceParam(data.WorkspaceID),
+		sql.NullString{String: data.ReportID, Valid: data.ReportID != ""},
+		data.Agent,
+		data.Tool,
+		data.Arguments,
+		data.Status,
+		data.Latency.Milliseconds(),
+		data.ResponseSize,
+		data.ErrorMessage,
+		data.Backend,
+		data.Cached,
+	))
+	if err != nil {
+		return ToolCall{}, err
+	}
+
+	return rowToToolCall(row)
+}
+
+// ToolCallFilter narrows a list of tool calls. Empty fields match every
+// call; Search matches the arguments, ignoring case.
+type ToolCallFilter struct {
+	ReportID string
+	Agent    string
+	Tool     string
+	Status   string
+	Search   string
+}
+
+type PaginatedToolCalls struct {
+	ToolCalls  []ToolCall
+	TotalCount int64
+	Page       int64
+	PageSize   int64
+	TotalPages int64
+}
+
+// PaginateToolCalls lists the tool calls of the workspace that match the
+// filter, newest first.
+func PaginateToolCalls(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	filter ToolCallFilter,
+	page int64,
+	pageSize int64,
+) (PaginatedToolCalls, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 10
+	}
+	if pageSize > 100 {
+		pageSize = 100
+	}
+
+	offset := (page - 1) * pageSize
+
+	totalCount, err := db.New().CountToolCalls(ctx, dbtx, db.NewCountToolCallsParams(
+		workspaceParam(workspaceID),
+		filter.ReportID,
+		filter.Agent,
+		filter.Tool,
+		filter.Status,
+		filter.Search,
+	))
+	if err != nil {
+		return PaginatedToolCalls{}, err
+	}
+
+	rows, err := db.New().QueryPaginatedToolCalls(ctx, dbtx, db.NewQueryPaginatedToolCallsParams(
+		workspaceParam(workspaceID),
+		filter.ReportID,
+		filter.Agent,
+		filter.Tool,
+		filter.Status,
+		filter.Search,
+		pageSize,
+		offset,
+	))
+	if err != nil {
+		return PaginatedToolCalls{}, err
+	}
+
+	toolCalls := make([]ToolCall, len(rows))
+	for i, row := range rows {
+		toolCall, err := rowToToolCall(row)
+		if err != nil {
+			return PaginatedToolCalls{}, err
+		}
+		toolCalls[i] = toolCall
+	}
+
+	return PaginatedToolCalls{
+		ToolCalls:  toolCalls,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: (totalCount + pageSize - 1) / pageSize,
+	}, nil
+}
+
+// FindToolCallAgents returns the agents that called tools in the workspace.
+func FindToolCallAgents(ctx context.Context, dbtx db.DBTX, workspaceID uuid.UUID) ([]string, error) {
+	return db.New().QueryToolCallAgents(ctx, dbtx, workspaceParam(workspaceID))
+}
+
+// FindToolCallTools returns the tools that were called in the workspace.
+func FindToolCallTools(ctx context.Context, dbtx db.DBTX, workspaceID uuid.UUID) ([]string, error) {
+	return db.New().QueryToolCallTools(ctx, dbtx, workspaceParam(workspaceID))
+}
+
+func rowToToolCall(row db.ToolCall) (ToolCall, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return ToolCall{}, err
+	}
+
+	return ToolCall{
+		ID:           id,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+		WorkspaceID:  row.WorkspaceID.String,
+		ReportID:     row.ReportID.String,
+		Agent:        row.Agent,
+		Tool:         row.Tool,
+		Arguments:    row.Arguments,
+		Status:       row.Status,
+		Latency:      time.Duration(row.LatencyMs) * time.Millisecond,
+		ResponseSize: row.ResponseSize,
+		ErrorMessage: row.ErrorMessage,
+		Backend:      row.Backend,
+		Cached:       row.Cached,
+	}, nil
+}
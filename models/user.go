@@ -0,0 +1,157 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+type User struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Email     string
+	// PasswordHash is the encoded argon2id hash of the password, including
+	// its parameters and salt. It is empty for users provisioned through
+	// single sign-on, who cannot sign in with a password.
+	PasswordHash string
+	LastLoginAt  time.Time
+	// OIDCIssuer and OIDCSubject identify the account at the single sign-on
+	// identity provider, once the user has signed in through it.
+	OIDCIssuer  string
+	OIDCSubject string
+}
+
+func FindUser(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (User, error) {
+	row, err := db.New().QueryUserByID(ctx, dbtx, id.String())
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
+// FindUserByEmail looks up a user by email address, ignoring case.
+func FindUserByEmail(
+	ctx context.Context,
+	dbtx db.DBTX,
+	email string,
+) (User, error) {
+	row, err := db.New().QueryUserByEmail(ctx, dbtx, normalizeEmail(email))
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
+// FindUserByOIDCIdentity looks up the user signed in as subject at the
+// identity provider issuer.
+func FindUserByOIDCIdentity(
+	ctx context.Context,
+	dbtx db.DBTX,
+	issuer string,
+	subject string,
+) (User, error) {
+	row, err := db.New().QueryUserByOIDCIdentity(ctx, dbtx, db.NewQueryUserByOIDCIdentityParams(
+		sql.NullString{String: issuer, Valid: true},
+		sql.NullString{String: subject, Valid: true},
+	))
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
+// CreateUserData describes a new account. Accounts provisioned through
+// single sign-on have an OIDC identity instead of a password, and cannot
+// sign in with a password.
+type CreateUserData struct {
+	Email        string `validate:"required,email,max=255"`
+	PasswordHash string `validate:"required_without=OIDCSubject"`
+	OIDCIssuer   string `validate:"required_with=OIDCSubject"`
+	OIDCSubject  string `validate:"max=255"`
+}
+
+func CreateUser(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateUserData,
+) (User, error) {
+	if err := validate.Struct(data); err != nil {
+		return User{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertUser(ctx, dbtx, db.NewInsertUserParams(
+		normalizeEmail(data.Email),
+		data.PasswordHash,
+		sql.NullString{String: data.OIDCIssuer, Valid: data.OIDCSubject != ""},
+		sql.NullString{String: data.OIDCSubject, Valid: data.OIDCSubject != ""},
+	))
+	if err != nil {
+		return User{}, err
+	}
+
+	return rowToUser(row)
+}
+
+func TouchUserLogin(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	loggedInAt time.Time,
+) error {
+	return db.New().UpdateUserLastLogin(ctx, dbtx, db.NewUpdateUserLastLoginParams(
+		id.String(),
+		loggedInAt,
+	))
+}
+
+// LinkUserOIDCIdentity records the identity provider account of an existing
+// user, who can sign in through single sign-on from then on.
+func LinkUserOIDCIdentity(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	issuer string,
+	subject string,
+) error {
+	return db.New().UpdateUserOIDCIdentity(ctx, dbtx, db.NewUpdateUserOIDCIdentityParams(
+		id.String(),
+		sql.NullString{String: issuer, Valid: true},
+		sql.NullString{String: subject, Valid: true},
+	))
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+func rowToUser(row db.User) (User, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return User{}, err
+	}
+
+	return User{
+		ID:           id,
+		CreatedAt:    row.CreatedAt,
+		UpdatedAt:    row.UpdatedAt,
+		Email:        row.Email,
+		PasswordHash: row.PasswordHash,
+		LastLoginAt:  row.LastLoginAt.Time,
+		OIDCIssuer:   row.OidcIssuer.String,
+		OIDCSubject:  row.OidcSubject.String,
+	}, nil
+}
@@ -0,0 +1,243 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	WatchlistScheduleWeekly  = "weekly"
+	WatchlistScheduleMonthly = "monthly"
+)
+
+type Watchlist struct {
+	ID                 uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	CompanyCandidateID string
+	CompanyName        string
+	CompanyURL         string
+	Schedule           string
+	NextRunAt          time.Time
+	LastRunAt          time.Time
+	LastReportID       string
+	WorkspaceID        string
+}
+
+// NextWatchlistRun returns the time a watchlist entry on the given schedule
+// should be re-researched, counting from the provided time.
+func NextWatchlistRun(schedule string, from time.Time) time.Time {
+	if schedule == WatchlistScheduleMonthly {
+		return from.AddDate(0, 1, 0)
+	}
+
+	return from.AddDate(0, 0, 7)
+}
+
+func FindWatchlist(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (Watchlist, error) {
+	row, err := db.New().QueryWatchlistByID(ctx, dbtx, db.NewQueryWatchlistByIDParams(
+		id.String(),
+		workspaceParam(workspaceID),
+	))
+	if err != nil {
+		return Watchlist{}, err
+	}
+
+	return rowToWatchlist(row)
+}
+
+func FindWatchlistByCompanyCandidateID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	companyCandidateID string,
+) (Watchlist, error) {
+	row, err := db.New().QueryWatchlistByCompanyCandidateID(
+		ctx,
+		dbtx,
+		db.NewQueryWatchlistByCompanyCandidateIDParams(companyCandidateID, workspaceParam(workspaceID)),
+	)
+	if err != nil {
+		return Watchlist{}, err
+	}
+
+	return rowToWatchlist(row)
+}
+
+func AllWatchlists(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]Watchlist, error) {
+	rows, err := db.New().QueryAllWatchlists(ctx, dbtx, workspaceParam(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToWatchlists(rows)
+}
+
+// DueWatchlists returns every watchlist entry whose next run is at or before
+// the provided time.
+func DueWatchlists(
+	ctx context.Context,
+	dbtx db.DBTX,
+	now time.Time,
+) ([]Watchlist, error) {
+	rows, err := db.New().QueryDueWatchlists(ctx, dbtx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToWatchlists(rows)
+}
+
+type CreateWatchlistData struct {
+	CompanyCandidateID string `validate:"required,uuid"`
+	CompanyName        string `validate:"required"`
+	CompanyURL         string
+	Schedule           string `validate:"required,oneof=weekly monthly"`
+	NextRunAt          time.Time
+	LastReportID       string
+	WorkspaceID        string `validate:"required,uuid"`
+}
+
+func CreateWatchlist(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateWatchlistData,
+) (Watchlist, error) {
+	if err := validate.Struct(data); err != nil {
+		return Watchlist{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	params := db.NewInsertWatchlistParams(
+		data.CompanyCandidateID,
+		data.CompanyName,
+		data.CompanyURL,
+		data.Schedule,
+		data.NextRunAt,
+		sql.NullTime{},
+		sql.NullString{String: data.LastReportID, Valid: data.LastReportID != ""},
+		optionalWorkspaceParam(data.WorkspaceID),
+	)
+	row, err := db.New().InsertWatchlist(ctx, dbtx, params)
+	if err != nil {
+		return Watchlist{}, err
+	}
+
+	return rowToWatchlist(row)
+}
+
+type UpdateWatchlistScheduleData struct {
+	ID        uuid.UUID
+	Schedule  string `validate:"required,oneof=weekly monthly"`
+	NextRunAt time.Time
+}
+
+func UpdateWatchlistSchedule(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data UpdateWatchlistScheduleData,
+) (Watchlist, error) {
+	if err := validate.Struct(data); err != nil {
+		return Watchlist{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().UpdateWatchlistSchedule(
+		ctx,
+		dbtx,
+		db.NewUpdateWatchlistScheduleParams(data.ID.String(), data.Schedule, data.NextRunAt),
+	)
+	if err != nil {
+		return Watchlist{}, err
+	}
+
+	return rowToWatchlist(row)
+}
+
+// RecordWatchlistRun stores when a re-research run started and moves the
+// next run forward according to the watchlist schedule.
+func RecordWatchlistRun(
+	ctx context.Context,
+	dbtx db.DBTX,
+	watchlist Watchlist,
+	ranAt time.Time,
+) error {
+	return db.New().UpdateWatchlistRun(ctx, dbtx, db.NewUpdateWatchlistRunParams(
+		watchlist.ID.String(),
+		sql.NullTime{Time: ranAt, Valid: true},
+		NextWatchlistRun(watchlist.Schedule, ranAt),
+	))
+}
+
+// RecordWatchlistReport makes a completed report the one the next run of the
+// watchlist is compared against.
+func RecordWatchlistReport(
+	ctx context.Context,
+	dbtx db.DBTX,
+	watchlistID uuid.UUID,
+	reportID uuid.UUID,
+) error {
+	return db.New().UpdateWatchlistLastReport(ctx, dbtx, db.NewUpdateWatchlistLastReportParams(
+		watchlistID.String(),
+		sql.NullString{String: reportID.String(), Valid: true},
+	))
+}
+
+func DestroyWatchlist(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) error {
+	return db.New().DeleteWatchlist(ctx, dbtx, db.NewDeleteWatchlistParams(
+		id.String(),
+		workspaceParam(workspaceID),
+	))
+}
+
+func rowsToWatchlists(rows []db.Watchlist) ([]Watchlist, error) {
+	watchlists := make([]Watchlist, len(rows))
+	for i, row := range rows {
+		result, err := rowToWatchlist(row)
+		if err != nil {
+			return nil, err
+		}
+		watchlists[i] = result
+	}
+
+	return watchlists, nil
+}
+
+func rowToWatchlist(row db.Watchlist) (Watchlist, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return Watchlist{}, err
+	}
+
+	return Watchlist{
+		ID:                 id,
+		CreatedAt:          row.CreatedAt,
+		UpdatedAt:          row.UpdatedAt,
+		CompanyCandidateID: row.CompanyCandidateID,
+		CompanyName:        row.CompanyName,
+		CompanyURL:         row.CompanyUrl,
+		Schedule:           row.Schedule,
+		NextRunAt:          row.NextRunAt,
+		LastRunAt:          row.LastRunAt.Time,
+		LastReportID:       row.LastReportID.String,
+		WorkspaceID:        row.WorkspaceID.String,
+	}, nil
+}
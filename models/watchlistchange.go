@@ -0,0 +1,176 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	WatchlistChangePending   = "pending"
+	WatchlistChangeCompleted = "completed"
+	WatchlistChangeFailed    = "failed"
+)
+
+type WatchlistChange struct {
+	ID                 uuid.UUID
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	WatchlistID        string
+	ReportID           string
+	PreviousReportID   string
+	Status             string
+	HasMaterialChanges bool
+	Summary            string
+}
+
+func FindWatchlistChange(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (WatchlistChange, error) {
+	row, err := db.New().QueryWatchlistChangeByID(ctx, dbtx, id.String())
+	if err != nil {
+		return WatchlistChange{}, err
+	}
+
+	return rowToWatchlistChange(row)
+}
+
+func FindWatchlistChangeByReportID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	reportID uuid.UUID,
+) (WatchlistChange, error) {
+	row, err := db.New().QueryWatchlistChangeByReportID(ctx, dbtx, reportID.String())
+	if err != nil {
+		return WatchlistChange{}, err
+	}
+
+	return rowToWatchlistChange(row)
+}
+
+func FindWatchlistChangesByWatchlistID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	watchlistID uuid.UUID,
+) ([]WatchlistChange, error) {
+	rows, err := db.New().QueryWatchlistChangesByWatchlistID(ctx, dbtx, watchlistID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToWatchlistChanges(rows)
+}
+
+// LatestWatchlistChanges returns the most recently completed change summaries
+// across all watchlist entries.
+func LatestWatchlistChanges(
+	ctx context.Context,
+	dbtx db.DBTX,
+	limit int64,
+) ([]WatchlistChange, error) {
+	rows, err := db.New().QueryLatestWatchlistChanges(ctx, dbtx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToWatchlistChanges(rows)
+}
+
+type CreateWatchlistChangeData struct {
+	WatchlistID      string `validate:"required,uuid"`
+	ReportID         string `validate:"required,uuid"`
+	PreviousReportID string
+}
+
+func CreateWatchlistChange(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateWatchlistChangeData,
+) (WatchlistChange, error) {
+	if err := validate.Struct(data); err != nil {
+		return WatchlistChange{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	params := db.NewInsertWatchlistChangeParams(
+		data.WatchlistID,
+		data.ReportID,
+		sql.NullString{String: data.PreviousReportID, Valid: data.PreviousReportID != ""},
+		WatchlistChangePending,
+		sql.NullBool{Bool: false, Valid: true},
+		sql.NullString{},
+	)
+	row, err := db.New().InsertWatchlistChange(ctx, dbtx, params)
+	if err != nil {
+		return WatchlistChange{}, err
+	}
+
+	return rowToWatchlistChange(row)
+}
+
+func CompleteWatchlistChange(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	hasMaterialChanges bool,
+	summary string,
+) error {
+	return db.New().UpdateWatchlistChangeSummary(ctx, dbtx, db.NewUpdateWatchlistChangeSummaryParams(
+		id.String(),
+		WatchlistChangeCompleted,
+		sql.NullBool{Bool: hasMaterialChanges, Valid: true},
+		sql.NullString{String: summary, Valid: true},
+	))
+}
+
+func FailWatchlistChange(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	reason string,
+) error {
+	return db.New().UpdateWatchlistChangeSummary(ctx, dbtx, db.NewUpdateWatchlistChangeSummaryParams(
+		id.String(),
+		WatchlistChangeFailed,
+		sql.NullBool{Bool: false, Valid: true},
+		sql.NullString{String: reason, Valid: true},
+	))
+}
+
+func rowsToWatchlistChanges(rows []db.WatchlistChange) ([]WatchlistChange, error) {
+	changes := make([]WatchlistChange, len(rows))
+	for i, row := range rows {
+		result, err := rowToWatchlistChange(row)
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = result
+	}
+
+	return changes, nil
+}
+
+func rowToWatchlistChange(row db.WatchlistChange) (WatchlistChange, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return WatchlistChange{}, err
+	}
+
+	return WatchlistChange{
+		ID:                 id,
+		CreatedAt:          row.CreatedAt,
+		UpdatedAt:          row.UpdatedAt,
+		WatchlistID:        row.WatchlistID,
+		ReportID:           row.ReportID,
+		PreviousReportID:   row.PreviousReportID.String,
+		Status:             row.Status,
+		HasMaterialChanges: row.HasMaterialChanges.Bool,
+		Summary:            row.Summary.String,
+	}, nil
+}
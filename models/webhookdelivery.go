@@ -0,0 +1,148 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	// WebhookDeliveryPending deliveries wait for their first attempt or for
+	// a retry after a failed attempt.
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	// WebhookDeliveryFailed deliveries ran out of attempts.
+	WebhookDeliveryFailed = "failed"
+)
+
+type WebhookDelivery struct {
+	ID                uuid.UUID
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	WebhookEndpointID string
+	Event             string
+	Payload           string
+	Status            string
+	Attempts          int64
+	LastStatusCode    int64
+	LastError         string
+	NextAttemptAt     time.Time
+	DeliveredAt       time.Time
+}
+
+func FindWebhookDelivery(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (WebhookDelivery, error) {
+	row, err := db.New().QueryWebhookDeliveryByID(ctx, dbtx, id.String())
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	return rowToWebhookDelivery(row)
+}
+
+// FindWebhookDeliveriesByEndpointID returns the most recent deliveries of an
+// endpoint, newest first.
+func FindWebhookDeliveriesByEndpointID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	endpointID uuid.UUID,
+	limit int64,
+) ([]WebhookDelivery, error) {
+	rows, err := db.New().QueryWebhookDeliveriesByEndpointID(
+		ctx,
+		dbtx,
+		db.NewQueryWebhookDeliveriesByEndpointIDParams(endpointID.String(), limit),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]WebhookDelivery, len(rows))
+	for i, row := range rows {
+		result, err := rowToWebhookDelivery(row)
+		if err != nil {
+			return nil, err
+		}
+		deliveries[i] = result
+	}
+
+	return deliveries, nil
+}
+
+// CreateWebhookDelivery stores a pending delivery of an already encoded
+// payload to an endpoint.
+func CreateWebhookDelivery(
+	ctx context.Context,
+	dbtx db.DBTX,
+	endpointID uuid.UUID,
+	event string,
+	payload string,
+) (WebhookDelivery, error) {
+	row, err := db.New().InsertWebhookDelivery(ctx, dbtx, db.NewInsertWebhookDeliveryParams(
+		endpointID.String(),
+		event,
+		payload,
+	))
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	return rowToWebhookDelivery(row)
+}
+
+type RecordWebhookDeliveryAttemptData struct {
+	ID         uuid.UUID
+	Status     string
+	Attempts   int64
+	StatusCode int64
+	Error      string
+	// NextAttemptAt is only set while a retry is scheduled.
+	NextAttemptAt time.Time
+	DeliveredAt   time.Time
+}
+
+// RecordWebhookDeliveryAttempt stores the outcome of the latest attempt.
+func RecordWebhookDeliveryAttempt(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data RecordWebhookDeliveryAttemptData,
+) error {
+	return db.New().UpdateWebhookDeliveryAttempt(ctx, dbtx, db.NewUpdateWebhookDeliveryAttemptParams(
+		data.ID.String(),
+		data.Status,
+		data.Attempts,
+		sql.NullInt64{Int64: data.StatusCode, Valid: data.StatusCode != 0},
+		sql.NullString{String: data.Error, Valid: data.Error != ""},
+		sql.NullTime{Time: data.NextAttemptAt, Valid: !data.NextAttemptAt.IsZero()},
+		sql.NullTime{Time: data.DeliveredAt, Valid: !data.DeliveredAt.IsZero()},
+	))
+}
+
+func rowToWebhookDelivery(row db.WebhookDelivery) (WebhookDelivery, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return WebhookDelivery{}, err
+	}
+
+	return WebhookDelivery{
+		ID:                id,
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+		WebhookEndpointID: row.WebhookEndpointID,
+		Event:             row.Event,
+		Payload:           row.Payload,
+		Status:            row.Status,
+		Attempts:          row.Attempts,
+		LastStatusCode:    row.LastStatusCode.Int64,
+		LastError:         row.LastError.String,
+		NextAttemptAt:     row.NextAttemptAt.Time,
+		DeliveredAt:       row.DeliveredAt.Time,
+	}, nil
+}
@@ -0,0 +1,230 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	WebhookEventReportStarted          = "report.started"
+	WebhookEventReportSectionCompleted = "report.section_completed"
+	WebhookEventReportCompleted        = "report.completed"
+	WebhookEventReportFailed           = "report.failed"
+	WebhookEventReportCanceled         = "report.canceled"
+)
+
+// WebhookEvents lists every event an endpoint can subscribe to.
+var WebhookEvents = []string{
+	WebhookEventReportStarted,
+	WebhookEventReportSectionCompleted,
+	WebhookEventReportCompleted,
+	WebhookEventReportFailed,
+	WebhookEventReportCanceled,
+}
+
+// WebhookSecretPrefix marks signing secrets so they are recognisable in
+// configuration files and secret scanners.
+const WebhookSecretPrefix = "whsec_"
+
+type WebhookEndpoint struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	URL         string
+	Description string
+	// Secret signs every payload sent to the endpoint. It is kept in plain
+	// text since the signature has to be computed on each delivery.
+	Secret      string
+	Events      []string
+	Active      bool
+	WorkspaceID string
+}
+
+func (e WebhookEndpoint) Subscribes(event string) bool {
+	return slices.Contains(e.Events, event)
+}
+
+// FindWebhookEndpoint looks up an endpoint within a workspace.
+func FindWebhookEndpoint(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (WebhookEndpoint, error) {
+	row, err := db.New().QueryWebhookEndpointByIDAndWorkspaceID(
+		ctx,
+		dbtx,
+		db.NewQueryWebhookEndpointByIDAndWorkspaceIDParams(id.String(), workspaceParam(workspaceID)),
+	)
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	return rowToWebhookEndpoint(row)
+}
+
+// FindWebhookEndpointByID looks up an endpoint in any workspace, for the
+// delivery jobs.
+func FindWebhookEndpointByID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (WebhookEndpoint, error) {
+	row, err := db.New().QueryWebhookEndpointByID(ctx, dbtx, id.String())
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	return rowToWebhookEndpoint(row)
+}
+
+func AllWebhookEndpoints(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]WebhookEndpoint, error) {
+	rows, err := db.New().QueryAllWebhookEndpoints(ctx, dbtx, workspaceParam(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToWebhookEndpoints(rows)
+}
+
+// ActiveWebhookEndpoints returns the active endpoints of a workspace, which
+// are the ones notified about its reports.
+func ActiveWebhookEndpoints(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]WebhookEndpoint, error) {
+	rows, err := db.New().QueryActiveWebhookEndpoints(ctx, dbtx, workspaceParam(workspaceID))
+	if err != nil {
+		return nil, err
+	}
+
+	return rowsToWebhookEndpoints(rows)
+}
+
+type CreateWebhookEndpointData struct {
+	URL         string   `validate:"required,http_url,max=2048"`
+	Description string   `validate:"max=200"`
+	Events      []string `validate:"required,min=1,dive,oneof=report.started report.section_completed report.completed report.failed"`
+	WorkspaceID string   `validate:"required,uuid"`
+}
+
+// CreateWebhookEndpoint stores a new active endpoint with a freshly generated
+// signing secret.
+func CreateWebhookEndpoint(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateWebhookEndpointData,
+) (WebhookEndpoint, error) {
+	if err := validate.Struct(data); err != nil {
+		return WebhookEndpoint{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	events := slices.Clone(data.Events)
+	slices.Sort(events)
+
+	row, err := db.New().InsertWebhookEndpoint(ctx, dbtx, db.NewInsertWebhookEndpointParams(
+		data.URL,
+		data.Description,
+		WebhookSecretPrefix+base64.RawURLEncoding.EncodeToString(secret),
+		strings.Join(slices.Compact(events), ","),
+		optionalWorkspaceParam(data.WorkspaceID),
+	))
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	return rowToWebhookEndpoint(row)
+}
+
+func UpdateWebhookEndpointActive(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+	active bool,
+) error {
+	return db.New().UpdateWebhookEndpointActive(ctx, dbtx, db.NewUpdateWebhookEndpointActiveParams(
+		id.String(),
+		workspaceParam(workspaceID),
+		active,
+	))
+}
+
+// DestroyWebhookEndpoint removes an endpoint of the workspace together with
+// its delivery log.
+func DestroyWebhookEndpoint(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) error {
+	endpoint, err := FindWebhookEndpoint(ctx, dbtx, workspaceID, id)
+	if err != nil {
+		return err
+	}
+
+	if err := db.New().DeleteWebhookDeliveriesByEndpointID(ctx, dbtx, endpoint.ID.String()); err != nil {
+		return err
+	}
+
+	return db.New().DeleteWebhookEndpoint(ctx, dbtx, db.NewDeleteWebhookEndpointParams(
+		endpoint.ID.String(),
+		workspaceParam(workspaceID),
+	))
+}
+
+func rowsToWebhookEndpoints(rows []db.WebhookEndpoint) ([]WebhookEndpoint, error) {
+	endpoints := make([]WebhookEndpoint, len(rows))
+	for i, row := range rows {
+		result, err := rowToWebhookEndpoint(row)
+		if err != nil {
+			return nil, err
+		}
+		endpoints[i] = result
+	}
+
+	return endpoints, nil
+}
+
+func rowToWebhookEndpoint(row db.WebhookEndpoint) (WebhookEndpoint, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return WebhookEndpoint{}, err
+	}
+
+	var events []string
+	if row.Events != "" {
+		events = strings.Split(row.Events, ",")
+	}
+
+	return WebhookEndpoint{
+		ID:          id,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		URL:         row.Url,
+		Description: row.Description,
+		Secret:      row.Secret,
+		Events:      events,
+		Active:      row.Active,
+		WorkspaceID: row.WorkspaceID.String,
+	}, nil
+}
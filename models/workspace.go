@@ -0,0 +1,135 @@
+package models
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// DefaultWorkspaceID is the workspace holding everything that was created
+// before there were workspaces.
+var DefaultWorkspaceID = uuid.MustParse("4f9d3c2a-8e1b-4c6f-9a7d-2b5e8f1c3d60")
+
+// Workspace groups the research of a team. Reports, research briefs,
+// batches, watchlists, webhooks and API keys all belong to one workspace and
+// are only visible to its members.
+type Workspace struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	Name      string
+}
+
+func FindWorkspace(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+) (Workspace, error) {
+	row, err := db.New().QueryWorkspaceByID(ctx, dbtx, id.String())
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	return rowToWorkspace(row)
+}
+
+// FindWorkspacesByUserID returns the workspaces the user is a member of,
+// ordered by name.
+func FindWorkspacesByUserID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	userID uuid.UUID,
+) ([]Workspace, error) {
+	rows, err := db.New().QueryWorkspacesByUserID(ctx, dbtx, userID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	workspaces := make([]Workspace, len(rows))
+	for i, row := range rows {
+		result, err := rowToWorkspace(row)
+		if err != nil {
+			return nil, err
+		}
+		workspaces[i] = result
+	}
+
+	return workspaces, nil
+}
+
+type CreateWorkspaceData struct {
+	Name string `validate:"required,max=100"`
+}
+
+func CreateWorkspace(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateWorkspaceData,
+) (Workspace, error) {
+	if err := validate.Struct(data); err != nil {
+		return Workspace{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertWorkspace(ctx, dbtx, db.NewInsertWorkspaceParams(data.Name))
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	return rowToWorkspace(row)
+}
+
+type UpdateWorkspaceNameData struct {
+	ID   uuid.UUID `validate:"required"`
+	Name string    `validate:"required,max=100"`
+}
+
+func UpdateWorkspaceName(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data UpdateWorkspaceNameData,
+) (Workspace, error) {
+	if err := validate.Struct(data); err != nil {
+		return Workspace{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().UpdateWorkspaceName(ctx, dbtx, db.NewUpdateWorkspaceNameParams(
+		data.ID.String(),
+		data.Name,
+	))
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	return rowToWorkspace(row)
+}
+
+// workspaceParam is how a workspace id is passed to the queries scoped to a
+// workspace.
+func workspaceParam(workspaceID uuid.UUID) sql.NullString {
+	return sql.NullString{String: workspaceID.String(), Valid: true}
+}
+
+// optionalWorkspaceParam stores an empty workspace id as NULL, for records
+// created outside of any workspace.
+func optionalWorkspaceParam(workspaceID string) sql.NullString {
+	return sql.NullString{String: workspaceID, Valid: workspaceID != ""}
+}
+
+func rowToWorkspace(row db.Workspace) (Workspace, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return Workspace{}, err
+	}
+
+	return Workspace{
+		ID:        id,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+		Name:      row.Name,
+	}, nil
+}
@@ -0,0 +1,194 @@
+package models
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+// WorkspaceInvitation lets the holder of its token join a workspace with the
+// given role, provided they sign in with the invited email address.
+type WorkspaceInvitation struct {
+	ID              uuid.UUID
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+	WorkspaceID     string
+	Email           string
+	Role            string
+	InvitedByUserID string
+	ExpiresAt       time.Time
+	AcceptedAt      time.Time
+	RevokedAt       time.Time
+}
+
+func (i WorkspaceInvitation) Accepted() bool {
+	return !i.AcceptedAt.IsZero()
+}
+
+func (i WorkspaceInvitation) Revoked() bool {
+	return !i.RevokedAt.IsZero()
+}
+
+func (i WorkspaceInvitation) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
+
+// MatchesEmail reports whether the invitation was sent to email.
+func (i WorkspaceInvitation) MatchesEmail(email string) bool {
+	return i.Email == normalizeEmail(email)
+}
+
+// FindWorkspaceInvitationByToken looks up an invitation by its plaintext
+// token. Tokens are stored hashed like API keys.
+func FindWorkspaceInvitationByToken(
+	ctx context.Context,
+	dbtx db.DBTX,
+	token string,
+) (WorkspaceInvitation, error) {
+	row, err := db.New().QueryWorkspaceInvitationByTokenHash(ctx, dbtx, HashAPIKey(token))
+	if err != nil {
+		return WorkspaceInvitation{}, err
+	}
+
+	return rowToWorkspaceInvitation(row)
+}
+
+// PendingWorkspaceInvitations returns the invitations of a workspace that can
+// still be accepted, newest first.
+func PendingWorkspaceInvitations(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	now time.Time,
+) ([]WorkspaceInvitation, error) {
+	rows, err := db.New().QueryPendingWorkspaceInvitations(
+		ctx,
+		dbtx,
+		db.NewQueryPendingWorkspaceInvitationsParams(workspaceID.String(), now),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	invitations := make([]WorkspaceInvitation, len(rows))
+	for i, row := range rows {
+		result, err := rowToWorkspaceInvitation(row)
+		if err != nil {
+			return nil, err
+		}
+		invitations[i] = result
+	}
+
+	return invitations, nil
+}
+
+type CreateWorkspaceInvitationData struct {
+	WorkspaceID     uuid.UUID `validate:"required"`
+	Email           string    `validate:"required,email,max=255"`
+	Role            string    `validate:"required,oneof=owner editor viewer"`
+	InvitedByUserID string    `validate:"omitempty,uuid"`
+	ExpiresAt       time.Time `validate:"required"`
+}
+
+// CreateWorkspaceInvitation stores an invitation and returns it together
+// with its plaintext token, which is only available here.
+func CreateWorkspaceInvitation(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateWorkspaceInvitationData,
+) (WorkspaceInvitation, string, error) {
+	if err := validate.Struct(data); err != nil {
+		return WorkspaceInvitation{}, "", errors.Join(ErrDomainValidation, err)
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return WorkspaceInvitation{}, "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(secret)
+
+	row, err := db.New().InsertWorkspaceInvitation(ctx, dbtx, db.NewInsertWorkspaceInvitationParams(
+		data.WorkspaceID.String(),
+		normalizeEmail(data.Email),
+		data.Role,
+		HashAPIKey(token),
+		sql.NullString{String: data.InvitedByUserID, Valid: data.InvitedByUserID != ""},
+		data.ExpiresAt,
+	))
+	if err != nil {
+		return WorkspaceInvitation{}, "", err
+	}
+
+	invitation, err := rowToWorkspaceInvitation(row)
+	if err != nil {
+		return WorkspaceInvitation{}, "", err
+	}
+
+	return invitation, token, nil
+}
+
+// AcceptWorkspaceInvitation marks an invitation as accepted. It returns false
+// when it was already accepted or revoked, so an invitation is used once.
+func AcceptWorkspaceInvitation(
+	ctx context.Context,
+	dbtx db.DBTX,
+	id uuid.UUID,
+	acceptedAt time.Time,
+) (bool, error) {
+	affected, err := db.New().AcceptWorkspaceInvitation(ctx, dbtx, db.NewAcceptWorkspaceInvitationParams(
+		id.String(),
+		acceptedAt,
+	))
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+// RevokeWorkspaceInvitation revokes a pending invitation. It returns false
+// when the invitation does not exist in the workspace or is no longer
+// pending.
+func RevokeWorkspaceInvitation(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (bool, error) {
+	affected, err := db.New().RevokeWorkspaceInvitation(ctx, dbtx, db.NewRevokeWorkspaceInvitationParams(
+		id.String(),
+		workspaceID.String(),
+	))
+	if err != nil {
+		return false, err
+	}
+
+	return affected > 0, nil
+}
+
+func rowToWorkspaceInvitation(row db.WorkspaceInvitation) (WorkspaceInvitation, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return WorkspaceInvitation{}, err
+	}
+
+	return WorkspaceInvitation{
+		ID:              id,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+		WorkspaceID:     row.WorkspaceID,
+		Email:           row.Email,
+		Role:            row.Role,
+		InvitedByUserID: row.InvitedByUserID.String,
+		ExpiresAt:       row.ExpiresAt,
+		AcceptedAt:      row.AcceptedAt.Time,
+		RevokedAt:       row.RevokedAt.Time,
+	}, nil
+}
@@ -0,0 +1,232 @@
+package models
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models/internal/db"
+)
+
+const (
+	// WorkspaceRoleOwner can do everything an editor can and also manages
+	// members, invitations and the workspace itself.
+	WorkspaceRoleOwner = "owner"
+	// WorkspaceRoleEditor can start research and manage reports, batches,
+	// watchlists, share links and webhooks.
+	WorkspaceRoleEditor = "editor"
+	// WorkspaceRoleViewer can only read what is in the workspace.
+	WorkspaceRoleViewer = "viewer"
+)
+
+// WorkspaceRoles lists the roles from most to least privileged.
+var WorkspaceRoles = []string{
+	WorkspaceRoleOwner,
+	WorkspaceRoleEditor,
+	WorkspaceRoleViewer,
+}
+
+var workspaceRoleRanks = map[string]int{
+	WorkspaceRoleViewer: 1,
+	WorkspaceRoleEditor: 2,
+	WorkspaceRoleOwner:  3,
+}
+
+// WorkspaceRoleAllows reports whether role grants at least the access of
+// required. Unknown roles grant nothing.
+func WorkspaceRoleAllows(role string, required string) bool {
+	rank, ok := workspaceRoleRanks[role]
+	return ok && rank >= workspaceRoleRanks[required]
+}
+
+type WorkspaceMembership struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	WorkspaceID string
+	UserID      string
+	Role        string
+}
+
+// Allows reports whether the member's role grants at least the access of
+// required.
+func (m WorkspaceMembership) Allows(required string) bool {
+	return WorkspaceRoleAllows(m.Role, required)
+}
+
+// WorkspaceMember is a membership together with the email of the member.
+type WorkspaceMember struct {
+	WorkspaceMembership
+	Email string
+}
+
+func FindWorkspaceMembership(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) (WorkspaceMembership, error) {
+	row, err := db.New().QueryWorkspaceMembershipByID(ctx, dbtx, db.NewQueryWorkspaceMembershipByIDParams(
+		id.String(),
+		workspaceID.String(),
+	))
+	if err != nil {
+		return WorkspaceMembership{}, err
+	}
+
+	return rowToWorkspaceMembership(row)
+}
+
+func FindWorkspaceMembershipByUserID(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	userID uuid.UUID,
+) (WorkspaceMembership, error) {
+	row, err := db.New().QueryWorkspaceMembershipByWorkspaceIDAndUserID(
+		ctx,
+		dbtx,
+		db.NewQueryWorkspaceMembershipByWorkspaceIDAndUserIDParams(
+			workspaceID.String(),
+			userID.String(),
+		),
+	)
+	if err != nil {
+		return WorkspaceMembership{}, err
+	}
+
+	return rowToWorkspaceMembership(row)
+}
+
+// FindFirstWorkspaceMembership returns the oldest membership of the user,
+// used when no workspace has been picked yet.
+func FindFirstWorkspaceMembership(
+	ctx context.Context,
+	dbtx db.DBTX,
+	userID uuid.UUID,
+) (WorkspaceMembership, error) {
+	row, err := db.New().QueryFirstWorkspaceMembershipByUserID(ctx, dbtx, userID.String())
+	if err != nil {
+		return WorkspaceMembership{}, err
+	}
+
+	return rowToWorkspaceMembership(row)
+}
+
+// FindWorkspaceMembers returns the members of a workspace ordered by email.
+func FindWorkspaceMembers(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) ([]WorkspaceMember, error) {
+	rows, err := db.New().QueryWorkspaceMembers(ctx, dbtx, workspaceID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]WorkspaceMember, len(rows))
+	for i, row := range rows {
+		membership, err := rowToWorkspaceMembership(row.WorkspaceMembership)
+		if err != nil {
+			return nil, err
+		}
+		members[i] = WorkspaceMember{
+			WorkspaceMembership: membership,
+			Email:               row.Email,
+		}
+	}
+
+	return members, nil
+}
+
+func CountWorkspaceOwners(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+) (int64, error) {
+	return db.New().CountWorkspaceOwners(ctx, dbtx, workspaceID.String())
+}
+
+type CreateWorkspaceMembershipData struct {
+	WorkspaceID uuid.UUID `validate:"required"`
+	UserID      uuid.UUID `validate:"required"`
+	Role        string    `validate:"required,oneof=owner editor viewer"`
+}
+
+func CreateWorkspaceMembership(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data CreateWorkspaceMembershipData,
+) (WorkspaceMembership, error) {
+	if err := validate.Struct(data); err != nil {
+		return WorkspaceMembership{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().InsertWorkspaceMembership(ctx, dbtx, db.NewInsertWorkspaceMembershipParams(
+		data.WorkspaceID.String(),
+		data.UserID.String(),
+		data.Role,
+	))
+	if err != nil {
+		return WorkspaceMembership{}, err
+	}
+
+	return rowToWorkspaceMembership(row)
+}
+
+type UpdateWorkspaceMembershipRoleData struct {
+	ID          uuid.UUID `validate:"required"`
+	WorkspaceID uuid.UUID `validate:"required"`
+	Role        string    `validate:"required,oneof=owner editor viewer"`
+}
+
+func UpdateWorkspaceMembershipRole(
+	ctx context.Context,
+	dbtx db.DBTX,
+	data UpdateWorkspaceMembershipRoleData,
+) (WorkspaceMembership, error) {
+	if err := validate.Struct(data); err != nil {
+		return WorkspaceMembership{}, errors.Join(ErrDomainValidation, err)
+	}
+
+	row, err := db.New().UpdateWorkspaceMembershipRole(ctx, dbtx, db.NewUpdateWorkspaceMembershipRoleParams(
+		data.ID.String(),
+		data.WorkspaceID.String(),
+		data.Role,
+	))
+	if err != nil {
+		return WorkspaceMembership{}, err
+	}
+
+	return rowToWorkspaceMembership(row)
+}
+
+func DestroyWorkspaceMembership(
+	ctx context.Context,
+	dbtx db.DBTX,
+	workspaceID uuid.UUID,
+	id uuid.UUID,
+) error {
+	return db.New().DeleteWorkspaceMembership(ctx, dbtx, db.NewDeleteWorkspaceMembershipParams(
+		id.String(),
+		workspaceID.String(),
+	))
+}
+
+func rowToWorkspaceMembership(row db.WorkspaceMembership) (WorkspaceMembership, error) {
+	id, err := uuid.Parse(row.ID)
+	if err != nil {
+		return WorkspaceMembership{}, err
+	}
+
+	return WorkspaceMembership{
+		ID:          id,
+		CreatedAt:   row.CreatedAt,
+		UpdatedAt:   row.UpdatedAt,
+		WorkspaceID: row.WorkspaceID,
+		UserID:      row.UserID,
+		Role:        row.Role,
+	}, nil
+}
@@ -0,0 +1,515 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// oidcClockSkew is how far the clocks of the identity provider and this
+// server may drift apart before ID tokens are rejected as expired.
+const oidcClockSkew = time.Minute
+
+// oidcKeyRefreshInterval limits how often the signing keys are fetched
+// again when a token names an unknown key, so forged tokens cannot make us
+// hammer the identity provider.
+const oidcKeyRefreshInterval = time.Minute
+
+var (
+	ErrOIDCDiscovery    = errors.New("oidc: identity provider discovery failed")
+	ErrOIDCExchange     = errors.New("oidc: exchanging the authorization code failed")
+	ErrOIDCInvalidToken = errors.New("oidc: invalid id token")
+)
+
+type OIDCConfig struct {
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// OIDC signs users in through any standards-compliant OpenID Connect
+// identity provider with the authorization code flow and PKCE. The provider
+// configuration is discovered from the issuer on first use.
+type OIDC struct {
+	cfg        OIDCConfig
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	discovery     *oidcDiscovery
+	keys          map[string]crypto.PublicKey
+	keysFetchedAt time.Time
+}
+
+// OIDCClaims holds the claims of a verified ID token that sign-in needs.
+type OIDCClaims struct {
+	Issuer  string
+	Subject string
+	Email   string
+	// EmailVerified is true only when the provider sends an email_verified
+	// claim of true. Tokens without the claim count as unverified.
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
+
+type oidcDiscovery struct {
+	Issuer                        string   `json:"issuer"`
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	JWKSURI                       string   `json:"jwks_uri"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+func NewOIDC(cfg OIDCConfig) *OIDC {
+	return &OIDC{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// NewOIDCSecret returns a random, URL safe value for the state, nonce and
+// PKCE code verifier of a sign-in.
+func NewOIDCSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(secret), nil
+}
+
+// PKCEChallenge derives the S256 code challenge of a code verifier.
+func PKCEChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL of the identity provider's sign-in page.
+func (o *OIDC) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	discovery, err := o.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(discovery.AuthorizationEndpoint)
+	if err != nil {
+		return "", errors.Join(ErrOIDCDiscovery, err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", o.cfg.ClientID)
+	query.Set("redirect_uri", o.cfg.RedirectURL)
+	query.Set("scope", strings.Join(o.cfg.Scopes, " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", PKCEChallenge(verifier))
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange trades an authorization code for the ID token of the user.
+func (o *OIDC) Exchange(ctx context.Context, code, verifier string) (string, error) {
+	discovery, err := o.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {o.cfg.RedirectURL},
+		"client_id":     {o.cfg.ClientID},
+		"code_verifier": {verifier},
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		discovery.TokenEndpoint,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", errors.Join(ErrOIDCExchange, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if o.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(o.cfg.ClientID), url.QueryEscape(o.cfg.ClientSecret))
+	}
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return "", errors.Join(ErrOIDCExchange, err)
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return "", errors.Join(ErrOIDCExchange, fmt.Errorf("status %d: %w", resp.StatusCode, err))
+	}
+
+	if resp.StatusCode != http.StatusOK || body.Error != "" {
+		return "", errors.Join(
+			ErrOIDCExchange,
+			fmt.Errorf("status %d: %s %s", resp.StatusCode, body.Error, body.ErrorDescription),
+		)
+	}
+	if body.IDToken == "" {
+		return "", errors.Join(ErrOIDCExchange, errors.New("response has no id_token"))
+	}
+
+	return body.IDToken, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of
+// an ID token and returns its claims. groupsClaim names the claim listing
+// the user's groups.
+func (o *OIDC) VerifyIDToken(
+	ctx context.Context,
+	raw string,
+	nonce string,
+	groupsClaim string,
+) (OIDCClaims, error) {
+	discovery, err := o.discover(ctx)
+	if err != nil {
+		return OIDCClaims{}, err
+	}
+
+	parts := strings.Split(raw, ".")
+	if len(parts) != 3 {
+		return OIDCClaims{}, invalidToken("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return OIDCClaims{}, invalidToken("malformed header")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return OIDCClaims{}, invalidToken("malformed signature")
+	}
+
+	key, err := o.signingKey(ctx, discovery, header.Kid)
+	if err != nil {
+		return OIDCClaims{}, err
+	}
+
+	if err := verifyJWTSignature(header.Alg, key, parts[0]+"."+parts[1], signature); err != nil {
+		return OIDCClaims{}, err
+	}
+
+	var payload map[string]any
+	if err := decodeJWTPart(parts[1], &payload); err != nil {
+		return OIDCClaims{}, invalidToken("malformed payload")
+	}
+
+	claims := OIDCClaims{
+		Issuer:        stringClaim(payload, "iss"),
+		Subject:       stringClaim(payload, "sub"),
+		Email:         stringClaim(payload, "email"),
+		EmailVerified: payload["email_verified"] == true,
+		Name:          stringClaim(payload, "name"),
+		Groups:        stringsClaim(payload, groupsClaim),
+	}
+
+	if claims.Issuer != discovery.Issuer {
+		return OIDCClaims{}, invalidToken("unexpected issuer")
+	}
+	if claims.Subject == "" {
+		return OIDCClaims{}, invalidToken("missing subject")
+	}
+
+	audience := stringsClaim(payload, "aud")
+	if !slices.Contains(audience, o.cfg.ClientID) {
+		return OIDCClaims{}, invalidToken("token is not meant for this client")
+	}
+	if azp := stringClaim(payload, "azp"); len(audience) > 1 && azp != o.cfg.ClientID {
+		return OIDCClaims{}, invalidToken("token is not meant for this client")
+	}
+
+	expiresAt, ok := payload["exp"].(float64)
+	if !ok || time.Now().Add(-oidcClockSkew).After(time.Unix(int64(expiresAt), 0)) {
+		return OIDCClaims{}, invalidToken("token has expired")
+	}
+
+	if stringClaim(payload, "nonce") != nonce {
+		return OIDCClaims{}, invalidToken("nonce does not match")
+	}
+
+	return claims, nil
+}
+
+func (o *OIDC) discover(ctx context.Context) (oidcDiscovery, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.discovery != nil {
+		return *o.discovery, nil
+	}
+
+	var discovery oidcDiscovery
+	wellKnown := strings.TrimSuffix(o.cfg.IssuerURL, "/") + "/.well-known/openid-configuration"
+	if err := o.getJSON(ctx, wellKnown, &discovery); err != nil {
+		return oidcDiscovery{}, errors.Join(ErrOIDCDiscovery, err)
+	}
+
+	switch {
+	case strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(o.cfg.IssuerURL, "/"):
+		return oidcDiscovery{}, errors.Join(
+			ErrOIDCDiscovery,
+			fmt.Errorf("issuer %q does not match the configured %q", discovery.Issuer, o.cfg.IssuerURL),
+		)
+	case discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "":
+		return oidcDiscovery{}, errors.Join(ErrOIDCDiscovery, errors.New("incomplete provider metadata"))
+	case len(discovery.CodeChallengeMethodsSupported) > 0 &&
+		!slices.Contains(discovery.CodeChallengeMethodsSupported, "S256"):
+		return oidcDiscovery{}, errors.Join(ErrOIDCDiscovery, errors.New("provider does not support PKCE with S256"))
+	}
+
+	o.discovery = &discovery
+
+	return discovery, nil
+}
+
+// signingKey returns the key with the given id, fetching the provider's
+// keys again when it is unknown. Tokens without a key id are accepted when
+// the provider publishes a single key.
+func (o *OIDC) signingKey(
+	ctx context.Context,
+	discovery oidcDiscovery,
+	kid string,
+) (crypto.PublicKey, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	lookup := func() (crypto.PublicKey, bool) {
+		if kid == "" && len(o.keys) == 1 {
+			for _, key := range o.keys {
+				return key, true
+			}
+		}
+		key, ok := o.keys[kid]
+		return key, ok
+	}
+
+	if key, ok := lookup(); ok {
+		return key, nil
+	}
+
+	if time.Since(o.keysFetchedAt) < oidcKeyRefreshInterval {
+		return nil, invalidToken("unknown signing key")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := o.getJSON(ctx, discovery.JWKSURI, &jwks); err != nil {
+		return nil, errors.Join(ErrOIDCDiscovery, err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	o.keys = keys
+	o.keysFetchedAt = time.Now()
+
+	if key, ok := lookup(); ok {
+		return key, nil
+	}
+
+	return nil, invalidToken("unknown signing key")
+}
+
+func (o *OIDC) getJSON(ctx context.Context, target string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: status %d", target, resp.StatusCode)
+	}
+
+	return json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(v)
+}
+
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exponent := new(big.Int).SetBytes(e)
+		if !exponent.IsInt64() || exponent.Int64() > 1<<31-1 {
+			return nil, errors.New("rsa exponent too large")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exponent.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		key := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(key.X, key.Y) {
+			return nil, errors.New("ec point is not on the curve")
+		}
+		return key, nil
+	}
+
+	return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+}
+
+func verifyJWTSignature(alg string, key crypto.PublicKey, signed string, signature []byte) error {
+	var hash crypto.Hash
+	switch alg {
+	case "RS256", "ES256":
+		hash = crypto.SHA256
+	case "RS384", "ES384":
+		hash = crypto.SHA384
+	case "RS512", "ES512":
+		hash = crypto.SHA512
+	default:
+		// Never accept "none" or symmetric algorithms keyed with public data.
+		return invalidToken(fmt.Sprintf("unsupported signing algorithm %q", alg))
+	}
+
+	hasher := hash.New()
+	hasher.Write([]byte(signed))
+	digest := hasher.Sum(nil)
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		if !strings.HasPrefix(alg, "RS") {
+			return invalidToken("algorithm does not match the signing key")
+		}
+		if err := rsa.VerifyPKCS1v15(key, hash, digest, signature); err != nil {
+			return invalidToken("bad signature")
+		}
+	case *ecdsa.PublicKey:
+		size := (key.Curve.Params().BitSize + 7) / 8
+		if !strings.HasPrefix(alg, "ES") || len(signature) != 2*size {
+			return invalidToken("algorithm does not match the signing key")
+		}
+		r := new(big.Int).SetBytes(signature[:size])
+		s := new(big.Int).SetBytes(signature[size:])
+		if !ecdsa.Verify(key, digest, r, s) {
+			return invalidToken("bad signature")
+		}
+	default:
+		return invalidToken("unsupported signing key")
+	}
+
+	return nil
+}
+
+func decodeJWTPart(part string, v any) error {
+	decoded, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(decoded, v)
+}
+
+func stringClaim(claims map[string]any, name string) string {
+	value, _ := claims[name].(string)
+	return value
+}
+
+// stringsClaim reads a claim that holds either a single string or a list of
+// strings, as aud and group claims do.
+func stringsClaim(claims map[string]any, name string) []string {
+	switch value := claims[name].(type) {
+	case string:
+		return []string{value}
+	case []any:
+		values := make([]string, 0, len(value))
+		for _, v := range value {
+			if s, ok := v.(string); ok {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+
+	return nil
+}
+
+func invalidToken(reason string) error {
+	return fmt.Errorf("%w: %s", ErrOIDCInvalidToken, reason)
+}
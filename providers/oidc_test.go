@@ -0,0 +1,299 @@
+package providers
+
+import (
+	"context"
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testClientID = "plyo"
+	testKeyID    = "test-key"
+	testNonce    = "nonce-1"
+)
+
+// newTestIdentityProvider serves the discovery document and the signing key
+// of an identity provider and returns an OIDC client configured for it.
+func newTestIdentityProvider(t *testing.T, key *rsa.PrivateKey) (*OIDC, string) {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(oidcDiscovery{
+			Issuer:                server.URL,
+			AuthorizationEndpoint: server.URL + "/authorize",
+			TokenEndpoint:         server.URL + "/token",
+			JWKSURI:               server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []jsonWebKey{{
+				Kty: "RSA",
+				Kid: testKeyID,
+				Use: "sig",
+				N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	return NewOIDC(OIDCConfig{IssuerURL: server.URL, ClientID: testClientID}), server.URL
+}
+
+func encodeJWTPart(t *testing.T, v any) string {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func signRS256(t *testing.T, key *rsa.PrivateKey, signed string) []byte {
+	t.Helper()
+
+	digest := sha256.Sum256([]byte(signed))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signature
+}
+
+func TestVerifyIDToken(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, issuer := newTestIdentityProvider(t, key)
+
+	validClaims := func() map[string]any {
+		return map[string]any{
+			"iss":            issuer,
+			"sub":            "user-1",
+			"aud":            testClientID,
+			"exp":            time.Now().Add(time.Hour).Unix(),
+			"nonce":          testNonce,
+			"email":          "ada@example.com",
+			"email_verified": true,
+		}
+	}
+
+	// token builds an ID token from a header and claims, signed by sign.
+	token := func(header map[string]any, claims map[string]any, sign func(signed string) []byte) string {
+		signed := encodeJWTPart(t, header) + "." + encodeJWTPart(t, claims)
+		return signed + "." + base64.RawURLEncoding.EncodeToString(sign(signed))
+	}
+	rs256 := map[string]any{"alg": "RS256", "kid": testKeyID}
+	signedBy := func(key *rsa.PrivateKey) func(string) []byte {
+		return func(signed string) []byte { return signRS256(t, key, signed) }
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:  "valid token",
+			token: token(rs256, validClaims(), signedBy(key)),
+		},
+		{
+			name:    "signed by another key",
+			token:   token(rs256, validClaims(), signedBy(otherKey)),
+			wantErr: true,
+		},
+		{
+			name: "claims changed after signing",
+			token: func() string {
+				parts := strings.Split(token(rs256, validClaims(), signedBy(key)), ".")
+				claims := validClaims()
+				claims["sub"] = "admin"
+				parts[1] = encodeJWTPart(t, claims)
+				return strings.Join(parts, ".")
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "alg none",
+			token:   token(map[string]any{"alg": "none", "kid": testKeyID}, validClaims(), func(string) []byte { return nil }),
+			wantErr: true,
+		},
+		{
+			name: "alg HS256 keyed with the public key",
+			token: token(map[string]any{"alg": "HS256", "kid": testKeyID}, validClaims(), func(signed string) []byte {
+				mac := hmac.New(sha256.New, key.N.Bytes())
+				mac.Write([]byte(signed))
+				return mac.Sum(nil)
+			}),
+			wantErr: true,
+		},
+		{
+			name:    "alg ES256 with an RSA key",
+			token:   token(map[string]any{"alg": "ES256", "kid": testKeyID}, validClaims(), signedBy(key)),
+			wantErr: true,
+		},
+		{
+			name:    "unknown key id",
+			token:   token(map[string]any{"alg": "RS256", "kid": "other"}, validClaims(), signedBy(key)),
+			wantErr: true,
+		},
+		{
+			name: "wrong audience",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = "another-client"
+				return token(rs256, claims, signedBy(key))
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "several audiences without azp",
+			token: func() string {
+				claims := validClaims()
+				claims["aud"] = []string{testClientID, "another-client"}
+				return token(rs256, claims, signedBy(key))
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "wrong issuer",
+			token: func() string {
+				claims := validClaims()
+				claims["iss"] = "https://attacker.example"
+				return token(rs256, claims, signedBy(key))
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "expired",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-2 * oidcClockSkew).Unix()
+				return token(rs256, claims, signedBy(key))
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "expired within the clock skew",
+			token: func() string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-oidcClockSkew / 2).Unix()
+				return token(rs256, claims, signedBy(key))
+			}(),
+		},
+		{
+			name: "no expiry",
+			token: func() string {
+				claims := validClaims()
+				delete(claims, "exp")
+				return token(rs256, claims, signedBy(key))
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "nonce mismatch",
+			token: func() string {
+				claims := validClaims()
+				claims["nonce"] = "nonce-2"
+				return token(rs256, claims, signedBy(key))
+			}(),
+			wantErr: true,
+		},
+		{
+			name:    "malformed token",
+			token:   "not-a-token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := provider.VerifyIDToken(context.Background(), tt.token, testNonce, "groups")
+			if tt.wantErr {
+				if !errors.Is(err, ErrOIDCInvalidToken) {
+					t.Fatalf("VerifyIDToken() error = %v, want %v", err, ErrOIDCInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("VerifyIDToken() error = %v", err)
+			}
+			if claims.Subject != "user-1" || claims.Email != "ada@example.com" {
+				t.Fatalf("VerifyIDToken() claims = %+v", claims)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenEmailVerified(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider, issuer := newTestIdentityProvider(t, key)
+
+	tests := []struct {
+		name          string
+		emailVerified any
+		want          bool
+	}{
+		{name: "true", emailVerified: true, want: true},
+		{name: "false", emailVerified: false, want: false},
+		{name: "missing", emailVerified: nil, want: false},
+		{name: "string true", emailVerified: "true", want: false},
+		{name: "number", emailVerified: 1, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims := map[string]any{
+				"iss":   issuer,
+				"sub":   "user-1",
+				"aud":   testClientID,
+				"exp":   time.Now().Add(time.Hour).Unix(),
+				"nonce": testNonce,
+				"email": "ada@example.com",
+			}
+			if tt.emailVerified != nil {
+				claims["email_verified"] = tt.emailVerified
+			}
+
+			signed := encodeJWTPart(t, map[string]any{"alg": "RS256", "kid": testKeyID}) + "." + encodeJWTPart(t, claims)
+			raw := signed + "." + base64.RawURLEncoding.EncodeToString(signRS256(t, key, signed))
+
+			got, err := provider.VerifyIDToken(context.Background(), raw, testNonce, "groups")
+			if err != nil {
+				t.Fatalf("VerifyIDToken() error = %v", err)
+			}
+			if got.EmailVerified != tt.want {
+				t.Fatalf("EmailVerified = %v, want %v", got.EmailVerified, tt.want)
+			}
+		})
+	}
+}
@@ -4,9 +4,13 @@ package providers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"time"
 
+	"github.com/mbvlabs/plyo-hackathon/models"
 	"github.com/mbvlabs/plyo-hackathon/tools"
 	"github.com/openai/openai-go/v2"
 	"github.com/openai/openai-go/v2/option"
@@ -54,11 +58,14 @@ func (c *Client) retryWithBackoff(ctx context.Context, fn func() error, maxRetri
 	return nil
 }
 
+// Prompt has the model answer the prompts, calling the tools of the toolset
+// as it sees fit. A tool is not called more often in the run than its quota
+// allows; calls beyond it are answered with a message telling the model so.
 func (c *Client) Prompt(
 	ctx context.Context,
 	model Model,
 	systemPrompt, userPrompt string,
-	tools map[string]tools.Tooler,
+	toolset tools.Toolset,
 	responseFormat *openai.ResponseFormatJSONSchemaJSONSchemaParam,
 ) (string, error) {
 	messages := []openai.ChatCompletionMessageParamUnion{}
@@ -69,11 +76,11 @@ func (c *Client) Prompt(
 
 	messages = append(messages, openai.UserMessage(userPrompt))
 
-	agentTools := make([]openai.ChatCompletionToolUnionParam, len(tools))
-	i := 0
-	for _, tool := range tools {
+	names := toolset.Names()
+	agentTools := make([]openai.ChatCompletionToolUnionParam, len(names))
+	for i, name := range names {
+		tool, _ := toolset.Tool(name)
 		agentTools[i] = tool.GetFunctionStructure()
-		i++
 	}
 
 	params := openai.ChatCompletionNewParams{
@@ -109,17 +116,10 @@ func (c *Client) Prompt(
 	}
 
 	params.Messages = append(params.Messages, resp.Choices[0].Message.ToParam())
+	calls := map[string]int{}
 	for _, toolCall := range toolCalls {
 		if toolCall.Function.Name != "" {
-			var args map[string]any
-			err := json.Unmarshal([]byte(toolCall.Function.Arguments), &args)
-			if err != nil {
-				return "", fmt.Errorf("failed to unmarshal tool arguments: %w", err)
-			}
-
-			result, err := tools[toolCall.Function.Name].Execute(
-				json.RawMessage(toolCall.Function.Arguments),
-			)
+			result, err := c.callTool(ctx, toolset, calls, toolCall.Function.Name, toolCall.Function.Arguments)
 			if err != nil {
 				return "", fmt.Errorf("tool execution failed: %w", err)
 			}
@@ -139,3 +139,75 @@ func (c *Client) Prompt(
 
 	return resp.Choices[0].Message.Content, nil
 }
+
+// callTool runs a tool call of the model, counting it in calls, and records
+// it in the toolset's call log. Calls of tools the toolset does not have, or
+// beyond a tool's quota, are not run; the model is told why instead, as it
+// is of tool failures, so it can go on with what it has. Arguments that are
+// not JSON end the run, like any other error.
+func (c *Client) callTool(
+	ctx context.Context,
+	toolset tools.Toolset,
+	calls map[string]int,
+	name, arguments string,
+) (string, error) {
+	call := tools.Call{Tool: name, Arguments: arguments}
+
+	var args map[string]any
+	if err := json.Unmarshal([]byte(arguments), &args); err != nil {
+		call.Status, call.Error = models.ToolCallStatusErrored, "invalid arguments: "+err.Error()
+		toolset.Record(ctx, call)
+		return "", fmt.Errorf("failed to unmarshal tool arguments: %w", err)
+	}
+
+	tool, ok := toolset.Tool(name)
+	if !ok {
+		slog.WarnContext(ctx, "model called a tool it does not have", "tool", name)
+		result := fmt.Sprintf(
+			"Not run: %s is not one of your tools. Use one of: %s.",
+			name,
+			strings.Join(toolset.Names(), ", "),
+		)
+		call.Status, call.Error = models.ToolCallStatusRefused, "not one of the agent's tools"
+		call.ResponseSize = len(result)
+		toolset.Record(ctx, call)
+		return result, nil
+	}
+
+	if quota := toolset.Quota(name); quota > 0 && calls[name] >= quota {
+		slog.InfoContext(ctx, "tool quota exhausted", "tool", name, "quota", quota)
+		result := fmt.Sprintf(
+			"Not run: you have used all %d calls of %s allowed in this run. Continue with the results you already have, or use another tool.",
+			quota,
+			name,
+		)
+		call.Status, call.Error = models.ToolCallStatusRefused, fmt.Sprintf("quota of %d calls used up", quota)
+		call.ResponseSize = len(result)
+		toolset.Record(ctx, call)
+		return result, nil
+	}
+	calls[name]++
+
+	execCtx, serving := tools.WithServing(ctx)
+	start := time.Now()
+	result, err := tool.Execute(execCtx, json.RawMessage(arguments))
+	call.Latency = time.Since(start)
+	call.Serving = *serving
+
+	var failure *tools.Failure
+	switch {
+	case err == nil:
+		call.Status = models.ToolCallStatusSucceeded
+	case errors.As(err, &failure):
+		call.Status, call.Error = models.ToolCallStatusFailed, failure.Error()
+		result, err = failure.Message, nil
+	case ctx.Err() != nil:
+		call.Status, call.Error = models.ToolCallStatusCanceled, err.Error()
+	default:
+		call.Status, call.Error = models.ToolCallStatusErrored, err.Error()
+	}
+	call.ResponseSize = len(result)
+	toolset.Record(ctx, call)
+
+	return result, err
+}
@@ -3,10 +3,13 @@ package cookies
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"strings"
 
 	"github.com/mbvlabs/plyo-hackathon/config"
 
+	"github.com/google/uuid"
+	"github.com/gorilla/sessions"
 	"github.com/labstack/echo-contrib/session"
 	"github.com/labstack/echo/v4"
 )
@@ -27,11 +30,19 @@ type appKey string
 
 const (
 	isAuthenticated = "is_authenticated"
+	userID          = "user_id"
+	workspaceID     = "workspace_id"
 )
 
+// authenticatedSessionMaxAge is how long a login lasts without signing in
+// again.
+const authenticatedSessionMaxAge = 14 * 24 * 60 * 60
+
 type App struct {
 	echo.Context
 	IsAuthenticated bool
+	UserID          uuid.UUID
+	WorkspaceID     uuid.UUID
 	FlashMessages   []FlashMessage
 }
 
@@ -54,6 +65,66 @@ func GetApp(c echo.Context) App {
 	if _, ok := sess.Values[isAuthenticated].(bool); ok {
 		app.IsAuthenticated = true
 	}
+	if id, ok := sess.Values[userID].(uuid.UUID); ok {
+		app.UserID = id
+	}
+	if id, ok := sess.Values[workspaceID].(uuid.UUID); ok {
+		app.WorkspaceID = id
+	}
 
 	return app
 }
+
+// CreateAuthenticatedSession signs the user in by storing their id in the
+// encrypted session cookie.
+func CreateAuthenticatedSession(c echo.Context, id uuid.UUID) error {
+	sess, err := session.Get(authenticatedSessionName, c)
+	if err != nil {
+		return err
+	}
+
+	sess.Options = authenticatedSessionOptions()
+	sess.Values[isAuthenticated] = true
+	sess.Values[userID] = id
+
+	return sess.Save(c.Request(), c.Response())
+}
+
+// DestroyAuthenticatedSession signs the current user out.
+func DestroyAuthenticatedSession(c echo.Context) error {
+	sess, err := session.Get(authenticatedSessionName, c)
+	if err != nil {
+		return err
+	}
+
+	sess.Options = &sessions.Options{Path: "/", MaxAge: -1}
+	delete(sess.Values, isAuthenticated)
+	delete(sess.Values, userID)
+	delete(sess.Values, workspaceID)
+
+	return sess.Save(c.Request(), c.Response())
+}
+
+// SetCurrentWorkspace remembers the workspace the signed-in user is working
+// in for the rest of their session.
+func SetCurrentWorkspace(c echo.Context, id uuid.UUID) error {
+	sess, err := session.Get(authenticatedSessionName, c)
+	if err != nil {
+		return err
+	}
+
+	sess.Options = authenticatedSessionOptions()
+	sess.Values[workspaceID] = id
+
+	return sess.Save(c.Request(), c.Response())
+}
+
+func authenticatedSessionOptions() *sessions.Options {
+	return &sessions.Options{
+		Path:     "/",
+		MaxAge:   authenticatedSessionMaxAge,
+		HttpOnly: true,
+		Secure:   config.App.Env == config.ProdEnvironment,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
@@ -0,0 +1,91 @@
+package cookies
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo-contrib/session"
+	"github.com/labstack/echo/v4"
+
+	"github.com/mbvlabs/plyo-hackathon/config"
+)
+
+var oidcSessionName = fmt.Sprintf(
+	"oidc-%s-%s",
+	strings.ToLower(config.App.ProjectName),
+	strings.ToLower(config.App.Env),
+)
+
+// oidcSessionMaxAge is how long a user has to sign in at the identity
+// provider.
+const oidcSessionMaxAge = 10 * 60
+
+const (
+	oidcState    = "state"
+	oidcNonce    = "nonce"
+	oidcVerifier = "verifier"
+	oidcNext     = "next"
+)
+
+// OIDCFlow holds the secrets of a single sign-on in progress, kept in the
+// encrypted session cookie until the identity provider redirects back.
+type OIDCFlow struct {
+	State    string
+	Nonce    string
+	Verifier string
+	Next     string
+}
+
+func SetOIDCFlow(c echo.Context, flow OIDCFlow) error {
+	sess, err := session.Get(oidcSessionName, c)
+	if err != nil {
+		return err
+	}
+
+	sess.Options = oidcSessionOptions(oidcSessionMaxAge)
+	sess.Values[oidcState] = flow.State
+	sess.Values[oidcNonce] = flow.Nonce
+	sess.Values[oidcVerifier] = flow.Verifier
+	sess.Values[oidcNext] = flow.Next
+
+	return sess.Save(c.Request(), c.Response())
+}
+
+// PopOIDCFlow returns the single sign-on in progress and forgets it, so a
+// callback can only be completed once. It returns false when there is none.
+func PopOIDCFlow(c echo.Context) (OIDCFlow, bool, error) {
+	sess, err := session.Get(oidcSessionName, c)
+	if err != nil {
+		return OIDCFlow{}, false, err
+	}
+
+	flow := OIDCFlow{}
+	flow.State, _ = sess.Values[oidcState].(string)
+	flow.Nonce, _ = sess.Values[oidcNonce].(string)
+	flow.Verifier, _ = sess.Values[oidcVerifier].(string)
+	flow.Next, _ = sess.Values[oidcNext].(string)
+
+	for _, key := range []string{oidcState, oidcNonce, oidcVerifier, oidcNext} {
+		delete(sess.Values, key)
+	}
+	sess.Options = oidcSessionOptions(-1)
+	if err := sess.Save(c.Request(), c.Response()); err != nil {
+		return OIDCFlow{}, false, err
+	}
+
+	return flow, flow.State != "", nil
+}
+
+func oidcSessionOptions(maxAge int) *sessions.Options {
+	// Lax, so the cookie comes along when the identity provider redirects
+	// back.
+	return &sessions.Options{
+		Path:     "/",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   config.App.Env == config.ProdEnvironment,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
@@ -0,0 +1,128 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// APIKeyContextKey is the echo context key holding the models.APIKey of an
+// authenticated API request.
+const APIKeyContextKey = "api_key"
+
+// APIKeyAuth requires a valid API key on every request whose path starts
+// with prefix. The key is read from "Authorization: Bearer <key>" or the
+// "X-API-Key" header.
+func APIKeyAuth(conn *sql.DB, prefix string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !strings.HasPrefix(c.Request().URL.Path, prefix) {
+				return next(c)
+			}
+
+			token := c.Request().Header.Get("X-API-Key")
+			if auth := c.Request().Header.Get(echo.HeaderAuthorization); token == "" && auth != "" {
+				scheme, value, found := strings.Cut(auth, " ")
+				if found && strings.EqualFold(scheme, "bearer") {
+					token = strings.TrimSpace(value)
+				}
+			}
+
+			if token == "" {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "missing API key"})
+			}
+
+			key, err := models.FindActiveAPIKeyByToken(c.Request().Context(), conn, token)
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusUnauthorized, map[string]string{"error": "invalid API key"})
+			}
+			if err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to look up api key",
+					"error", err,
+				)
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal error"})
+			}
+
+			if err := models.TouchAPIKey(c.Request().Context(), conn, key.ID, time.Now()); err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to record api key usage",
+					"error", err,
+					"api_key_id", key.ID,
+				)
+			}
+
+			c.Set(APIKeyContextKey, key)
+
+			// A key acts for its owner with the role they hold in the
+			// workspace it was created for, so it grants no more than their
+			// membership and stops working once they leave.
+			userID, err := uuid.Parse(key.UserID)
+			if err != nil {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "API key has no owner"})
+			}
+			user, err := models.FindUser(c.Request().Context(), conn, userID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "API key owner has no account"})
+			}
+			if err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to look up api key owner",
+					"error", err,
+					"api_key_id", key.ID,
+				)
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal error"})
+			}
+
+			workspaceID, err := uuid.Parse(key.WorkspaceID)
+			if err != nil {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "API key has no workspace"})
+			}
+			membership, err := models.FindWorkspaceMembershipByUserID(
+				c.Request().Context(),
+				conn,
+				workspaceID,
+				user.ID,
+			)
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "API key owner is not a member of its workspace"})
+			}
+			if err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to look up api key owner membership",
+					"error", err,
+					"api_key_id", key.ID,
+				)
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal error"})
+			}
+			workspace, err := models.FindWorkspace(c.Request().Context(), conn, workspaceID)
+			if err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to look up api key workspace",
+					"error", err,
+					"api_key_id", key.ID,
+				)
+				return c.JSON(http.StatusInternalServerError, map[string]string{"error": "internal error"})
+			}
+
+			c.Set(UserContextKey, user)
+			c.Set(WorkspaceContextKey, workspace)
+			c.Set(WorkspaceMembershipContextKey, membership)
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+	"github.com/starfederation/datastar-go/datastar"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+)
+
+// UserContextKey is the echo context key holding the models.User making the
+// request, signed in through the session or through an API key.
+const UserContextKey = "user"
+
+// RequireUser requires a signed-in user on every request for which public
+// returns false. Browsers are sent to loginPath, with the requested page in
+// the next query parameter so they return to it after signing in.
+func RequireUser(
+	conn *sql.DB,
+	loginPath string,
+	public func(path string) bool,
+) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if public(c.Request().URL.Path) {
+				return next(c)
+			}
+
+			app := cookies.GetApp(c)
+			if !app.IsAuthenticated {
+				return redirectToLogin(c, loginPath)
+			}
+
+			user, err := models.FindUser(c.Request().Context(), conn, app.UserID)
+			if errors.Is(err, sql.ErrNoRows) {
+				if err := cookies.DestroyAuthenticatedSession(c); err != nil {
+					return err
+				}
+				return redirectToLogin(c, loginPath)
+			}
+			if err != nil {
+				slog.ErrorContext(
+					c.Request().Context(),
+					"failed to look up signed in user",
+					"error", err,
+					"user_id", app.UserID,
+				)
+				return c.String(http.StatusInternalServerError, "internal error")
+			}
+
+			c.Set(UserContextKey, user)
+
+			return next(c)
+		}
+	}
+}
+
+func redirectToLogin(c echo.Context, loginPath string) error {
+	target := loginPath
+	if c.Request().Method == http.MethodGet {
+		target += "?" + url.Values{"next": {c.Request().URL.RequestURI()}}.Encode()
+	}
+
+	// Datastar requests expect server-sent events rather than a redirect
+	// response.
+	if c.Request().Header.Get("Datastar-Request") == "true" {
+		return datastar.NewSSE(c.Response(), c.Request()).Redirect(target)
+	}
+
+	if c.Request().Method != http.MethodGet {
+		return c.String(http.StatusUnauthorized, "sign in to continue")
+	}
+
+	return c.Redirect(http.StatusSeeOther, target)
+}
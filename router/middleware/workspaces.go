@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/starfederation/datastar-go/datastar"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/cookies"
+)
+
+const (
+	// WorkspaceContextKey is the echo context key holding the models.Workspace
+	// every query of the request is scoped to.
+	WorkspaceContextKey = "workspace"
+	// WorkspaceMembershipContextKey is the echo context key holding the
+	// models.WorkspaceMembership that grants access to the workspace.
+	WorkspaceMembershipContextKey = "workspace_membership"
+)
+
+// RequireWorkspace resolves the workspace a signed-in user is working in:
+// the one picked in their session, or their oldest membership otherwise.
+// Users without any membership are sent to workspacesPath, unless optional
+// returns true for the requested path. Requests without a user, and API
+// requests whose key already decided the workspace, pass through.
+func RequireWorkspace(
+	conn *sql.DB,
+	workspacesPath string,
+	optional func(path string) bool,
+) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if _, ok := c.Get(WorkspaceContextKey).(models.Workspace); ok {
+				return next(c)
+			}
+
+			user, ok := c.Get(UserContextKey).(models.User)
+			if !ok {
+				return next(c)
+			}
+
+			ctx := c.Request().Context()
+
+			membership, err := models.FindWorkspaceMembershipByUserID(
+				ctx,
+				conn,
+				cookies.GetApp(c).WorkspaceID,
+				user.ID,
+			)
+			if errors.Is(err, sql.ErrNoRows) {
+				membership, err = models.FindFirstWorkspaceMembership(ctx, conn, user.ID)
+			}
+			if errors.Is(err, sql.ErrNoRows) {
+				if optional(c.Request().URL.Path) {
+					return next(c)
+				}
+				return redirect(c, workspacesPath)
+			}
+			if err != nil {
+				slog.ErrorContext(
+					ctx,
+					"failed to look up workspace membership",
+					"error", err,
+					"user_id", user.ID,
+				)
+				return c.String(http.StatusInternalServerError, "internal error")
+			}
+
+			workspace, err := models.FindWorkspace(ctx, conn, uuid.MustParse(membership.WorkspaceID))
+			if err != nil {
+				slog.ErrorContext(
+					ctx,
+					"failed to look up workspace",
+					"error", err,
+					"workspace_id", membership.WorkspaceID,
+				)
+				return c.String(http.StatusInternalServerError, "internal error")
+			}
+
+			c.Set(WorkspaceContextKey, workspace)
+			c.Set(WorkspaceMembershipContextKey, membership)
+
+			return next(c)
+		}
+	}
+}
+
+// RequireWorkspaceRole only lets members whose role grants at least role
+// through. Browsers are sent back to the page they came from with an error
+// flash; API clients get a 403.
+func RequireWorkspaceRole(role string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			membership, _ := c.Get(WorkspaceMembershipContextKey).(models.WorkspaceMembership)
+			if membership.Allows(role) {
+				return next(c)
+			}
+
+			if _, ok := c.Get(APIKeyContextKey).(models.APIKey); ok {
+				return c.JSON(http.StatusForbidden, map[string]string{"error": "insufficient workspace role"})
+			}
+
+			if err := cookies.AddFlash(
+				c,
+				cookies.FlashError,
+				fmt.Sprintf("You need the %s role in this workspace to do that", role),
+			); err != nil {
+				return err
+			}
+
+			return redirect(c, refererPath(c))
+		}
+	}
+}
+
+func redirect(c echo.Context, target string) error {
+	// Datastar requests expect server-sent events rather than a redirect
+	// response.
+	if c.Request().Header.Get("Datastar-Request") == "true" {
+		return datastar.NewSSE(c.Response(), c.Request()).Redirect(target)
+	}
+
+	return c.Redirect(http.StatusSeeOther, target)
+}
+
+// refererPath returns the path of the page the request came from, or "/"
+// when it is missing. Only the path is kept, and like the login's next
+// parameter it must be a path on this site: "//host" and "/\host" would be
+// read by browsers as another host.
+func refererPath(c echo.Context) string {
+	referer, err := url.Parse(c.Request().Referer())
+	if err != nil {
+		return "/"
+	}
+
+	path := referer.Path
+	if !strings.HasPrefix(path, "/") || strings.HasPrefix(path, "//") || strings.HasPrefix(path, "/\\") {
+		return "/"
+	}
+
+	if referer.RawQuery != "" {
+		return path + "?" + referer.RawQuery
+	}
+
+	return path
+}
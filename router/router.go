@@ -12,6 +12,8 @@ import (
 
 	"github.com/mbvlabs/plyo-hackathon/config"
 	"github.com/mbvlabs/plyo-hackathon/controllers"
+	"github.com/mbvlabs/plyo-hackathon/database"
+	"github.com/mbvlabs/plyo-hackathon/router/middleware"
 	"github.com/mbvlabs/plyo-hackathon/router/cookies"
 	"github.com/mbvlabs/plyo-hackathon/router/routes"
 
@@ -30,6 +32,7 @@ type Router struct {
 
 func New(
 	controllers controllers.Controllers,
+	db database.SQLite,
 ) (*Router, error) {
 	gob.Register(uuid.UUID{})
 	gob.Register(cookies.FlashMessage{})
@@ -72,6 +75,9 @@ func New(
 
 		echomw.Recover(),
 		echomw.Logger(),
+		middleware.APIKeyAuth(db.Conn(), routes.APIV1RoutePrefix),
+		middleware.RequireUser(db.Conn(), routes.SessionNew.Path, isPublicPath),
+		middleware.RequireWorkspace(db.Conn(), routes.WorkspaceIndex.Path, isWorkspaceOptionalPath),
 	)
 
 	return &Router{
@@ -170,6 +176,37 @@ func getHandlerFunc(controller any, methodName string) echo.HandlerFunc {
 	}
 }
 
+// isPublicPath reports whether a path is reachable without signing in. The
+// JSON API authenticates with API keys instead and share links carry their
+// own signed token.
+func isPublicPath(path string) bool {
+	switch path {
+	case routes.SessionNew.Path, routes.SessionDestroy.Path, routes.RegistrationNew.Path, routes.Health.Path,
+		routes.OIDCStart.Path, routes.OIDCCallback.Path:
+		return true
+	}
+
+	for _, prefix := range []string{
+		routes.AssetsRoutePrefix,
+		routes.APIRoutePrefix,
+		routes.SharedRoutePrefix + "/",
+	} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isWorkspaceOptionalPath reports whether a signed-in user without any
+// workspace can reach a path, so they can create one or accept an
+// invitation.
+func isWorkspaceOptionalPath(path string) bool {
+	return path == routes.WorkspaceIndex.Path ||
+		strings.HasPrefix(path, routes.InvitationsRoutePrefix+"/")
+}
+
 func registerAppContext(
 	next echo.HandlerFunc,
 ) echo.HandlerFunc {
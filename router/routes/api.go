@@ -1,14 +1,35 @@
 package routes
 
-import "net/http"
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
 
 const (
 	APIRoutePrefix = "/api"
 	apiNamePrefix  = "api"
+
+	// APIV1RoutePrefix is the prefix of the versioned JSON API. Every route
+	// below it requires an API key.
+	APIV1RoutePrefix = APIRoutePrefix + "/v1"
+	apiV1NamePrefix  = apiNamePrefix + ".v1"
 )
 
 var apiRoutes = []Route{
 	Health,
+	APIResearchBriefCreate,
+	APIResearchBriefShow.Route,
+	APIResearchBriefCandidates.Route,
+	APIReportCreate,
+	APIReportSearch,
+	APIReportShow.Route,
+	APIReportCancel.Route,
+	APIReportSections.Route,
+	APIReportSection.Route,
+	APIReportFinal.Route,
+	APIReportExport.Route,
 }
 
 var Health = Route{
@@ -18,3 +39,135 @@ var Health = Route{
 	Handler:      "API",
 	HandleMethod: "Health",
 }
+
+var APIResearchBriefCreate = Route{
+	Name:         apiV1NamePrefix + ".research-briefs.create",
+	Path:         APIV1RoutePrefix + "/research-briefs",
+	Method:       http.MethodPost,
+	Handler:      "API",
+	HandleMethod: "CreateResearchBrief",
+	Middleware:   requireEditor,
+}
+
+var APIResearchBriefShow = apiIDRoute{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".research-briefs.show",
+		Path:         APIV1RoutePrefix + "/research-briefs/:id",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ShowResearchBrief",
+	},
+}
+
+var APIResearchBriefCandidates = apiIDRoute{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".research-briefs.candidates",
+		Path:         APIV1RoutePrefix + "/research-briefs/:id/candidates",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ListCandidates",
+	},
+}
+
+var APIReportCreate = Route{
+	Name:         apiV1NamePrefix + ".reports.create",
+	Path:         APIV1RoutePrefix + "/reports",
+	Method:       http.MethodPost,
+	Handler:      "API",
+	HandleMethod: "CreateReport",
+	Middleware:   requireEditor,
+}
+
+var APIReportSearch = Route{
+	Name:         apiV1NamePrefix + ".reports.search",
+	Path:         APIV1RoutePrefix + "/reports/search",
+	Method:       http.MethodGet,
+	Handler:      "API",
+	HandleMethod: "SearchReports",
+}
+
+var APIReportShow = apiIDRoute{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".reports.show",
+		Path:         APIV1RoutePrefix + "/reports/:id",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ShowReport",
+	},
+}
+
+var APIReportCancel = apiIDRoute{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".reports.cancel",
+		Path:         APIV1RoutePrefix + "/reports/:id/cancel",
+		Method:       http.MethodPost,
+		Handler:      "API",
+		HandleMethod: "CancelReport",
+		Middleware:   requireEditor,
+	},
+}
+
+var APIReportSections = apiIDRoute{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".reports.sections",
+		Path:         APIV1RoutePrefix + "/reports/:id/sections",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ShowReportSections",
+	},
+}
+
+var APIReportSection = apiReportSection{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".reports.section",
+		Path:         APIV1RoutePrefix + "/reports/:id/sections/:section",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ShowReportSection",
+	},
+}
+
+var APIReportFinal = apiIDRoute{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".reports.final",
+		Path:         APIV1RoutePrefix + "/reports/:id/final",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ShowFinalReport",
+	},
+}
+
+var APIReportExport = apiReportExport{
+	Route: Route{
+		Name:         apiV1NamePrefix + ".reports.export",
+		Path:         APIV1RoutePrefix + "/reports/:id/export/:format",
+		Method:       http.MethodGet,
+		Handler:      "API",
+		HandleMethod: "ExportReport",
+	},
+}
+
+type apiIDRoute struct {
+	Route
+}
+
+func (r apiIDRoute) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+type apiReportSection struct {
+	Route
+}
+
+func (r apiReportSection) GetPath(id uuid.UUID, section string) string {
+	path := strings.Replace(r.Path, ":id", id.String(), 1)
+	return strings.Replace(path, ":section", section, 1)
+}
+
+type apiReportExport struct {
+	Route
+}
+
+func (r apiReportExport) GetPath(id uuid.UUID, format string) string {
+	return strings.NewReplacer(":id", id.String(), ":format", format).Replace(r.Path)
+}
@@ -0,0 +1,53 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	apiKeysRoutePrefix = "/settings/api-keys"
+	apiKeysNamePrefix  = "api_keys"
+)
+
+var APIKeyRoutes = []Route{
+	APIKeyIndex,
+	APIKeyCreate,
+	APIKeyRevoke.Route,
+}
+
+var APIKeyIndex = Route{
+	Name:         apiKeysNamePrefix + ".index",
+	Path:         apiKeysRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "APIKeys",
+	HandleMethod: "Index",
+}
+
+var APIKeyCreate = Route{
+	Name:         apiKeysNamePrefix + ".create",
+	Path:         apiKeysRoutePrefix,
+	Method:       http.MethodPost,
+	Handler:      "APIKeys",
+	HandleMethod: "Create",
+}
+
+var APIKeyRevoke = apiKeysRevoke{
+	Route: Route{
+		Name:         apiKeysNamePrefix + ".revoke",
+		Path:         apiKeysRoutePrefix + "/:id/revoke",
+		Method:       http.MethodPost,
+		Handler:      "APIKeys",
+		HandleMethod: "Revoke",
+	},
+}
+
+type apiKeysRevoke struct {
+	Route
+}
+
+func (r apiKeysRevoke) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
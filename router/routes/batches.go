@@ -0,0 +1,115 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	batchesRoutePrefix = "/batches"
+	batchesNamePrefix  = "batches"
+)
+
+var BatchRoutes = []Route{
+	BatchIndex,
+	BatchCreate,
+	BatchShow.Route,
+	BatchStream.Route,
+	BatchDownload.Route,
+	BatchSelectCandidate.Route,
+}
+
+var BatchIndex = Route{
+	Name:         batchesNamePrefix + ".index",
+	Path:         batchesRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Batches",
+	HandleMethod: "Index",
+}
+
+var BatchCreate = Route{
+	Name:         batchesNamePrefix + ".create",
+	Path:         batchesRoutePrefix,
+	Method:       http.MethodPost,
+	Handler:      "Batches",
+	HandleMethod: "Create",
+	Middleware:   requireEditor,
+}
+
+var BatchShow = batchesShow{
+	Route: Route{
+		Name:         batchesNamePrefix + ".show",
+		Path:         batchesRoutePrefix + "/:id",
+		Method:       http.MethodGet,
+		Handler:      "Batches",
+		HandleMethod: "Show",
+	},
+}
+
+type batchesShow struct {
+	Route
+}
+
+func (r batchesShow) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var BatchStream = batchesStream{
+	Route: Route{
+		Name:         batchesNamePrefix + ".stream",
+		Path:         batchesRoutePrefix + "/:id/stream",
+		Method:       http.MethodGet,
+		Handler:      "Batches",
+		HandleMethod: "Stream",
+	},
+}
+
+type batchesStream struct {
+	Route
+}
+
+func (r batchesStream) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var BatchDownload = batchesDownload{
+	Route: Route{
+		Name:         batchesNamePrefix + ".download",
+		Path:         batchesRoutePrefix + "/:id/download",
+		Method:       http.MethodGet,
+		Handler:      "Batches",
+		HandleMethod: "Download",
+	},
+}
+
+type batchesDownload struct {
+	Route
+}
+
+func (r batchesDownload) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var BatchSelectCandidate = batchesSelectCandidate{
+	Route: Route{
+		Name:         batchesNamePrefix + ".select-candidate",
+		Path:         batchesRoutePrefix + "/:id/rows/:row_id/select",
+		Method:       http.MethodPost,
+		Handler:      "Batches",
+		HandleMethod: "SelectCandidate",
+		Middleware:   requireEditor,
+	},
+}
+
+type batchesSelectCandidate struct {
+	Route
+}
+
+func (r batchesSelectCandidate) GetPath(id, rowID, candidateID uuid.UUID) string {
+	path := strings.Replace(r.Path, ":id", id.String(), 1)
+	path = strings.Replace(path, ":row_id", rowID.String(), 1)
+
+	return path + "?candidate_id=" + candidateID.String()
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	companiesRoutePrefix = "/companies"
+	companiesNamePrefix  = "companies"
+)
+
+var CompanyRoutes = []Route{
+	CompanyIndex,
+	CompanyShow.Route,
+	CompanyMerge.Route,
+}
+
+var CompanyIndex = Route{
+	Name:         companiesNamePrefix + ".index",
+	Path:         companiesRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Companies",
+	HandleMethod: "Index",
+}
+
+var CompanyShow = companyIDRoute{
+	Route: Route{
+		Name:         companiesNamePrefix + ".show",
+		Path:         companiesRoutePrefix + "/:id",
+		Method:       http.MethodGet,
+		Handler:      "Companies",
+		HandleMethod: "Show",
+	},
+}
+
+var CompanyMerge = companyIDRoute{
+	Route: Route{
+		Name:         companiesNamePrefix + ".merge",
+		Path:         companiesRoutePrefix + "/:id/merge",
+		Method:       http.MethodPost,
+		Handler:      "Companies",
+		HandleMethod: "Merge",
+		Middleware:   requireEditor,
+	},
+}
+
+type companyIDRoute struct {
+	Route
+}
+
+func (r companyIDRoute) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
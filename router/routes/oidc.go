@@ -0,0 +1,31 @@
+package routes
+
+import (
+	"net/http"
+)
+
+const oidcNamePrefix = "oidc"
+
+var OIDCRoutes = []Route{
+	OIDCStart,
+	OIDCCallback,
+}
+
+// OIDCStart sends the user to the identity provider to sign in.
+var OIDCStart = Route{
+	Name:         oidcNamePrefix + ".start",
+	Path:         "/auth/oidc",
+	Method:       http.MethodGet,
+	Handler:      "OIDC",
+	HandleMethod: "Start",
+}
+
+// OIDCCallback is where the identity provider sends the user back to, and
+// must be registered as the redirect URI at the identity provider.
+var OIDCCallback = Route{
+	Name:         oidcNamePrefix + ".callback",
+	Path:         "/auth/oidc/callback",
+	Method:       http.MethodGet,
+	Handler:      "OIDC",
+	HandleMethod: "Callback",
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"net/http"
+)
+
+const registrationsNamePrefix = "registrations"
+
+var RegistrationRoutes = []Route{
+	RegistrationNew,
+	RegistrationCreate,
+}
+
+var RegistrationNew = Route{
+	Name:         registrationsNamePrefix + ".new",
+	Path:         "/register",
+	Method:       http.MethodGet,
+	Handler:      "Registrations",
+	HandleMethod: "New",
+}
+
+var RegistrationCreate = Route{
+	Name:         registrationsNamePrefix + ".create",
+	Path:         "/register",
+	Method:       http.MethodPost,
+	Handler:      "Registrations",
+	HandleMethod: "Create",
+}
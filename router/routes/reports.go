@@ -2,6 +2,9 @@ package routes
 
 import (
 	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -10,10 +13,30 @@ const (
 )
 
 var ReportRoutes = []Route{
+	ReportIndex,
+	ReportSearch,
 	ReportCreate,
-	ReportShow,
+	ReportShow.Route,
+	ReportCancel.Route,
 	ReportStreamProgress,
 	ReportStreamGeneration,
+	ReportExport.Route,
+}
+
+var ReportIndex = Route{
+	Name:         reportsNamePrefix + ".index",
+	Path:         reportsRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Reports",
+	HandleMethod: "Index",
+}
+
+var ReportSearch = Route{
+	Name:         reportsNamePrefix + ".search",
+	Path:         reportsRoutePrefix + "/search",
+	Method:       http.MethodGet,
+	Handler:      "Reports",
+	HandleMethod: "Search",
 }
 
 var ReportCreate = Route{
@@ -22,14 +45,36 @@ var ReportCreate = Route{
 	Method:       http.MethodPost,
 	Handler:      "Reports",
 	HandleMethod: "Create",
+	Middleware:   requireEditor,
 }
 
-var ReportShow = Route{
-	Name:         reportsNamePrefix + ".show",
-	Path:         reportsRoutePrefix + "/:id",
-	Method:       http.MethodGet,
-	Handler:      "Reports",
-	HandleMethod: "Show",
+var ReportShow = reportsIDRoute{
+	Route: Route{
+		Name:         reportsNamePrefix + ".show",
+		Path:         reportsRoutePrefix + "/:id",
+		Method:       http.MethodGet,
+		Handler:      "Reports",
+		HandleMethod: "Show",
+	},
+}
+
+var ReportCancel = reportsIDRoute{
+	Route: Route{
+		Name:         reportsNamePrefix + ".cancel",
+		Path:         reportsRoutePrefix + "/:id/cancel",
+		Method:       http.MethodPost,
+		Handler:      "Reports",
+		HandleMethod: "Cancel",
+		Middleware:   requireEditor,
+	},
+}
+
+type reportsIDRoute struct {
+	Route
+}
+
+func (r reportsIDRoute) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
 }
 
 var ReportStreamProgress = Route{
@@ -47,3 +92,21 @@ var ReportStreamGeneration = Route{
 	Handler:      "Reports",
 	HandleMethod: "TrackReportGeneration",
 }
+
+var ReportExport = reportsExport{
+	Route: Route{
+		Name:         reportsNamePrefix + ".export",
+		Path:         reportsRoutePrefix + "/:id/export/:format",
+		Method:       http.MethodGet,
+		Handler:      "Reports",
+		HandleMethod: "Export",
+	},
+}
+
+type reportsExport struct {
+	Route
+}
+
+func (r reportsExport) GetPath(id uuid.UUID, format string) string {
+	return strings.NewReplacer(":id", id.String(), ":format", format).Replace(r.Path)
+}
@@ -13,8 +13,8 @@ const (
 )
 
 var ResearchBriefRoutes = []Route{
-	// ResearchBriefIndex,
-	// ResearchBriefShow.Route,
+	ResearchBriefIndex,
+	ResearchBriefShow.Route,
 	// ResearchBriefNew,
 	ResearchBriefCreate,
 	// ResearchBriefEdit.Route,
@@ -62,6 +62,7 @@ var ResearchBriefCreate = Route{
 	Method:       http.MethodPost,
 	Handler:      "ResearchBriefs",
 	HandleMethod: "Create",
+	Middleware:   requireEditor,
 }
 
 var ResearchBriefEdit = researchbriefsEdit{
@@ -89,6 +90,7 @@ var ResearchBriefUpdate = researchbriefsUpdate{
 		Method:       http.MethodPut,
 		Handler:      "ResearchBriefs",
 		HandleMethod: "Update",
+		Middleware:   requireEditor,
 	},
 }
 
@@ -107,6 +109,7 @@ var ResearchBriefDestroy = researchbriefsDestroy{
 		Method:       http.MethodDelete,
 		Handler:      "ResearchBriefs",
 		HandleMethod: "Destroy",
+		Middleware:   requireEditor,
 	},
 }
 
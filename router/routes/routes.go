@@ -1,6 +1,11 @@
 package routes
 
-import "github.com/labstack/echo/v4"
+import (
+	"github.com/labstack/echo/v4"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/middleware"
+)
 
 type Route struct {
 	Name         string
@@ -11,6 +16,17 @@ type Route struct {
 	Middleware   []func(next echo.HandlerFunc) echo.HandlerFunc
 }
 
+// requireEditor and requireOwner guard routes by the role of the current
+// user in the current workspace.
+var (
+	requireEditor = []func(next echo.HandlerFunc) echo.HandlerFunc{
+		middleware.RequireWorkspaceRole(models.WorkspaceRoleEditor),
+	}
+	requireOwner = []func(next echo.HandlerFunc) echo.HandlerFunc{
+		middleware.RequireWorkspaceRole(models.WorkspaceRoleOwner),
+	}
+)
+
 var BuildRoutes = func() []Route {
 	var r []Route
 
@@ -39,5 +55,60 @@ var BuildRoutes = func() []Route {
 		ReportRoutes...,
 	)
 
+	r = append(
+		r,
+		CompanyRoutes...,
+	)
+
+	r = append(
+		r,
+		WatchlistRoutes...,
+	)
+
+	r = append(
+		r,
+		BatchRoutes...,
+	)
+
+	r = append(
+		r,
+		WebhookRoutes...,
+	)
+
+	r = append(
+		r,
+		APIKeyRoutes...,
+	)
+
+	r = append(
+		r,
+		ShareLinkRoutes...,
+	)
+
+	r = append(
+		r,
+		ToolCallRoutes...,
+	)
+
+	r = append(
+		r,
+		SessionRoutes...,
+	)
+
+	r = append(
+		r,
+		OIDCRoutes...,
+	)
+
+	r = append(
+		r,
+		RegistrationRoutes...,
+	)
+
+	r = append(
+		r,
+		WorkspaceRoutes...,
+	)
+
 	return r
 }()
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"net/http"
+)
+
+const sessionsNamePrefix = "sessions"
+
+var SessionRoutes = []Route{
+	SessionNew,
+	SessionCreate,
+	SessionDestroy,
+}
+
+var SessionNew = Route{
+	Name:         sessionsNamePrefix + ".new",
+	Path:         "/login",
+	Method:       http.MethodGet,
+	Handler:      "Sessions",
+	HandleMethod: "New",
+}
+
+var SessionCreate = Route{
+	Name:         sessionsNamePrefix + ".create",
+	Path:         "/login",
+	Method:       http.MethodPost,
+	Handler:      "Sessions",
+	HandleMethod: "Create",
+}
+
+var SessionDestroy = Route{
+	Name:         sessionsNamePrefix + ".destroy",
+	Path:         "/logout",
+	Method:       http.MethodPost,
+	Handler:      "Sessions",
+	HandleMethod: "Destroy",
+}
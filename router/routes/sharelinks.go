@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	shareLinksNamePrefix = "share-links"
+
+	// SharedRoutePrefix is the prefix of the public, read-only report view
+	// opened through share links.
+	SharedRoutePrefix = "/shared"
+)
+
+var ShareLinkRoutes = []Route{
+	ShareLinkIndex.Route,
+	ShareLinkCreate.Route,
+	ShareLinkRevoke.Route,
+	SharedReportShow.Route,
+}
+
+var ShareLinkIndex = shareLinksReportRoute{
+	Route: Route{
+		Name:         shareLinksNamePrefix + ".index",
+		Path:         reportsRoutePrefix + "/:id/share-links",
+		Method:       http.MethodGet,
+		Handler:      "ShareLinks",
+		HandleMethod: "Index",
+	},
+}
+
+var ShareLinkCreate = shareLinksReportRoute{
+	Route: Route{
+		Name:         shareLinksNamePrefix + ".create",
+		Path:         reportsRoutePrefix + "/:id/share-links",
+		Method:       http.MethodPost,
+		Handler:      "ShareLinks",
+		HandleMethod: "Create",
+		Middleware:   requireEditor,
+	},
+}
+
+type shareLinksReportRoute struct {
+	Route
+}
+
+func (r shareLinksReportRoute) GetPath(reportID uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", reportID.String(), 1)
+}
+
+var ShareLinkRevoke = shareLinksRevoke{
+	Route: Route{
+		Name:         shareLinksNamePrefix + ".revoke",
+		Path:         "/share-links/:id/revoke",
+		Method:       http.MethodPost,
+		Handler:      "ShareLinks",
+		HandleMethod: "Revoke",
+		Middleware:   requireEditor,
+	},
+}
+
+type shareLinksRevoke struct {
+	Route
+}
+
+func (r shareLinksRevoke) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var SharedReportShow = sharedReportShow{
+	Route: Route{
+		Name:         shareLinksNamePrefix + ".shared",
+		Path:         SharedRoutePrefix + "/:token",
+		Method:       http.MethodGet,
+		Handler:      "ShareLinks",
+		HandleMethod: "Shared",
+	},
+}
+
+type sharedReportShow struct {
+	Route
+}
+
+func (r sharedReportShow) GetPath(token string) string {
+	return strings.Replace(r.Path, ":token", token, 1)
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"net/http"
+)
+
+const (
+	toolCallsRoutePrefix = "/tool-calls"
+	toolCallsNamePrefix  = "tool_calls"
+)
+
+var ToolCallRoutes = []Route{
+	ToolCallIndex,
+}
+
+var ToolCallIndex = Route{
+	Name:         toolCallsNamePrefix + ".index",
+	Path:         toolCallsRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "ToolCalls",
+	HandleMethod: "Index",
+	Middleware:   requireOwner,
+}
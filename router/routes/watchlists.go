@@ -0,0 +1,75 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	watchlistsRoutePrefix = "/watchlists"
+	watchlistsNamePrefix  = "watchlists"
+)
+
+var WatchlistRoutes = []Route{
+	WatchlistIndex,
+	WatchlistCreate,
+	WatchlistRun.Route,
+	WatchlistDestroy.Route,
+}
+
+var WatchlistIndex = Route{
+	Name:         watchlistsNamePrefix + ".index",
+	Path:         watchlistsRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Watchlists",
+	HandleMethod: "Index",
+}
+
+var WatchlistCreate = Route{
+	Name:         watchlistsNamePrefix + ".create",
+	Path:         watchlistsRoutePrefix,
+	Method:       http.MethodPost,
+	Handler:      "Watchlists",
+	HandleMethod: "Create",
+	Middleware:   requireEditor,
+}
+
+var WatchlistRun = watchlistsRun{
+	Route: Route{
+		Name:         watchlistsNamePrefix + ".run",
+		Path:         watchlistsRoutePrefix + "/:id/run",
+		Method:       http.MethodPost,
+		Handler:      "Watchlists",
+		HandleMethod: "Run",
+		Middleware:   requireEditor,
+	},
+}
+
+type watchlistsRun struct {
+	Route
+}
+
+func (r watchlistsRun) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var WatchlistDestroy = watchlistsDestroy{
+	Route: Route{
+		Name:         watchlistsNamePrefix + ".destroy",
+		Path:         watchlistsRoutePrefix + "/:id",
+		Method:       http.MethodDelete,
+		Handler:      "Watchlists",
+		HandleMethod: "Destroy",
+		Middleware:   requireEditor,
+	},
+}
+
+type watchlistsDestroy struct {
+	Route
+}
+
+func (r watchlistsDestroy) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
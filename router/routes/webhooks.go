@@ -0,0 +1,94 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhooksRoutePrefix = "/webhooks"
+	webhooksNamePrefix  = "webhooks"
+)
+
+var WebhookRoutes = []Route{
+	WebhookIndex,
+	WebhookCreate,
+	WebhookShow.Route,
+	WebhookToggle.Route,
+	WebhookDestroy.Route,
+}
+
+var WebhookIndex = Route{
+	Name:         webhooksNamePrefix + ".index",
+	Path:         webhooksRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Webhooks",
+	HandleMethod: "Index",
+}
+
+var WebhookCreate = Route{
+	Name:         webhooksNamePrefix + ".create",
+	Path:         webhooksRoutePrefix,
+	Method:       http.MethodPost,
+	Handler:      "Webhooks",
+	HandleMethod: "Create",
+	Middleware:   requireEditor,
+}
+
+var WebhookShow = webhooksShow{
+	Route: Route{
+		Name:         webhooksNamePrefix + ".show",
+		Path:         webhooksRoutePrefix + "/:id",
+		Method:       http.MethodGet,
+		Handler:      "Webhooks",
+		HandleMethod: "Show",
+	},
+}
+
+type webhooksShow struct {
+	Route
+}
+
+func (r webhooksShow) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var WebhookToggle = webhooksToggle{
+	Route: Route{
+		Name:         webhooksNamePrefix + ".toggle",
+		Path:         webhooksRoutePrefix + "/:id/toggle",
+		Method:       http.MethodPost,
+		Handler:      "Webhooks",
+		HandleMethod: "Toggle",
+		Middleware:   requireEditor,
+	},
+}
+
+type webhooksToggle struct {
+	Route
+}
+
+func (r webhooksToggle) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var WebhookDestroy = webhooksDestroy{
+	Route: Route{
+		Name:         webhooksNamePrefix + ".destroy",
+		Path:         webhooksRoutePrefix + "/:id",
+		Method:       http.MethodDelete,
+		Handler:      "Webhooks",
+		HandleMethod: "Destroy",
+		Middleware:   requireEditor,
+	},
+}
+
+type webhooksDestroy struct {
+	Route
+}
+
+func (r webhooksDestroy) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
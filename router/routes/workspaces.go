@@ -0,0 +1,157 @@
+package routes
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+const (
+	workspacesRoutePrefix = "/workspaces"
+	workspacesNamePrefix  = "workspaces"
+
+	// currentWorkspaceRoutePrefix is the prefix of the pages managing the
+	// workspace the user is currently working in.
+	currentWorkspaceRoutePrefix = "/workspace"
+
+	// InvitationsRoutePrefix is the prefix of the pages accepting workspace
+	// invitations, reachable before the user belongs to any workspace.
+	InvitationsRoutePrefix = "/invitations"
+	invitationsNamePrefix  = "invitations"
+)
+
+var WorkspaceRoutes = []Route{
+	WorkspaceIndex,
+	WorkspaceCreate,
+	WorkspaceSwitch.Route,
+	WorkspaceShow,
+	WorkspaceUpdate,
+	WorkspaceMemberUpdate.Route,
+	WorkspaceMemberDestroy.Route,
+	WorkspaceInvitationCreate,
+	WorkspaceInvitationRevoke.Route,
+	InvitationShow.Route,
+	InvitationAccept.Route,
+}
+
+var WorkspaceIndex = Route{
+	Name:         workspacesNamePrefix + ".index",
+	Path:         workspacesRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Workspaces",
+	HandleMethod: "Index",
+}
+
+var WorkspaceCreate = Route{
+	Name:         workspacesNamePrefix + ".create",
+	Path:         workspacesRoutePrefix,
+	Method:       http.MethodPost,
+	Handler:      "Workspaces",
+	HandleMethod: "Create",
+}
+
+var WorkspaceSwitch = workspacesRoute{
+	Route: Route{
+		Name:         workspacesNamePrefix + ".switch",
+		Path:         workspacesRoutePrefix + "/:id/switch",
+		Method:       http.MethodPost,
+		Handler:      "Workspaces",
+		HandleMethod: "Switch",
+	},
+}
+
+var WorkspaceShow = Route{
+	Name:         workspacesNamePrefix + ".show",
+	Path:         currentWorkspaceRoutePrefix,
+	Method:       http.MethodGet,
+	Handler:      "Workspaces",
+	HandleMethod: "Show",
+}
+
+var WorkspaceUpdate = Route{
+	Name:         workspacesNamePrefix + ".update",
+	Path:         currentWorkspaceRoutePrefix,
+	Method:       http.MethodPost,
+	Handler:      "Workspaces",
+	HandleMethod: "Update",
+	Middleware:   requireOwner,
+}
+
+var WorkspaceMemberUpdate = workspacesRoute{
+	Route: Route{
+		Name:         workspacesNamePrefix + ".members.update",
+		Path:         currentWorkspaceRoutePrefix + "/members/:id",
+		Method:       http.MethodPost,
+		Handler:      "Workspaces",
+		HandleMethod: "UpdateMember",
+		Middleware:   requireOwner,
+	},
+}
+
+var WorkspaceMemberDestroy = workspacesRoute{
+	Route: Route{
+		Name:         workspacesNamePrefix + ".members.destroy",
+		Path:         currentWorkspaceRoutePrefix + "/members/:id",
+		Method:       http.MethodDelete,
+		Handler:      "Workspaces",
+		HandleMethod: "DestroyMember",
+		Middleware:   requireOwner,
+	},
+}
+
+var WorkspaceInvitationCreate = Route{
+	Name:         workspacesNamePrefix + ".invitations.create",
+	Path:         currentWorkspaceRoutePrefix + "/invitations",
+	Method:       http.MethodPost,
+	Handler:      "Workspaces",
+	HandleMethod: "CreateInvitation",
+	Middleware:   requireOwner,
+}
+
+var WorkspaceInvitationRevoke = workspacesRoute{
+	Route: Route{
+		Name:         workspacesNamePrefix + ".invitations.revoke",
+		Path:         currentWorkspaceRoutePrefix + "/invitations/:id/revoke",
+		Method:       http.MethodPost,
+		Handler:      "Workspaces",
+		HandleMethod: "RevokeInvitation",
+		Middleware:   requireOwner,
+	},
+}
+
+type workspacesRoute struct {
+	Route
+}
+
+func (r workspacesRoute) GetPath(id uuid.UUID) string {
+	return strings.Replace(r.Path, ":id", id.String(), 1)
+}
+
+var InvitationShow = invitationsRoute{
+	Route: Route{
+		Name:         invitationsNamePrefix + ".show",
+		Path:         InvitationsRoutePrefix + "/:token",
+		Method:       http.MethodGet,
+		Handler:      "Invitations",
+		HandleMethod: "Show",
+	},
+}
+
+var InvitationAccept = invitationsRoute{
+	Route: Route{
+		Name:         invitationsNamePrefix + ".accept",
+		Path:         InvitationsRoutePrefix + "/:token",
+		Method:       http.MethodPost,
+		Handler:      "Invitations",
+		HandleMethod: "Accept",
+	},
+}
+
+type invitationsRoute struct {
+	Route
+}
+
+func (r invitationsRoute) GetPath(token string) string {
+	return strings.Replace(r.Path, ":token", token, 1)
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// MinPasswordLength is the shortest password accepted at registration.
+const MinPasswordLength = 10
+
+// argon2id parameters, following the OWASP recommendation for interactive
+// logins. They are stored with every hash, so they can be raised later
+// without invalidating existing passwords.
+const (
+	passwordHashTime    = 3
+	passwordHashMemory  = 64 * 1024
+	passwordHashThreads = 2
+	passwordHashKeyLen  = 32
+	passwordSaltLen     = 16
+)
+
+var (
+	ErrEmailTaken = errors.New("an account with this email already exists")
+	// ErrInvalidCredentials is returned for an unknown email and for a wrong
+	// password alike, so logins do not reveal which accounts exist.
+	ErrInvalidCredentials = errors.New("invalid email or password")
+	ErrPasswordTooShort   = fmt.Errorf("passwords must be at least %d characters", MinPasswordLength)
+)
+
+// dummyPasswordHash is verified against when no user has the given email, so
+// failed logins take as long for unknown accounts as for known ones.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	return HashPassword("not a real password")
+})
+
+// RegisterUser creates an account with a hashed password, together with a
+// personal workspace the user owns.
+func RegisterUser(
+	ctx context.Context,
+	conn *sql.DB,
+	email string,
+	password string,
+) (models.User, error) {
+	if len(password) < MinPasswordLength {
+		return models.User{}, ErrPasswordTooShort
+	}
+
+	if _, err := models.FindUserByEmail(ctx, conn, email); err == nil {
+		return models.User{}, ErrEmailTaken
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return models.User{}, err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
+	user, err := models.CreateUser(ctx, tx, models.CreateUserData{
+		Email:        email,
+		PasswordHash: HashPassword(password),
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	workspace, err := models.CreateWorkspace(ctx, tx, models.CreateWorkspaceData{
+		Name: personalWorkspaceName(user.Email),
+	})
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if _, err := models.CreateWorkspaceMembership(ctx, tx, models.CreateWorkspaceMembershipData{
+		WorkspaceID: workspace.ID,
+		UserID:      user.ID,
+		Role:        models.WorkspaceRoleOwner,
+	}); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// personalWorkspaceName names the workspace created at registration after
+// the local part of the user's email.
+func personalWorkspaceName(email string) string {
+	name, _, _ := strings.Cut(email, "@")
+	return fmt.Sprintf("%s's workspace", name)
+}
+
+// AuthenticateUser returns the user with the given email and password and
+// records the login.
+func AuthenticateUser(
+	ctx context.Context,
+	conn *sql.DB,
+	email string,
+	password string,
+) (models.User, error) {
+	user, err := models.FindUserByEmail(ctx, conn, email)
+	if errors.Is(err, sql.ErrNoRows) {
+		VerifyPassword(dummyPasswordHash(), password)
+		return models.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if !VerifyPassword(user.PasswordHash, password) {
+		return models.User{}, ErrInvalidCredentials
+	}
+
+	if err := models.TouchUserLogin(ctx, conn, user.ID, time.Now()); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+// HashPassword returns the encoded argon2id hash of a password with a random
+// salt, in the PHC string format. The password is peppered with
+// config.Auth.PasswordSalt first, so a leaked users table alone is not
+// enough to brute force it.
+func HashPassword(password string) string {
+	salt := make([]byte, passwordSaltLen)
+	if _, err := rand.Read(salt); err != nil {
+		// crypto/rand never fails on supported platforms.
+		panic(err)
+	}
+
+	key := argon2.IDKey(
+		pepperPassword(password),
+		salt,
+		passwordHashTime,
+		passwordHashMemory,
+		passwordHashThreads,
+		passwordHashKeyLen,
+	)
+
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version,
+		passwordHashMemory,
+		passwordHashTime,
+		passwordHashThreads,
+		base64.RawStdEncoding.EncodeToString(salt),
+		base64.RawStdEncoding.EncodeToString(key),
+	)
+}
+
+// VerifyPassword reports whether password matches an encoded hash made by
+// HashPassword.
+func VerifyPassword(encoded string, password string) bool {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false
+	}
+
+	var memory, iterations uint32
+	var threads uint8
+	// argon2 panics on zero rounds or threads instead of returning an error.
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &iterations, &threads); err != nil ||
+		iterations < 1 || threads < 1 {
+		return false
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return false
+	}
+	// An empty key would compare equal to the empty key derived for any
+	// password.
+	want, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(want) == 0 {
+		return false
+	}
+
+	got := argon2.IDKey(pepperPassword(password), salt, iterations, memory, threads, uint32(len(want)))
+
+	return subtle.ConstantTimeCompare(got, want) == 1
+}
+
+func pepperPassword(password string) []byte {
+	mac := hmac.New(sha256.New, []byte(config.Auth.PasswordSalt))
+	mac.Write([]byte(password))
+
+	return mac.Sum(nil)
+}
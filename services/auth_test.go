@@ -0,0 +1,114 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/argon2"
+
+	"github.com/mbvlabs/plyo-hackathon/config"
+)
+
+func TestVerifyPassword(t *testing.T) {
+	const password = "correct horse battery staple"
+
+	encoded := HashPassword(password)
+	parts := strings.Split(encoded, "$")
+	salt, key := parts[4], parts[5]
+
+	// withParams re-encodes the hash with other parameters, keeping its salt
+	// and key.
+	withParams := func(params string) string {
+		return fmt.Sprintf("$argon2id$v=%d$%s$%s$%s", argon2.Version, params, salt, key)
+	}
+
+	tests := []struct {
+		name     string
+		encoded  string
+		password string
+		want     bool
+	}{
+		{name: "right password", encoded: encoded, password: password, want: true},
+		{name: "wrong password", encoded: encoded, password: "correct horse battery stable"},
+		{name: "empty password", encoded: encoded, password: ""},
+		{name: "empty hash", encoded: "", password: password},
+		{
+			name:     "argon2i instead of argon2id",
+			encoded:  strings.Replace(encoded, "$argon2id$", "$argon2i$", 1),
+			password: password,
+		},
+		{
+			name:     "other version",
+			encoded:  strings.Replace(encoded, fmt.Sprintf("v=%d", argon2.Version), "v=16", 1),
+			password: password,
+		},
+		{
+			name:     "other memory",
+			encoded:  withParams(fmt.Sprintf("m=%d,t=%d,p=%d", passwordHashMemory/2, passwordHashTime, passwordHashThreads)),
+			password: password,
+		},
+		{
+			name:     "zero iterations",
+			encoded:  withParams(fmt.Sprintf("m=%d,t=0,p=%d", passwordHashMemory, passwordHashThreads)),
+			password: password,
+		},
+		{
+			name:     "zero threads",
+			encoded:  withParams(fmt.Sprintf("m=%d,t=%d,p=0", passwordHashMemory, passwordHashTime)),
+			password: password,
+		},
+		{name: "missing params", encoded: withParams("m=1"), password: password},
+		{name: "missing key", encoded: strings.TrimSuffix(encoded, key), password: password},
+		{name: "salt not base64", encoded: strings.Replace(encoded, salt, "!!!", 1), password: password},
+		{name: "too many parts", encoded: encoded + "$extra", password: password},
+		{name: "bcrypt hash", encoded: "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy", password: password},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := VerifyPassword(tt.encoded, tt.password); got != tt.want {
+				t.Fatalf("VerifyPassword() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyPasswordWrongPepper(t *testing.T) {
+	const password = "correct horse battery staple"
+
+	pepper := config.Auth.PasswordSalt
+	t.Cleanup(func() { config.Auth.PasswordSalt = pepper })
+
+	config.Auth.PasswordSalt = "pepper-one"
+	encoded := HashPassword(password)
+
+	tests := []struct {
+		name   string
+		pepper string
+		want   bool
+	}{
+		{name: "same pepper", pepper: "pepper-one", want: true},
+		{name: "other pepper", pepper: "pepper-two"},
+		{name: "no pepper", pepper: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config.Auth.PasswordSalt = tt.pepper
+			if got := VerifyPassword(encoded, password); got != tt.want {
+				t.Fatalf("VerifyPassword() with pepper %q = %v, want %v", tt.pepper, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashPasswordSalted(t *testing.T) {
+	first, second := HashPassword("same password"), HashPassword("same password")
+	if first == second {
+		t.Fatal("HashPassword() returned the same hash twice, the salt is not random")
+	}
+	if !strings.HasPrefix(first, fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$", argon2.Version, passwordHashMemory, passwordHashTime, passwordHashThreads)) {
+		t.Fatalf("HashPassword() = %q, not in the PHC format", first)
+	}
+}
@@ -0,0 +1,579 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/tools"
+	"maragu.dev/goqite"
+)
+
+// MaxBatchRows caps the number of companies in a single CSV import.
+const MaxBatchRows = 500
+
+var (
+	ErrBatchEmpty    = errors.New("csv does not contain any companies")
+	ErrBatchTooLarge = fmt.Errorf("csv contains more than %d companies", MaxBatchRows)
+)
+
+// dispatchMu serialises report dispatching so concurrent job handlers cannot
+// start more reports than the concurrency limit allows.
+var dispatchMu sync.Mutex
+
+// ImportBatchCSV creates a batch from a CSV of company names and optional
+// URLs and queues preliminary research for every row. A header row naming a
+// "name" and optionally a "url" column is honoured; without one the first
+// column is the name and the second the URL. The batch and everything it
+// researches are owned by userID and belong to workspaceID.
+func ImportBatchCSV(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	name string,
+	userID string,
+	workspaceID string,
+	r io.Reader,
+) (models.Batch, error) {
+	entries, err := parseBatchCSV(r)
+	if err != nil {
+		return models.Batch{}, err
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Batch{}, err
+	}
+	defer tx.Rollback()
+
+	batch, err := models.CreateBatch(ctx, tx, models.CreateBatchData{
+		Name:        name,
+		UserID:      userID,
+		WorkspaceID: workspaceID,
+	})
+	if err != nil {
+		return models.Batch{}, err
+	}
+
+	rows := make([]models.BatchRow, len(entries))
+	for i, entry := range entries {
+		row, err := models.CreateBatchRow(ctx, tx, models.CreateBatchRowData{
+			BatchID:   batch.ID.String(),
+			RowNumber: int64(i + 1),
+			InputName: entry.name,
+			InputURL:  entry.url,
+		})
+		if err != nil {
+			return models.Batch{}, err
+		}
+		rows[i] = row
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Batch{}, err
+	}
+
+	for _, row := range rows {
+		if err := enqueue(ctx, q, agents.PreliminaryResearchJobName, agents.PreliminaryResearchJobParams{
+			BatchRowID: row.ID,
+		}); err != nil {
+			return models.Batch{}, err
+		}
+	}
+
+	return batch, nil
+}
+
+// ResearchBatchRow runs preliminary research for a batch row. When the
+// identification confidence reaches the threshold and a single candidate
+// matches, the row is queued for a full report; otherwise it waits for a
+// human to pick a candidate.
+func ResearchBatchRow(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	agent agents.PreliminaryResearch,
+	rowID uuid.UUID,
+	threshold float64,
+	concurrency int64,
+) error {
+	row, err := models.FindBatchRow(ctx, conn, rowID)
+	if err != nil {
+		return err
+	}
+
+	if row.Status != models.BatchRowPending {
+		return nil
+	}
+
+	batch, err := models.FindBatchByID(ctx, conn, uuid.MustParse(row.BatchID))
+	if err != nil {
+		return err
+	}
+
+	query := row.InputName
+	if row.InputURL != "" {
+		query = fmt.Sprintf("%s (%s)", row.InputName, row.InputURL)
+	}
+
+	result, err := agent.Research(tools.WithCallScope(ctx, batch.WorkspaceID, ""), query)
+	if err != nil {
+		return models.UpdateBatchRowStatus(ctx, conn, row.ID, models.BatchRowFailed, err.Error())
+	}
+
+	brief, err := SaveResearchBrief(ctx, conn, result, batch.UserID, batch.WorkspaceID)
+	if err != nil {
+		return models.UpdateBatchRowStatus(ctx, conn, row.ID, models.BatchRowFailed, err.Error())
+	}
+
+	candidate, _, ok, err := identifyCandidate(ctx, conn, brief, result, row.InputURL, threshold)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		return models.SetBatchRowResearchBrief(
+			ctx,
+			conn,
+			row.ID,
+			models.BatchRowNeedsReview,
+			brief.ID.String(),
+			"",
+		)
+	}
+
+	if err := models.SetBatchRowResearchBrief(
+		ctx,
+		conn,
+		row.ID,
+		models.BatchRowQueued,
+		brief.ID.String(),
+		candidate.ID.String(),
+	); err != nil {
+		return err
+	}
+
+	return DispatchBatchReports(ctx, conn, q, concurrency)
+}
+
+// SelectBatchCandidate records the candidate a human picked for a row that
+// needed review and queues the row for a full report.
+func SelectBatchCandidate(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	row models.BatchRow,
+	candidateID uuid.UUID,
+	concurrency int64,
+) error {
+	candidate, err := models.FindCompanyCandidatesByID(ctx, conn, candidateID)
+	if err != nil {
+		return err
+	}
+
+	if candidate.ResearchBriefID != row.ResearchBriefID {
+		return errors.Join(
+			models.ErrDomainValidation,
+			errors.New("candidate does not belong to the row's research brief"),
+		)
+	}
+
+	if err := models.SetBatchRowResearchBrief(
+		ctx,
+		conn,
+		row.ID,
+		models.BatchRowQueued,
+		row.ResearchBriefID,
+		candidate.ID.String(),
+	); err != nil {
+		return err
+	}
+
+	return DispatchBatchReports(ctx, conn, q, concurrency)
+}
+
+// DispatchBatchReports starts full reports for queued rows, oldest first,
+// until the number of running batch reports reaches the concurrency limit.
+func DispatchBatchReports(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	concurrency int64,
+) error {
+	dispatchMu.Lock()
+	defer dispatchMu.Unlock()
+
+	running, err := models.CountBatchRowsByStatus(ctx, conn, models.BatchRowRunning)
+	if err != nil {
+		return err
+	}
+
+	if running >= concurrency {
+		return nil
+	}
+
+	queued, err := models.QueuedBatchRows(ctx, conn, concurrency-running)
+	if err != nil {
+		return err
+	}
+
+	for _, row := range queued {
+		if err := startBatchReport(ctx, conn, q, row); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to start batch report",
+				"error", err,
+				"batch_row_id", row.ID,
+			)
+			if statusErr := models.UpdateBatchRowStatus(ctx, conn, row.ID, models.BatchRowFailed, err.Error()); statusErr != nil {
+				return errors.Join(err, statusErr)
+			}
+		}
+	}
+
+	return nil
+}
+
+// CompleteBatchRow marks the batch row behind a generated report as completed
+// and frees its slot for the next queued row. Reports outside a batch are
+// ignored.
+func CompleteBatchRow(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+	concurrency int64,
+) error {
+	row, err := models.FindBatchRowByReportID(ctx, conn, reportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := models.UpdateBatchRowStatus(ctx, conn, row.ID, models.BatchRowCompleted, ""); err != nil {
+		return err
+	}
+
+	return DispatchBatchReports(ctx, conn, q, concurrency)
+}
+
+// FailBatchRow marks the batch row behind a failed report as failed and
+// starts the next queued row. Reports outside a batch are ignored.
+func FailBatchRow(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+	cause error,
+	concurrency int64,
+) error {
+	row, err := models.FindBatchRowByReportID(ctx, conn, reportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := models.UpdateBatchRowStatus(ctx, conn, row.ID, models.BatchRowFailed, cause.Error()); err != nil {
+		return err
+	}
+
+	return DispatchBatchReports(ctx, conn, q, concurrency)
+}
+
+// WriteBatchArchive writes a zip with one markdown file per completed report
+// and a summary.csv describing every row of the batch.
+func WriteBatchArchive(
+	ctx context.Context,
+	conn *sql.DB,
+	w io.Writer,
+	batch models.Batch,
+) error {
+	rows, err := models.FindBatchRowsByBatchID(ctx, conn, batch.ID)
+	if err != nil {
+		return err
+	}
+
+	archive := zip.NewWriter(w)
+
+	// The summary is written last since the zip writer only accepts writes to
+	// the most recently created file.
+	var summary bytes.Buffer
+	summaryWriter := csv.NewWriter(&summary)
+	if err := summaryWriter.Write([]string{"row", "name", "url", "status", "company", "file", "error"}); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		var companyName, fileName string
+
+		if row.ReportID != "" {
+			report, err := models.FindReportByID(ctx, conn, uuid.MustParse(row.ReportID))
+			if err != nil {
+				return err
+			}
+
+			companyName = report.CompanyName
+			if report.FinalReport != "" {
+				fileName = fmt.Sprintf("%03d-%s.md", row.RowNumber, slugify(report.CompanyName))
+
+				file, err := archive.Create(fileName)
+				if err != nil {
+					return err
+				}
+				if _, err := io.WriteString(file, report.FinalReport); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := summaryWriter.Write([]string{
+			strconv.FormatInt(row.RowNumber, 10),
+			row.InputName,
+			row.InputURL,
+			row.Status,
+			companyName,
+			fileName,
+			row.Error,
+		}); err != nil {
+			return err
+		}
+	}
+
+	summaryWriter.Flush()
+	if err := summaryWriter.Error(); err != nil {
+		return err
+	}
+
+	file, err := archive.Create("summary.csv")
+	if err != nil {
+		return err
+	}
+	if _, err := summary.WriteTo(file); err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
+
+func startBatchReport(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	row models.BatchRow,
+) error {
+	candidate, err := models.FindCompanyCandidatesByID(ctx, conn, uuid.MustParse(row.CompanyCandidateID))
+	if err != nil {
+		return err
+	}
+
+	report, err := CreateReport(ctx, conn, candidate)
+	if err != nil {
+		return err
+	}
+
+	if err := models.SetBatchRowReport(ctx, conn, row.ID, models.BatchRowRunning, report.ID); err != nil {
+		return err
+	}
+
+	return StartResearch(ctx, conn, q, report, candidate.Domain)
+}
+
+// identifyCandidate loads the candidates of a saved research brief and picks
+// one without human review when possible. A confident brief without any
+// candidates gets a candidate created from the brief itself.
+func identifyCandidate(
+	ctx context.Context,
+	conn *sql.DB,
+	researchBrief models.ResearchBrief,
+	brief agents.ResearchBrief,
+	inputURL string,
+	threshold float64,
+) (models.CompanyCandidates, []models.CompanyCandidates, bool, error) {
+	candidates, err := models.FindCompanyCandidatesByResearchBriefID(
+		ctx,
+		conn,
+		researchBrief.ID.String(),
+	)
+	if err != nil {
+		return models.CompanyCandidates{}, nil, false, err
+	}
+
+	if len(candidates) == 0 && brief.ConfidenceScore >= threshold && brief.CompanyName != "" {
+		companyID, err := resolveCompanyID(
+			ctx,
+			conn,
+			researchBrief.WorkspaceID,
+			brief.CompanyName,
+			brief.OfficialDomain,
+		)
+		if err != nil {
+			return models.CompanyCandidates{}, nil, false, err
+		}
+
+		candidate, err := models.CreateCompanyCandidates(ctx, conn, models.CreateCompanyCandidatesData{
+			ResearchBriefID: researchBrief.ID.String(),
+			Name:            brief.CompanyName,
+			Domain:          brief.OfficialDomain,
+			Industry:        brief.Industry,
+			Location:        brief.Headquarters,
+			CompanyID:       companyID,
+		})
+		if err != nil {
+			return models.CompanyCandidates{}, nil, false, err
+		}
+		candidates = append(candidates, candidate)
+	}
+
+	candidate, ok := autoSelectCandidate(inputURL, brief, candidates, threshold)
+
+	return candidate, candidates, ok, nil
+}
+
+// autoSelectCandidate picks a candidate without human review when the
+// research is confident and either a single candidate was found or exactly
+// one candidate matches the given URL or the domain found by the research.
+func autoSelectCandidate(
+	inputURL string,
+	brief agents.ResearchBrief,
+	candidates []models.CompanyCandidates,
+	threshold float64,
+) (models.CompanyCandidates, bool) {
+	if brief.ConfidenceScore < threshold || len(candidates) == 0 {
+		return models.CompanyCandidates{}, false
+	}
+
+	if len(candidates) == 1 {
+		return candidates[0], true
+	}
+
+	domain := normalizeDomain(inputURL)
+	if domain == "" {
+		domain = normalizeDomain(brief.OfficialDomain)
+	}
+	if domain == "" {
+		return models.CompanyCandidates{}, false
+	}
+
+	var matches []models.CompanyCandidates
+	for _, candidate := range candidates {
+		if normalizeDomain(candidate.Domain) == domain {
+			matches = append(matches, candidate)
+		}
+	}
+
+	if len(matches) != 1 {
+		return models.CompanyCandidates{}, false
+	}
+
+	return matches[0], true
+}
+
+type batchEntry struct {
+	name string
+	url  string
+}
+
+func parseBatchCSV(r io.Reader) ([]batchEntry, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, errors.Join(models.ErrDomainValidation, err)
+	}
+
+	nameColumn, urlColumn := 0, 1
+	if len(records) > 0 {
+		header := make(map[string]int, len(records[0]))
+		for i, column := range records[0] {
+			header[strings.ToLower(strings.TrimSpace(column))] = i
+		}
+
+		if i, ok := header["name"]; ok {
+			nameColumn, urlColumn = i, -1
+			for _, key := range []string{"url", "website", "domain"} {
+				if i, ok := header[key]; ok {
+					urlColumn = i
+					break
+				}
+			}
+			records = records[1:]
+		}
+	}
+
+	entries := make([]batchEntry, 0, len(records))
+	for _, record := range records {
+		entry := batchEntry{name: column(record, nameColumn), url: column(record, urlColumn)}
+		if entry.name == "" {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) == 0 {
+		return nil, errors.Join(models.ErrDomainValidation, ErrBatchEmpty)
+	}
+	if len(entries) > MaxBatchRows {
+		return nil, errors.Join(models.ErrDomainValidation, ErrBatchTooLarge)
+	}
+
+	return entries, nil
+}
+
+func column(record []string, i int) string {
+	if i < 0 || i >= len(record) {
+		return ""
+	}
+
+	return strings.TrimSpace(record[i])
+}
+
+// normalizeDomain reduces a URL or bare domain to its lower-cased host
+// without a leading "www.".
+func normalizeDomain(raw string) string {
+	raw = strings.TrimSpace(strings.ToLower(raw))
+	if raw == "" {
+		return ""
+	}
+
+	if !strings.Contains(raw, "://") {
+		raw = "https://" + raw
+	}
+
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimPrefix(parsed.Hostname(), "www.")
+}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+func slugify(s string) string {
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(s), "-"), "-")
+	if slug == "" {
+		return "report"
+	}
+
+	return slug
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+var (
+	ErrCompanyMergeSelf    = errors.New("that domain already belongs to this company")
+	ErrCompanyMerged       = errors.New("this company was merged into another one")
+	ErrCompanyMergeUnknown = errors.New("no company with that domain in this workspace")
+)
+
+// CompanyDomain returns the key of the canonical company behind a URL or
+// domain: the lower-cased host without scheme, port, path or a leading
+// "www.". It is empty for values research agents write when they found no
+// domain, such as "N/A" or "unknown".
+func CompanyDomain(raw string) string {
+	domain := normalizeDomain(raw)
+	if !strings.Contains(domain, ".") || strings.ContainsAny(domain, " \t") {
+		return ""
+	}
+
+	return domain
+}
+
+// resolveCompanyID returns the id of the canonical company of the workspace
+// for a candidate's domain, creating the company when the domain is new. It
+// returns an empty id when the domain is unusable or the candidate belongs
+// to no workspace, in which case the candidate is simply not linked.
+func resolveCompanyID(
+	ctx context.Context,
+	conn *sql.DB,
+	workspaceID string,
+	name string,
+	domain string,
+) (string, error) {
+	key := CompanyDomain(domain)
+	if key == "" || workspaceID == "" {
+		return "", nil
+	}
+
+	if strings.TrimSpace(name) == "" {
+		name = key
+	}
+
+	company, err := models.FindOrCreateCompany(ctx, conn, models.FindOrCreateCompanyData{
+		WorkspaceID: uuid.MustParse(workspaceID),
+		Domain:      key,
+		Name:        strings.TrimSpace(name),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return company.ID.String(), nil
+}
+
+// MergeCompanies merges the company keyed by duplicateDomain into target:
+// its candidates, and with them its research briefs, its reports and its
+// findings move to target, and its domain resolves to target from now on.
+// Both must belong to the workspace. It returns the duplicate as it was
+// before the merge.
+func MergeCompanies(
+	ctx context.Context,
+	conn *sql.DB,
+	workspaceID uuid.UUID,
+	targetID uuid.UUID,
+	duplicateDomain string,
+) (models.Company, error) {
+	key := CompanyDomain(duplicateDomain)
+	if key == "" {
+		return models.Company{}, ErrCompanyMergeUnknown
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Company{}, err
+	}
+	defer tx.Rollback()
+
+	target, err := models.FindCompany(ctx, tx, workspaceID, targetID)
+	if err != nil {
+		return models.Company{}, err
+	}
+	if target.Merged() {
+		return models.Company{}, ErrCompanyMerged
+	}
+
+	source, err := models.FindCompanyByDomain(ctx, tx, workspaceID, key)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Company{}, ErrCompanyMergeUnknown
+	}
+	if err != nil {
+		return models.Company{}, err
+	}
+	if source.ID == target.ID {
+		return models.Company{}, ErrCompanyMergeSelf
+	}
+
+	if err := models.MergeCompany(ctx, tx, source.ID, target.ID); err != nil {
+		return models.Company{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Company{}, err
+	}
+
+	return source, nil
+}
@@ -0,0 +1,448 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strings"
+
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// Abstract numbering definitions in docxNumbering.
+const (
+	docxBulletList  = 0
+	docxDecimalList = 1
+)
+
+// docxListIndent is the indentation per list level, in twentieths of a
+// point.
+const docxListIndent = 720
+
+type docxRunStyle struct {
+	bold   bool
+	italic bool
+	strike bool
+	code   bool
+	link   bool
+}
+
+type docxList struct {
+	abstractID int
+	level      int
+	start      int
+}
+
+type docxWriter struct {
+	source []byte
+	body   bytes.Buffer
+	// links holds the targets of external hyperlinks. Link i has the
+	// relationship id "rIdLink<i>".
+	links []string
+	// lists holds a numbering instance per markdown list, so every ordered
+	// list starts counting on its own.
+	lists []docxList
+}
+
+// RenderReportDOCX renders the final report as a Word document. Headings,
+// lists, tables and links are mapped onto Word's built-in styles from the
+// goldmark AST, so the document stays editable like any other.
+func RenderReportDOCX(report models.Report) ([]byte, error) {
+	doc, source := parseMarkdown(report.FinalReport)
+
+	generatedAt := reportGeneratedAt(report)
+
+	w := &docxWriter{source: source}
+	w.paragraph("Title", func() { w.text(report.CompanyName, docxRunStyle{}) })
+	w.paragraph("Subtitle", func() {
+		w.text("Company research report, generated "+generatedAt.Format("2 January 2006"), docxRunStyle{})
+	})
+	w.blocks(doc, 0)
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, part := range []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", docxContentTypes},
+		{"_rels/.rels", docxPackageRels},
+		{"word/document.xml", w.document()},
+		{"word/styles.xml", docxStyles},
+		{"word/numbering.xml", w.numbering()},
+		{"word/_rels/document.xml.rels", w.documentRels()},
+	} {
+		// A fixed modification time keeps the archive identical for the same
+		// report.
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     part.name,
+			Method:   zip.Deflate,
+			Modified: generatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if _, err := file.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (w *docxWriter) blocks(parent ast.Node, depth int) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		w.block(n, depth)
+	}
+}
+
+func (w *docxWriter) block(n ast.Node, depth int) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		w.paragraph(fmt.Sprintf("Heading%d", min(n.Level, 6)), func() {
+			w.inlines(n, docxRunStyle{})
+		})
+	case *ast.Paragraph, *ast.TextBlock:
+		w.paragraph("", func() { w.inlines(n, docxRunStyle{}) })
+	case *ast.List:
+		w.list(n, depth)
+	case *ast.Blockquote:
+		for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+			if _, ok := child.(*ast.Paragraph); ok {
+				w.paragraph("Quote", func() { w.inlines(child, docxRunStyle{}) })
+				continue
+			}
+			w.block(child, depth)
+		}
+	case *ast.FencedCodeBlock:
+		w.code(n.Lines())
+	case *ast.CodeBlock:
+		w.code(n.Lines())
+	case *ast.ThematicBreak:
+		w.body.WriteString(`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="D1D5DB"/></w:pBdr></w:pPr></w:p>`)
+	case *extast.Table:
+		w.table(n)
+	case *ast.HTMLBlock:
+		// Raw HTML has no counterpart in the document.
+	default:
+		w.blocks(n, depth)
+	}
+}
+
+func (w *docxWriter) list(n *ast.List, depth int) {
+	list := docxList{abstractID: docxBulletList, level: depth, start: n.Start}
+	if n.IsOrdered() {
+		list.abstractID = docxDecimalList
+	}
+	w.lists = append(w.lists, list)
+	numID := len(w.lists)
+
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		numbered := false
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			switch child := child.(type) {
+			case *ast.TextBlock, *ast.Paragraph:
+				// Only the first paragraph of an item carries the bullet or
+				// number; later ones are indented to line up with it.
+				props := fmt.Sprintf(`<w:ind w:left="%d" w:hanging="360"/>`, docxListIndent*(depth+1))
+				if !numbered {
+					props = fmt.Sprintf(`<w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr>`, depth, numID)
+					numbered = true
+				}
+				w.body.WriteString(`<w:p><w:pPr><w:pStyle w:val="ListParagraph"/>` + props + `</w:pPr>`)
+				w.inlines(child, docxRunStyle{})
+				w.body.WriteString(`</w:p>`)
+			case *ast.List:
+				w.list(child, depth+1)
+			default:
+				w.block(child, depth+1)
+			}
+		}
+	}
+}
+
+func (w *docxWriter) code(lines *text.Segments) {
+	for i := range lines.Len() {
+		line := lines.At(i)
+		w.paragraph("Code", func() {
+			w.text(strings.TrimRight(string(line.Value(w.source)), "\r\n"), docxRunStyle{})
+		})
+	}
+}
+
+func (w *docxWriter) table(n *extast.Table) {
+	w.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr><w:tblGrid>`)
+	for range n.Alignments {
+		w.body.WriteString(`<w:gridCol/>`)
+	}
+	w.body.WriteString(`</w:tblGrid>`)
+
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		_, header := row.(*extast.TableHeader)
+
+		w.body.WriteString(`<w:tr>`)
+		if header {
+			w.body.WriteString(`<w:trPr><w:tblHeader/></w:trPr>`)
+		}
+
+		column := 0
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			align := ""
+			if column < len(n.Alignments) {
+				switch n.Alignments[column] {
+				case extast.AlignCenter:
+					align = `<w:jc w:val="center"/>`
+				case extast.AlignRight:
+					align = `<w:jc w:val="right"/>`
+				}
+			}
+
+			w.body.WriteString(`<w:tc><w:p><w:pPr><w:spacing w:after="0"/>` + align + `</w:pPr>`)
+			w.inlines(cell, docxRunStyle{bold: header})
+			w.body.WriteString(`</w:p></w:tc>`)
+			column++
+		}
+		w.body.WriteString(`</w:tr>`)
+	}
+
+	// Word merges a table with one that directly follows it, so every table
+	// is closed off with an empty paragraph.
+	w.body.WriteString(`</w:tbl><w:p/>`)
+}
+
+func (w *docxWriter) paragraph(style string, content func()) {
+	w.body.WriteString(`<w:p>`)
+	if style != "" {
+		w.body.WriteString(`<w:pPr><w:pStyle w:val="` + style + `"/></w:pPr>`)
+	}
+	content()
+	w.body.WriteString(`</w:p>`)
+}
+
+func (w *docxWriter) inlines(parent ast.Node, style docxRunStyle) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		w.inline(n, style)
+	}
+}
+
+func (w *docxWriter) inline(n ast.Node, style docxRunStyle) {
+	switch n := n.(type) {
+	case *ast.Text:
+		w.text(string(n.Segment.Value(w.source)), style)
+		if n.HardLineBreak() {
+			w.body.WriteString(`<w:r><w:br/></w:r>`)
+		} else if n.SoftLineBreak() {
+			w.text(" ", style)
+		}
+	case *ast.String:
+		w.text(string(n.Value), style)
+	case *ast.Emphasis:
+		if n.Level >= 2 {
+			style.bold = true
+		} else {
+			style.italic = true
+		}
+		w.inlines(n, style)
+	case *extast.Strikethrough:
+		style.strike = true
+		w.inlines(n, style)
+	case *ast.CodeSpan:
+		style.code = true
+		w.text(markdownText(n, w.source), style)
+	case *ast.Link:
+		w.hyperlink(string(n.Destination), func() {
+			style.link = true
+			w.inlines(n, style)
+		})
+	case *ast.AutoLink:
+		url := string(n.URL(w.source))
+		target := url
+		if n.AutoLinkType == ast.AutoLinkEmail {
+			target = "mailto:" + url
+		}
+		w.hyperlink(target, func() {
+			style.link = true
+			w.text(url, style)
+		})
+	case *ast.Image:
+		w.text(markdownText(n, w.source), style)
+	case *ast.RawHTML:
+		// Inline HTML has no counterpart in the document.
+	default:
+		w.inlines(n, style)
+	}
+}
+
+func (w *docxWriter) hyperlink(target string, content func()) {
+	w.links = append(w.links, target)
+	fmt.Fprintf(&w.body, `<w:hyperlink r:id="rIdLink%d">`, len(w.links)-1)
+	content()
+	w.body.WriteString(`</w:hyperlink>`)
+}
+
+func (w *docxWriter) text(s string, style docxRunStyle) {
+	if s == "" {
+		return
+	}
+
+	w.body.WriteString(`<w:r>`)
+	if style != (docxRunStyle{}) {
+		w.body.WriteString(`<w:rPr>`)
+		if style.link {
+			w.body.WriteString(`<w:rStyle w:val="Hyperlink"/>`)
+		}
+		if style.code {
+			w.body.WriteString(`<w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/>`)
+		}
+		if style.bold {
+			w.body.WriteString(`<w:b/>`)
+		}
+		if style.italic {
+			w.body.WriteString(`<w:i/>`)
+		}
+		if style.strike {
+			w.body.WriteString(`<w:strike/>`)
+		}
+		w.body.WriteString(`</w:rPr>`)
+	}
+	w.body.WriteString(`<w:t xml:space="preserve">`)
+	w.body.WriteString(docxEscape(s))
+	w.body.WriteString(`</w:t></w:r>`)
+}
+
+func (w *docxWriter) document() string {
+	return xml.Header +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` +
+		w.body.String() +
+		`<w:sectPr><w:pgSz w:w="11906" w:h="16838"/><w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="708" w:footer="708" w:gutter="0"/></w:sectPr>` +
+		`</w:body></w:document>`
+}
+
+func (w *docxWriter) documentRels() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	sb.WriteString(`<Relationship Id="rIdStyles" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`)
+	sb.WriteString(`<Relationship Id="rIdNumbering" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering" Target="numbering.xml"/>`)
+	for i, target := range w.links {
+		fmt.Fprintf(
+			&sb,
+			`<Relationship Id="rIdLink%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`,
+			i,
+			docxEscape(target),
+		)
+	}
+	sb.WriteString(`</Relationships>`)
+
+	return sb.String()
+}
+
+func (w *docxWriter) numbering() string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<w:numbering xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">`)
+
+	bullets := []string{"•", "◦", "▪"}
+	for _, abstract := range []int{docxBulletList, docxDecimalList} {
+		fmt.Fprintf(&sb, `<w:abstractNum w:abstractNumId="%d"><w:multiLevelType w:val="hybridMultilevel"/>`, abstract)
+		for level := range 9 {
+			format, text := "decimal", fmt.Sprintf("%%%d.", level+1)
+			if abstract == docxBulletList {
+				format, text = "bullet", bullets[level%len(bullets)]
+			}
+			fmt.Fprintf(
+				&sb,
+				`<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="%s"/><w:lvlText w:val="%s"/><w:lvlJc w:val="left"/><w:pPr><w:ind w:left="%d" w:hanging="360"/></w:pPr></w:lvl>`,
+				level,
+				format,
+				text,
+				docxListIndent*(level+1),
+			)
+		}
+		sb.WriteString(`</w:abstractNum>`)
+	}
+
+	for i, list := range w.lists {
+		fmt.Fprintf(&sb, `<w:num w:numId="%d"><w:abstractNumId w:val="%d"/>`, i+1, list.abstractID)
+		if list.abstractID == docxDecimalList {
+			fmt.Fprintf(
+				&sb,
+				`<w:lvlOverride w:ilvl="%d"><w:startOverride w:val="%d"/></w:lvlOverride>`,
+				list.level,
+				max(list.start, 1),
+			)
+		}
+		sb.WriteString(`</w:num>`)
+	}
+	sb.WriteString(`</w:numbering>`)
+
+	return sb.String()
+}
+
+func docxEscape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+	return buf.String()
+}
+
+const docxContentTypes = xml.Header +
+	`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/word/document.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"/>` +
+	`<Override PartName="/word/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"/>` +
+	`<Override PartName="/word/numbering.xml" ContentType="application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"/>` +
+	`</Types>`
+
+const docxPackageRels = xml.Header +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="word/document.xml"/>` +
+	`</Relationships>`
+
+// docxStyles uses Word's built-in style ids, so the headings show up in the
+// navigation pane and the document picks up the reader's theme.
+var docxStyles = xml.Header +
+	`<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="Calibri" w:hAnsi="Calibri" w:cs="Calibri"/><w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="160" w:line="264" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:spacing w:after="80"/></w:pPr><w:rPr><w:b/><w:sz w:val="56"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:spacing w:after="360"/></w:pPr><w:rPr><w:color w:val="4B5563"/><w:sz w:val="26"/></w:rPr></w:style>` +
+	docxHeadingStyles() +
+	`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:qFormat/><w:pPr><w:spacing w:after="60"/><w:contextualSpacing/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:ind w:left="720"/></w:pPr><w:rPr><w:i/><w:color w:val="4B5563"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/><w:basedOn w:val="Normal"/><w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/><w:shd w:val="clear" w:color="auto" w:fill="F3F4F6"/></w:pPr><w:rPr><w:rFonts w:ascii="Consolas" w:hAnsi="Consolas" w:cs="Consolas"/><w:sz w:val="18"/></w:rPr></w:style>` +
+	`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:rPr><w:color w:val="2563EB"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/><w:tblPr><w:tblBorders>` +
+	`<w:top w:val="single" w:sz="4" w:space="0" w:color="D1D5DB"/><w:left w:val="single" w:sz="4" w:space="0" w:color="D1D5DB"/>` +
+	`<w:bottom w:val="single" w:sz="4" w:space="0" w:color="D1D5DB"/><w:right w:val="single" w:sz="4" w:space="0" w:color="D1D5DB"/>` +
+	`<w:insideH w:val="single" w:sz="4" w:space="0" w:color="D1D5DB"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="D1D5DB"/>` +
+	`</w:tblBorders><w:tblCellMar><w:left w:w="108" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+	`</w:styles>`
+
+func docxHeadingStyles() string {
+	sizes := []int{36, 30, 26, 24, 22, 22}
+
+	var sb strings.Builder
+	for i, size := range sizes {
+		fmt.Fprintf(
+			&sb,
+			`<w:style w:type="paragraph" w:styleId="Heading%d"><w:name w:val="heading %d"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/><w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="%d"/></w:pPr><w:rPr><w:b/><w:color w:val="111827"/><w:sz w:val="%d"/></w:rPr></w:style>`,
+			i+1,
+			i+1,
+			i,
+			size,
+		)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+const (
+	ReportExportPDF  = "pdf"
+	ReportExportDOCX = "docx"
+	ReportExportHTML = "html"
+)
+
+// ReportExportFormats lists the export formats in the order they are offered
+// for download.
+var ReportExportFormats = []string{ReportExportPDF, ReportExportDOCX, ReportExportHTML}
+
+// ErrReportNotReady is returned when exporting a report whose final report
+// has not been generated yet.
+var ErrReportNotReady = errors.New("the final report has not been generated yet")
+
+// ErrUnknownExportFormat is returned for formats without a renderer.
+var ErrUnknownExportFormat = errors.New("unknown export format")
+
+// reportExportVersion is part of every export fingerprint. Bump it when a
+// renderer changes so stored exports are rendered again.
+const reportExportVersion = "1"
+
+type reportExporter struct {
+	contentType string
+	render      func(report models.Report) ([]byte, error)
+}
+
+var reportExporters = map[string]reportExporter{
+	ReportExportPDF: {"application/pdf", RenderReportPDF},
+	ReportExportDOCX: {
+		"application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		RenderReportDOCX,
+	},
+	ReportExportHTML: {"text/html; charset=utf-8", RenderReportHTML},
+}
+
+// ExportReport renders the final report in the given format. Rendered exports
+// are stored and reused until the report content changes.
+func ExportReport(
+	ctx context.Context,
+	conn *sql.DB,
+	report models.Report,
+	format string,
+) ([]byte, error) {
+	exporter, ok := reportExporters[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownExportFormat, format)
+	}
+
+	if report.FinalReport == "" {
+		return nil, ErrReportNotReady
+	}
+
+	fingerprint := reportFingerprint(report)
+
+	stored, err := models.FindReportExport(ctx, conn, report.ID, format)
+	switch {
+	case err == nil && stored.Fingerprint == fingerprint:
+		return stored.Content, nil
+	case err != nil && !errors.Is(err, sql.ErrNoRows):
+		return nil, err
+	}
+
+	content, err := exporter.render(report)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := models.StoreReportExport(ctx, conn, models.StoreReportExportData{
+		ReportID:    report.ID,
+		Format:      format,
+		Fingerprint: fingerprint,
+		Content:     content,
+	}); err != nil {
+		return nil, err
+	}
+
+	return content, nil
+}
+
+// ReportExportContentType returns the media type of an export format.
+func ReportExportContentType(format string) string {
+	return reportExporters[format].contentType
+}
+
+// ReportExportFileName returns the download name of a report export.
+func ReportExportFileName(report models.Report, format string) string {
+	return fmt.Sprintf("%s-report.%s", slugify(report.CompanyName), format)
+}
+
+// reportGeneratedAt is the date printed on exports of a report.
+func reportGeneratedAt(report models.Report) time.Time {
+	if report.CompletedAt.IsZero() {
+		return report.UpdatedAt
+	}
+
+	return report.CompletedAt
+}
+
+func reportFingerprint(report models.Report) string {
+	sum := sha256.New()
+	for _, part := range []string{
+		reportExportVersion,
+		report.CompanyName,
+		report.CompletedAt.UTC().Format(time.RFC3339),
+		report.FinalReport,
+	} {
+		sum.Write([]byte(part))
+		sum.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(sum.Sum(nil))
+}
@@ -0,0 +1,99 @@
+package services
+
+import (
+	"bytes"
+	"html/template"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// RenderReportHTML renders the final report as a single self-contained HTML
+// file. The stylesheet is inlined and code blocks carry their highlighting
+// as inline styles, so the file opens the same anywhere, offline included.
+func RenderReportHTML(report models.Report) ([]byte, error) {
+	body, err := ParseMarkdownToHTML(report.FinalReport)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := htmlReportTemplate.Execute(&buf, struct {
+		CompanyName string
+		GeneratedAt string
+		Stylesheet  template.CSS
+		Body        template.HTML
+	}{
+		CompanyName: report.CompanyName,
+		GeneratedAt: reportGeneratedAt(report).Format("2 January 2006"),
+		Stylesheet:  template.CSS(htmlReportStylesheet),
+		Body:        template.HTML(body),
+	}); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+var htmlReportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.CompanyName}} – Company research report</title>
+<style>{{.Stylesheet}}</style>
+</head>
+<body>
+<header>
+<h1 class="title">{{.CompanyName}}</h1>
+<p class="subtitle">Company research report, generated {{.GeneratedAt}}</p>
+</header>
+<main>
+{{.Body}}
+</main>
+</body>
+</html>
+`))
+
+// htmlReportStylesheet follows the colours of the PDF and DOCX exports and
+// keeps the document readable when printed.
+const htmlReportStylesheet = `
+*, *::before, *::after { box-sizing: border-box; }
+body {
+	margin: 0 auto;
+	max-width: 52rem;
+	padding: 3rem 1.5rem;
+	font-family: -apple-system, BlinkMacSystemFont, "Segoe UI", Calibri, Helvetica, Arial, sans-serif;
+	font-size: 1rem;
+	line-height: 1.65;
+	color: #111827;
+	background: #ffffff;
+}
+header { margin-bottom: 2.5rem; padding-bottom: 1.5rem; border-bottom: 1px solid #d1d5db; }
+.title { margin: 0 0 0.25rem; font-size: 2.25rem; line-height: 1.2; }
+.subtitle { margin: 0; color: #4b5563; font-size: 1.1rem; }
+h1, h2, h3, h4, h5, h6 { margin: 2rem 0 0.75rem; line-height: 1.3; color: #111827; }
+h1 { font-size: 1.75rem; }
+h2 { font-size: 1.5rem; }
+h3 { font-size: 1.25rem; }
+h4, h5, h6 { font-size: 1.05rem; }
+p, ul, ol, blockquote, table, pre { margin: 0 0 1rem; }
+ul, ol { padding-left: 1.5rem; }
+li { margin-bottom: 0.25rem; }
+a { color: #2563eb; }
+blockquote { margin-left: 0; padding-left: 1rem; border-left: 3px solid #d1d5db; color: #4b5563; font-style: italic; }
+hr { border: 0; border-top: 1px solid #d1d5db; margin: 2rem 0; }
+code { font-family: Consolas, "SFMono-Regular", Menlo, monospace; font-size: 0.9em; background: #f3f4f6; padding: 0.1em 0.3em; border-radius: 3px; }
+pre { overflow-x: auto; padding: 0.75rem 1rem; background: #f3f4f6; border-radius: 4px; }
+pre code { padding: 0; background: none; }
+table { width: 100%; border-collapse: collapse; font-size: 0.95rem; }
+th, td { padding: 0.4rem 0.6rem; border: 1px solid #d1d5db; vertical-align: top; }
+th { background: #f9fafb; font-weight: 600; }
+th:not([align]) { text-align: left; }
+img { max-width: 100%; }
+@media print {
+	body { max-width: none; padding: 0; }
+	a { color: inherit; }
+	h1, h2, h3, h4, h5, h6 { break-after: avoid; }
+	tr, pre, blockquote { break-inside: avoid; }
+}
+`
@@ -7,9 +7,12 @@ import (
 	chromahtml "github.com/alecthomas/chroma/v2/formatters/html"
 	"github.com/yuin/goldmark"
 	highlighting "github.com/yuin/goldmark-highlighting/v2"
+	"github.com/yuin/goldmark/ast"
 	"github.com/yuin/goldmark/extension"
 	"github.com/yuin/goldmark/parser"
 	"github.com/yuin/goldmark/renderer/html"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
 )
 
 func newParser() goldmark.Markdown {
@@ -26,16 +29,43 @@ func newParser() goldmark.Markdown {
 		),
 		goldmark.WithParserOptions(
 			parser.WithAutoHeadingID(),
-			parser.WithAttribute(),
+			parser.WithASTTransformers(
+				util.Prioritized(dangerousAutoLinkTransformer{}, 100),
+			),
 		),
+		// Reports embed text from fetched pages and are shown on public share
+		// links, so raw HTML and dangerous link URLs are left out of the output
+		// rather than passed through.
 		goldmark.WithRendererOptions(
 			html.WithHardWraps(),
 			html.WithXHTML(),
-			html.WithUnsafe(),
 		),
 	)
 }
 
+// dangerousAutoLinkTransformer turns autolinks such as <javascript:...> into
+// plain text. goldmark drops dangerous URLs from regular links and images,
+// but renders autolinks as given.
+type dangerousAutoLinkTransformer struct{}
+
+func (dangerousAutoLinkTransformer) Transform(doc *ast.Document, reader text.Reader, _ parser.Context) {
+	source := reader.Source()
+
+	var dangerous []*ast.AutoLink
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if link, ok := n.(*ast.AutoLink); ok && entering &&
+			link.AutoLinkType == ast.AutoLinkURL && html.IsDangerousURL(link.URL(source)) {
+			dangerous = append(dangerous, link)
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	for _, link := range dangerous {
+		link.Parent().ReplaceChild(link.Parent(), link, ast.NewString(link.Label(source)))
+	}
+}
+
 func cleanMarkdownBlock(report string) string {
 	report = strings.TrimSpace(report)
 
@@ -66,3 +96,37 @@ func ParseMarkdownToHTML(content string) (string, error) {
 
 	return htmlOutput.String(), nil
 }
+
+// parseMarkdown parses a report into a goldmark AST for the export
+// renderers. The returned source backs the text segments of the nodes.
+func parseMarkdown(content string) (ast.Node, []byte) {
+	source := []byte(cleanMarkdownBlock(content))
+	return newParser().Parser().Parse(text.NewReader(source)), source
+}
+
+// markdownText returns the plain text below a node with all inline markup
+// stripped.
+func markdownText(n ast.Node, source []byte) string {
+	var sb strings.Builder
+	_ = ast.Walk(n, func(child ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+
+		switch child := child.(type) {
+		case *ast.Text:
+			sb.Write(child.Segment.Value(source))
+			if child.SoftLineBreak() || child.HardLineBreak() {
+				sb.WriteByte(' ')
+			}
+		case *ast.String:
+			sb.Write(child.Value)
+		case *ast.AutoLink:
+			sb.Write(child.URL(source))
+		}
+
+		return ast.WalkContinue, nil
+	})
+
+	return strings.TrimSpace(sb.String())
+}
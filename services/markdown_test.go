@@ -0,0 +1,79 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mbvlabs/plyo-hackathon/agents"
+)
+
+func TestParseMarkdownToHTMLOmitsUnsafeContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		unsafe   string
+	}{
+		{name: "script block", markdown: "<script>alert(1)</script>", unsafe: "<script"},
+		{name: "inline script", markdown: "See <script>alert(1)</script> here", unsafe: "<script"},
+		{name: "event handler", markdown: `<img src="x" onerror="alert(1)">`, unsafe: "onerror"},
+		{name: "iframe", markdown: `<iframe src="https://attacker.example"></iframe>`, unsafe: "<iframe"},
+		{name: "javascript link", markdown: "[click](javascript:alert(1))", unsafe: `href="javascript:`},
+		{name: "javascript autolink", markdown: "<javascript:alert(1)>", unsafe: `href="javascript:`},
+		{name: "data link", markdown: "[click](data:text/html;base64,PHNjcmlwdD4=)", unsafe: `href="data:text/html`},
+		{name: "heading attributes", markdown: "# Title {onclick=\"alert(1)\" style=\"position:fixed\"}", unsafe: `onclick="`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMarkdownToHTML(tt.markdown)
+			if err != nil {
+				t.Fatalf("ParseMarkdownToHTML() error = %v", err)
+			}
+			if strings.Contains(got, tt.unsafe) {
+				t.Fatalf("ParseMarkdownToHTML() = %q, contains %q", got, tt.unsafe)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownToHTMLKeepsFormatting(t *testing.T) {
+	got, err := ParseMarkdownToHTML("# Findings\n\nSee [the source](https://example.com) and **this**.\n\n| a | b |\n|---|---|\n| 1 | 2 |")
+	if err != nil {
+		t.Fatalf("ParseMarkdownToHTML() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<h1 id="findings">Findings</h1>`,
+		`<a href="https://example.com">the source</a>`,
+		"<strong>this</strong>",
+		"<table>",
+	} {
+		if !strings.Contains(got, want) {
+			t.Fatalf("ParseMarkdownToHTML() = %q, missing %q", got, want)
+		}
+	}
+}
+
+func TestParseMarkdownToHTMLEscapesChangeSummaries(t *testing.T) {
+	summary := agents.ChangeSummary{
+		Headline: `New CEO <img src=x onerror="alert(1)">`,
+		Changes: []agents.MaterialChange{
+			{Category: "leadership", Description: "<script>alert(1)</script> joined as CEO"},
+			{Category: "product", Description: "Launched [a page](javascript:alert(1))"},
+		},
+	}
+
+	got, err := ParseMarkdownToHTML(summary.Markdown())
+	if err != nil {
+		t.Fatalf("ParseMarkdownToHTML() error = %v", err)
+	}
+
+	for _, unsafe := range []string{"<img", "<script", `href="javascript:`} {
+		if strings.Contains(got, unsafe) {
+			t.Fatalf("ParseMarkdownToHTML() = %q, contains %q", got, unsafe)
+		}
+	}
+	if !strings.Contains(got, "<strong>leadership</strong>") {
+		t.Fatalf("ParseMarkdownToHTML() = %q, lost the change list", got)
+	}
+}
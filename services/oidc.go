@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/providers"
+)
+
+var (
+	ErrOIDCEmailMissing = errors.New("the identity provider did not share a verified email address")
+	ErrOIDCNoRole       = errors.New("your account is not in any group that has access")
+	// ErrOIDCAccountConflict is returned when the email of the signed-in
+	// identity belongs to an account linked to another identity.
+	ErrOIDCAccountConflict = errors.New("an account with this email is linked to another single sign-on identity")
+	// ErrOIDCAccountExists is returned when the email of the signed-in
+	// identity belongs to an account that may not be linked to it yet.
+	ErrOIDCAccountExists = errors.New("an account with this email already exists, sign in with your password first and then sign in with single sign-on again to link it")
+)
+
+// OIDCAccountLinking decides when a first single sign-on may take over an
+// existing account with the same verified email, which would otherwise hand
+// it to whoever controls that email at the identity provider.
+type OIDCAccountLinking struct {
+	// SignedInUserID is the user signed in while going through single
+	// sign-on, confirming that the account is theirs, or uuid.Nil.
+	SignedInUserID uuid.UUID
+	// ByEmail links any existing account by its email alone.
+	ByEmail bool
+}
+
+// OIDCRoleMapping decides the workspace role of a user signing in through
+// single sign-on from their identity provider groups.
+type OIDCRoleMapping struct {
+	Groups map[string]string
+	// DefaultRole is given to users in none of the mapped groups. Such users
+	// are refused when it is empty.
+	DefaultRole string
+}
+
+// OIDCNoDefaultRole configures single sign-on to refuse users in none of
+// the mapped groups.
+const OIDCNoDefaultRole = "none"
+
+// ParseOIDCRoleMapping parses comma separated group=role pairs, e.g.
+// "deal-leads=owner,analysts=editor". A defaultRole of OIDCNoDefaultRole
+// refuses users in none of the groups.
+func ParseOIDCRoleMapping(mapping string, defaultRole string) (OIDCRoleMapping, error) {
+	if defaultRole == OIDCNoDefaultRole {
+		defaultRole = ""
+	}
+	parsed := OIDCRoleMapping{Groups: map[string]string{}, DefaultRole: defaultRole}
+
+	if defaultRole != "" && !slices.Contains(models.WorkspaceRoles, defaultRole) {
+		return OIDCRoleMapping{}, fmt.Errorf("unknown default role %q", defaultRole)
+	}
+
+	for pair := range strings.SplitSeq(mapping, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" {
+			return OIDCRoleMapping{}, fmt.Errorf("malformed group mapping %q, use group=role", pair)
+		}
+		if !slices.Contains(models.WorkspaceRoles, role) {
+			return OIDCRoleMapping{}, fmt.Errorf("unknown role %q for group %q", role, group)
+		}
+
+		parsed.Groups[group] = role
+	}
+
+	return parsed, nil
+}
+
+// Role returns the most privileged role any of the groups maps to, or the
+// default role. It returns false when the user gets no role.
+func (m OIDCRoleMapping) Role(groups []string) (string, bool) {
+	role := m.DefaultRole
+	for _, group := range groups {
+		mapped, ok := m.Groups[group]
+		if ok && (role == "" || models.WorkspaceRoleAllows(mapped, role)) {
+			role = mapped
+		}
+	}
+
+	return role, role != ""
+}
+
+// SignInWithOIDC returns the account of a user who signed in through single
+// sign-on, provisioning it on first sign-in. An existing account with the
+// same verified email is only linked to the identity as linking allows. The
+// user's role in the workspace follows their groups on every sign-in, so the
+// identity provider stays the source of truth for who can do what there.
+func SignInWithOIDC(
+	ctx context.Context,
+	conn *sql.DB,
+	claims providers.OIDCClaims,
+	workspaceID uuid.UUID,
+	mapping OIDCRoleMapping,
+	linking OIDCAccountLinking,
+	now time.Time,
+) (models.User, error) {
+	role, ok := mapping.Role(claims.Groups)
+	if !ok {
+		return models.User{}, ErrOIDCNoRole
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.User{}, err
+	}
+	defer tx.Rollback()
+
+	user, err := models.FindUserByOIDCIdentity(ctx, tx, claims.Issuer, claims.Subject)
+	if errors.Is(err, sql.ErrNoRows) {
+		user, err = provisionOIDCUser(ctx, tx, claims, linking)
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	membership, err := models.FindWorkspaceMembershipByUserID(ctx, tx, workspaceID, user.ID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = models.CreateWorkspaceMembership(ctx, tx, models.CreateWorkspaceMembershipData{
+			WorkspaceID: workspaceID,
+			UserID:      user.ID,
+			Role:        role,
+		})
+	case err == nil && membership.Role != role:
+		_, err = models.UpdateWorkspaceMembershipRole(ctx, tx, models.UpdateWorkspaceMembershipRoleData{
+			ID:          membership.ID,
+			WorkspaceID: workspaceID,
+			Role:        role,
+		})
+	}
+	if err != nil {
+		return models.User{}, err
+	}
+
+	if err := models.TouchUserLogin(ctx, tx, user.ID, now); err != nil {
+		return models.User{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.User{}, err
+	}
+
+	return user, nil
+}
+
+func provisionOIDCUser(
+	ctx context.Context,
+	tx *sql.Tx,
+	claims providers.OIDCClaims,
+	linking OIDCAccountLinking,
+) (models.User, error) {
+	if claims.Email == "" || !claims.EmailVerified {
+		return models.User{}, ErrOIDCEmailMissing
+	}
+
+	user, err := models.FindUserByEmail(ctx, tx, claims.Email)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.CreateUser(ctx, tx, models.CreateUserData{
+			Email:       claims.Email,
+			OIDCIssuer:  claims.Issuer,
+			OIDCSubject: claims.Subject,
+		})
+	case err != nil:
+		return models.User{}, err
+	case user.OIDCSubject != "":
+		return models.User{}, ErrOIDCAccountConflict
+	case !linking.ByEmail && user.ID != linking.SignedInUserID:
+		return models.User{}, ErrOIDCAccountExists
+	}
+
+	if err := models.LinkUserOIDCIdentity(ctx, tx, user.ID, claims.Issuer, claims.Subject); err != nil {
+		return models.User{}, err
+	}
+
+	user.OIDCIssuer = claims.Issuer
+	user.OIDCSubject = claims.Subject
+
+	return user, nil
+}
@@ -0,0 +1,531 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/yuin/goldmark/ast"
+	extast "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// A4 page layout, in millimetres and points for font sizes.
+const (
+	pdfMargin        = 20.0
+	pdfFont          = "Helvetica"
+	pdfMonoFont      = "Courier"
+	pdfBodyFontSize  = 11.0
+	pdfTableFontSize = 9.0
+	pdfLineHeight    = 5.5
+	pdfIndent        = 7.0
+	pdfTOCPageWidth  = 12.0
+	// pdfTOCMaxLevel is the deepest heading level listed in the table of
+	// contents.
+	pdfTOCMaxLevel = 2
+)
+
+// pdfTextReplacer maps characters the core PDF fonts cannot show to close
+// ASCII equivalents. Everything else outside Windows-1252 is dropped by the
+// font translator.
+var pdfTextReplacer = strings.NewReplacer(
+	"\u00a0", " ",
+	"\u2011", "-",
+	"\u2212", "-",
+	"\u2192", "->",
+	"\u2190", "<-",
+	"\u2265", ">=",
+	"\u2264", "<=",
+	"\u2248", "~",
+)
+
+type pdfHeading struct {
+	level int
+	text  string
+	page  int
+}
+
+type pdfRenderer struct {
+	pdf       *fpdf.Fpdf
+	translate func(string) string
+	source    []byte
+	headings  []pdfHeading
+	links     []int
+	// nextHeading is the index of the next table of contents entry to be
+	// reached while rendering the body.
+	nextHeading int
+
+	fontSize  float64
+	bold      int
+	italic    int
+	underline bool
+	mono      bool
+}
+
+// RenderReportPDF renders the final report as an A4 PDF with a cover page, a
+// linked table of contents and page numbers. Only the core PDF fonts are
+// used, so no font files or browser are needed on the server.
+func RenderReportPDF(report models.Report) ([]byte, error) {
+	doc, source := parseMarkdown(report.FinalReport)
+
+	var headings []pdfHeading
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if heading, ok := n.(*ast.Heading); ok && entering && heading.Level <= pdfTOCMaxLevel {
+			headings = append(headings, pdfHeading{
+				level: heading.Level,
+				text:  markdownText(heading, source),
+			})
+		}
+		return ast.WalkContinue, nil
+	})
+
+	// The page of each heading is only known once the body has been laid
+	// out, so the document is rendered twice: the first pass records the
+	// pages and the second prints them in the table of contents. Only the
+	// numbers change between the passes, so the layout stays the same.
+	if _, err := renderPDF(report, doc, source, headings); err != nil {
+		return nil, err
+	}
+
+	pdf, err := renderPDF(report, doc, source, headings)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func renderPDF(
+	report models.Report,
+	doc ast.Node,
+	source []byte,
+	headings []pdfHeading,
+) (*fpdf.Fpdf, error) {
+	generatedAt := reportGeneratedAt(report)
+
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.AliasNbPages("")
+	pdf.SetTitle(fmt.Sprintf("%s research report", report.CompanyName), true)
+	pdf.SetCreationDate(generatedAt)
+	pdf.SetModificationDate(generatedAt)
+
+	r := &pdfRenderer{
+		pdf:       pdf,
+		translate: pdf.UnicodeTranslatorFromDescriptor(""),
+		source:    source,
+		headings:  headings,
+		links:     make([]int, len(headings)),
+		fontSize:  pdfBodyFontSize,
+	}
+	for i := range headings {
+		r.links[i] = pdf.AddLink()
+	}
+
+	pdf.SetHeaderFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.SetTextColor(107, 114, 128)
+		pdf.SetY(10)
+		pdf.CellFormat(0, 5, r.text(report.CompanyName), "", 0, "L", false, 0, "")
+		pdf.CellFormat(0, 5, generatedAt.Format("2 January 2006"), "", 0, "R", false, 0, "")
+		pdf.SetXY(pdfMargin, pdfMargin)
+	})
+	pdf.SetFooterFunc(func() {
+		if pdf.PageNo() == 1 {
+			return
+		}
+		pdf.SetY(-15)
+		pdf.SetFont(pdfFont, "", 8)
+		pdf.SetTextColor(107, 114, 128)
+		pdf.CellFormat(0, 10, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	r.coverPage(report, generatedAt)
+	if len(headings) > 0 {
+		r.tableOfContents()
+	}
+
+	pdf.AddPage()
+	r.setFont()
+	r.blocks(doc)
+
+	return pdf, pdf.Error()
+}
+
+func (r *pdfRenderer) coverPage(report models.Report, generatedAt time.Time) {
+	r.pdf.AddPage()
+
+	r.pdf.SetDrawColor(59, 130, 246)
+	r.pdf.SetLineWidth(1)
+	r.pdf.Line(pdfMargin, 85, pdfMargin+40, 85)
+	r.pdf.SetLineWidth(0.2)
+
+	r.pdf.SetY(92)
+	r.pdf.SetTextColor(17, 24, 39)
+	r.pdf.SetFont(pdfFont, "B", 28)
+	r.pdf.MultiCell(0, 12, r.text(report.CompanyName), "", "L", false)
+
+	r.pdf.Ln(4)
+	r.pdf.SetTextColor(75, 85, 99)
+	r.pdf.SetFont(pdfFont, "", 14)
+	r.pdf.MultiCell(0, 7, "Company research report", "", "L", false)
+
+	r.pdf.Ln(24)
+	r.pdf.SetFont(pdfFont, "", pdfBodyFontSize)
+	r.pdf.MultiCell(0, 6, "Generated "+generatedAt.Format("2 January 2006"), "", "L", false)
+}
+
+func (r *pdfRenderer) tableOfContents() {
+	r.pdf.AddPage()
+	r.pdf.SetTextColor(17, 24, 39)
+	r.pdf.SetFont(pdfFont, "B", 18)
+	r.pdf.CellFormat(0, 10, "Contents", "", 1, "L", false, 0, "")
+	r.pdf.Ln(4)
+
+	pageWidth, _ := r.pdf.GetPageSize()
+	width := pageWidth - 2*pdfMargin
+
+	for i, heading := range r.headings {
+		indent := float64(heading.level-1) * pdfIndent
+		style := ""
+		if heading.level == 1 {
+			style = "B"
+		}
+		r.pdf.SetFont(pdfFont, style, pdfBodyFontSize)
+		r.pdf.SetX(pdfMargin + indent)
+
+		titleWidth := width - indent - pdfTOCPageWidth
+		r.pdf.CellFormat(titleWidth, 7, r.fit(r.text(heading.text), titleWidth), "", 0, "L", false, r.links[i], "")
+		r.pdf.CellFormat(pdfTOCPageWidth, 7, strconv.Itoa(heading.page), "", 1, "R", false, r.links[i], "")
+	}
+}
+
+func (r *pdfRenderer) blocks(parent ast.Node) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		r.block(n)
+	}
+}
+
+func (r *pdfRenderer) block(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Heading:
+		r.heading(n)
+	case *ast.Paragraph:
+		r.inlines(n)
+		r.pdf.Ln(pdfLineHeight + 2)
+	case *ast.TextBlock:
+		r.inlines(n)
+		r.pdf.Ln(pdfLineHeight)
+	case *ast.List:
+		r.list(n)
+		r.pdf.Ln(2)
+	case *ast.Blockquote:
+		r.blockquote(n)
+	case *ast.FencedCodeBlock:
+		r.code(n.Lines())
+	case *ast.CodeBlock:
+		r.code(n.Lines())
+	case *ast.ThematicBreak:
+		pageWidth, _ := r.pdf.GetPageSize()
+		y := r.pdf.GetY() + 2
+		r.pdf.SetDrawColor(209, 213, 219)
+		r.pdf.Line(pdfMargin, y, pageWidth-pdfMargin, y)
+		r.pdf.Ln(6)
+	case *extast.Table:
+		r.table(n)
+	case *ast.HTMLBlock:
+		// Raw HTML has no counterpart in the PDF.
+	default:
+		r.blocks(n)
+	}
+}
+
+func (r *pdfRenderer) heading(n *ast.Heading) {
+	size := 12.0
+	switch n.Level {
+	case 1:
+		size = 18
+	case 2:
+		size = 15
+	case 3:
+		size = 13
+	}
+
+	// Keep a heading on the same page as the first lines below it.
+	_, pageHeight := r.pdf.GetPageSize()
+	if r.pdf.GetY() > pageHeight-pdfMargin-30 {
+		r.pdf.AddPage()
+	} else if r.pdf.GetY() > pdfMargin {
+		r.pdf.Ln(3)
+	}
+
+	if n.Level <= pdfTOCMaxLevel && r.nextHeading < len(r.headings) {
+		r.headings[r.nextHeading].page = r.pdf.PageNo()
+		r.pdf.SetLink(r.links[r.nextHeading], r.pdf.GetY(), -1)
+		r.nextHeading++
+	}
+
+	r.pdf.SetTextColor(17, 24, 39)
+	r.pdf.SetFont(pdfFont, "B", size)
+	r.pdf.MultiCell(0, size*0.5, r.text(markdownText(n, r.source)), "", "L", false)
+	r.pdf.Ln(2)
+	r.setFont()
+}
+
+func (r *pdfRenderer) list(n *ast.List) {
+	left, _, _, _ := r.pdf.GetMargins()
+	number := n.Start
+
+	for item := n.FirstChild(); item != nil; item = item.NextSibling() {
+		marker := "•"
+		if n.IsOrdered() {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		r.pdf.SetLeftMargin(left)
+		r.pdf.SetX(left)
+		r.setFont()
+		r.pdf.CellFormat(pdfIndent, pdfLineHeight, r.text(marker), "", 0, "L", false, 0, "")
+
+		// Wrapped lines of the item align with its first line rather than
+		// the marker.
+		r.pdf.SetLeftMargin(left + pdfIndent)
+		for child := item.FirstChild(); child != nil; child = child.NextSibling() {
+			switch child := child.(type) {
+			case *ast.TextBlock, *ast.Paragraph:
+				r.inlines(child)
+				r.pdf.Ln(pdfLineHeight)
+			case *ast.List:
+				r.list(child)
+			default:
+				r.block(child)
+			}
+		}
+	}
+
+	r.pdf.SetLeftMargin(left)
+	r.pdf.SetX(left)
+}
+
+func (r *pdfRenderer) blockquote(n *ast.Blockquote) {
+	left, _, _, _ := r.pdf.GetMargins()
+	r.pdf.SetLeftMargin(left + pdfIndent)
+	r.pdf.SetX(left + pdfIndent)
+
+	r.italic++
+	r.blocks(n)
+	r.italic--
+
+	r.pdf.SetLeftMargin(left)
+	r.pdf.SetX(left)
+	r.setFont()
+}
+
+func (r *pdfRenderer) code(lines *text.Segments) {
+	var sb strings.Builder
+	for i := range lines.Len() {
+		segment := lines.At(i)
+		sb.Write(segment.Value(r.source))
+	}
+
+	r.pdf.SetFont(pdfMonoFont, "", 9)
+	r.pdf.SetFillColor(243, 244, 246)
+	r.pdf.MultiCell(0, 4.5, r.text(strings.TrimRight(sb.String(), "\n")), "", "L", true)
+	r.pdf.Ln(3)
+	r.setFont()
+}
+
+func (r *pdfRenderer) table(n *extast.Table) {
+	columns := len(n.Alignments)
+	if columns == 0 {
+		return
+	}
+
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+	left, _, right, _ := r.pdf.GetMargins()
+	columnWidth := (pageWidth - left - right) / float64(columns)
+	lineHeight := 4.5
+
+	r.fontSize = pdfTableFontSize
+	defer func() {
+		r.fontSize = pdfBodyFontSize
+		r.bold = 0
+		r.setFont()
+	}()
+
+	for row := n.FirstChild(); row != nil; row = row.NextSibling() {
+		_, header := row.(*extast.TableHeader)
+		r.bold = 0
+		if header {
+			r.bold = 1
+		}
+		r.setFont()
+
+		cells := make([][][]byte, columns)
+		height := lineHeight
+		column := 0
+		for cell := row.FirstChild(); cell != nil && column < columns; cell = cell.NextSibling() {
+			cells[column] = r.pdf.SplitLines([]byte(r.text(markdownText(cell, r.source))), columnWidth-2)
+			height = max(height, float64(len(cells[column]))*lineHeight)
+			column++
+		}
+		height += 2
+
+		if r.pdf.GetY()+height > pageHeight-pdfMargin {
+			r.pdf.AddPage()
+			r.setFont()
+		}
+
+		y := r.pdf.GetY()
+		r.pdf.SetDrawColor(209, 213, 219)
+		r.pdf.SetFillColor(243, 244, 246)
+		for i, lines := range cells {
+			x := left + float64(i)*columnWidth
+			style := "D"
+			if header {
+				style = "FD"
+			}
+			r.pdf.Rect(x, y, columnWidth, height, style)
+
+			align := "L"
+			switch n.Alignments[i] {
+			case extast.AlignCenter:
+				align = "C"
+			case extast.AlignRight:
+				align = "R"
+			}
+			for l, line := range lines {
+				r.pdf.SetXY(x+1, y+1+float64(l)*lineHeight)
+				r.pdf.CellFormat(columnWidth-2, lineHeight, string(line), "", 0, align, false, 0, "")
+			}
+		}
+		r.pdf.SetXY(left, y+height)
+	}
+
+	r.pdf.Ln(4)
+}
+
+func (r *pdfRenderer) inlines(parent ast.Node) {
+	for n := parent.FirstChild(); n != nil; n = n.NextSibling() {
+		r.inline(n)
+	}
+}
+
+func (r *pdfRenderer) inline(n ast.Node) {
+	switch n := n.(type) {
+	case *ast.Text:
+		r.write(string(n.Segment.Value(r.source)))
+		if n.HardLineBreak() {
+			r.pdf.Ln(pdfLineHeight)
+		} else if n.SoftLineBreak() {
+			r.write(" ")
+		}
+	case *ast.String:
+		r.write(string(n.Value))
+	case *ast.Emphasis:
+		if n.Level >= 2 {
+			r.bold++
+		} else {
+			r.italic++
+		}
+		r.setFont()
+		r.inlines(n)
+		if n.Level >= 2 {
+			r.bold--
+		} else {
+			r.italic--
+		}
+		r.setFont()
+	case *ast.CodeSpan:
+		r.mono = true
+		r.setFont()
+		r.write(markdownText(n, r.source))
+		r.mono = false
+		r.setFont()
+	case *ast.Link:
+		r.link(markdownText(n, r.source), string(n.Destination))
+	case *ast.AutoLink:
+		url := string(n.URL(r.source))
+		target := url
+		if n.AutoLinkType == ast.AutoLinkEmail {
+			target = "mailto:" + url
+		}
+		r.link(url, target)
+	case *ast.Image:
+		r.write(markdownText(n, r.source))
+	case *ast.RawHTML:
+		// Inline HTML has no counterpart in the PDF.
+	default:
+		r.inlines(n)
+	}
+}
+
+func (r *pdfRenderer) link(label string, target string) {
+	r.underline = true
+	r.setFont()
+	r.pdf.SetTextColor(37, 99, 235)
+	r.pdf.WriteLinkString(pdfLineHeight, r.text(label), target)
+	r.underline = false
+	r.setFont()
+}
+
+func (r *pdfRenderer) write(s string) {
+	r.pdf.Write(pdfLineHeight, r.text(s))
+}
+
+// setFont applies the current inline style and resets the text colour.
+func (r *pdfRenderer) setFont() {
+	family := pdfFont
+	if r.mono {
+		family = pdfMonoFont
+	}
+
+	var style strings.Builder
+	if r.bold > 0 {
+		style.WriteString("B")
+	}
+	if r.italic > 0 {
+		style.WriteString("I")
+	}
+	if r.underline {
+		style.WriteString("U")
+	}
+
+	r.pdf.SetFont(family, style.String(), r.fontSize)
+	r.pdf.SetTextColor(31, 41, 55)
+}
+
+// text converts UTF-8 to the Windows-1252 encoding of the core fonts.
+func (r *pdfRenderer) text(s string) string {
+	return r.translate(pdfTextReplacer.Replace(s))
+}
+
+// fit shortens already translated text with an ellipsis until it fits in
+// width.
+func (r *pdfRenderer) fit(s string, width float64) string {
+	if r.pdf.GetStringWidth(s) <= width-2 {
+		return s
+	}
+
+	ellipsis := r.text("…")
+	for len(s) > 0 && r.pdf.GetStringWidth(s+ellipsis) > width-2 {
+		s = s[:len(s)-1]
+	}
+
+	return s + ellipsis
+}
@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/providers"
+	"github.com/mbvlabs/plyo-hackathon/tools"
+)
+
+// ErrAmbiguousCompany is returned by Pipeline.Run when preliminary research
+// found several companies and none could be picked without a human.
+var ErrAmbiguousCompany = errors.New("company could not be identified unambiguously")
+
+type domainResearcher interface {
+	Research(
+		ctx context.Context,
+		companyName string,
+		companyURL string,
+		prior agents.PriorFindings,
+	) (string, error)
+}
+
+type domainStep struct {
+	agent domainResearcher
+	store func(ctx context.Context, conn *sql.DB, reportID uuid.UUID, data string) error
+	// maxAge is how long the section's findings on a company are offered to
+	// later reports on it.
+	maxAge time.Duration
+}
+
+// FindingsFreshness holds how long the findings of each domain section are
+// reused by later reports on the same company. Zero disables reuse for the
+// section.
+type FindingsFreshness struct {
+	CompanyIntelligence     time.Duration
+	CompetitiveIntelligence time.Duration
+	MarketDynamics          time.Duration
+	TrendAnalysis           time.Duration
+}
+
+// Pipeline runs the research agents and stores their findings. The web job
+// handlers and the CLI share it so both produce the same reports.
+type Pipeline struct {
+	preliminary agents.PreliminaryResearch
+	validator   agents.DataValidation
+	generator   agents.ReportGenerator
+	domains     map[string]domainStep
+}
+
+func NewPipeline(
+	client providers.Client,
+	researchTools ResearchTools,
+	freshness FindingsFreshness,
+) Pipeline {
+	return Pipeline{
+		preliminary: agents.NewPreliminaryResearch(client, researchTools.PreliminaryResearch),
+		validator:   agents.NewDataValidation(client, researchTools.DataValidation),
+		generator:   agents.NewReportGenerator(client, tools.Toolset{}),
+		domains: map[string]domainStep{
+			agents.CompanyIntelligenceJobName: {
+				agents.NewCompanyIntelligence(client, researchTools.CompanyIntelligence),
+				func(ctx context.Context, conn *sql.DB, reportID uuid.UUID, data string) error {
+					return models.UpdateCompanyIntelligence(ctx, conn, reportID, data)
+				},
+				freshness.CompanyIntelligence,
+			},
+			agents.CompetitiveIntelligenceJobName: {
+				agents.NewCompetitiveIntelligence(client, researchTools.CompetitiveIntelligence),
+				func(ctx context.Context, conn *sql.DB, reportID uuid.UUID, data string) error {
+					return models.UpdateCompetitiveIntelligence(ctx, conn, reportID, data)
+				},
+				freshness.CompetitiveIntelligence,
+			},
+			agents.MarketDynamicsJobName: {
+				agents.NewMarketDynamics(client, researchTools.MarketDynamics),
+				func(ctx context.Context, conn *sql.DB, reportID uuid.UUID, data string) error {
+					return models.UpdateMarketDynamics(ctx, conn, reportID, data)
+				},
+				freshness.MarketDynamics,
+			},
+			agents.TrendAnalysisJobName: {
+				agents.NewTrendAnalysis(client, researchTools.TrendAnalysis),
+				func(ctx context.Context, conn *sql.DB, reportID uuid.UUID, data string) error {
+					return models.UpdateTrendAnalysis(ctx, conn, reportID, data)
+				},
+				freshness.TrendAnalysis,
+			},
+		},
+	}
+}
+
+func (p Pipeline) PreliminaryResearch() agents.PreliminaryResearch {
+	return p.preliminary
+}
+
+// ResearchDomain runs the domain agent registered under jobName, validates
+// its findings and stores them on the report and, when the report is on a
+// known company, as the company's latest findings for the section. Fresh
+// findings of an earlier report on the company are offered to the agent, so
+// it only researches what is stale or missing.
+func (p Pipeline) ResearchDomain(
+	ctx context.Context,
+	conn *sql.DB,
+	jobName string,
+	reportID uuid.UUID,
+	companyName string,
+	companyURL string,
+) error {
+	step, ok := p.domains[jobName]
+	if !ok {
+		return fmt.Errorf("unknown research domain %q", jobName)
+	}
+
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return err
+	}
+	ctx = tools.WithCallScope(ctx, report.WorkspaceID, reportID.String())
+
+	section := reportSections[jobName]
+	prior := priorFinding(ctx, conn, report, section, step.maxAge)
+
+	result, err := step.agent.Research(ctx, companyName, companyURL, agents.PriorFindings{
+		Findings: prior.Data,
+		AsOf:     prior.AsOf,
+	})
+	if err != nil {
+		return err
+	}
+
+	validatedResult, err := p.validator.Research(ctx, companyName, companyURL, result)
+	if err != nil {
+		return err
+	}
+
+	if err := step.store(ctx, conn, reportID, validatedResult); err != nil {
+		return err
+	}
+
+	if report.CompanyID != "" {
+		// Findings built on prior ones are only as fresh as those, so
+		// facts carried over are researched again once they expire.
+		asOf, priorFindingID := time.Now(), ""
+		if prior.Data != "" {
+			asOf, priorFindingID = prior.AsOf, prior.ID.String()
+		}
+
+		if _, err := models.CreateCompanyFinding(ctx, conn, models.CreateCompanyFindingData{
+			CompanyID:      uuid.MustParse(report.CompanyID),
+			ReportID:       reportID.String(),
+			PriorFindingID: priorFindingID,
+			Section:        section,
+			Data:           validatedResult,
+			AsOf:           asOf,
+		}); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to store company finding",
+				"error", err,
+				"report_id", reportID,
+				"section", section,
+			)
+		}
+	}
+
+	return models.UpdateReportProgress(ctx, conn, reportID)
+}
+
+// priorFinding returns the company's latest findings for section when they
+// are younger than maxAge, and an empty finding otherwise. Failing to look
+// them up only costs a research from scratch, so it is logged and ignored.
+func priorFinding(
+	ctx context.Context,
+	conn *sql.DB,
+	report models.Report,
+	section string,
+	maxAge time.Duration,
+) models.CompanyFinding {
+	if report.CompanyID == "" || maxAge <= 0 {
+		return models.CompanyFinding{}
+	}
+
+	finding, err := models.FindLatestCompanyFinding(ctx, conn, uuid.MustParse(report.CompanyID), section)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.ErrorContext(
+				ctx,
+				"failed to fetch company finding",
+				"error", err,
+				"report_id", report.ID,
+				"section", section,
+			)
+		}
+		return models.CompanyFinding{}
+	}
+
+	if !finding.Fresh(time.Now(), maxAge) {
+		return models.CompanyFinding{}
+	}
+
+	slog.InfoContext(
+		ctx,
+		"reusing company finding",
+		"report_id", report.ID,
+		"section", section,
+		"finding_id", finding.ID,
+		"as_of", finding.AsOf,
+	)
+
+	return finding
+}
+
+// GenerateReport writes the final report from the stored domain findings
+// following the given section template.
+func (p Pipeline) GenerateReport(
+	ctx context.Context,
+	conn *sql.DB,
+	reportID uuid.UUID,
+	companyName string,
+	companyURL string,
+	template string,
+) (string, error) {
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return "", err
+	}
+
+	if template == "" {
+		template = agents.ReportTemplates[agents.DefaultReportTemplate]
+	}
+
+	result, err := p.generator.GenerateWithTemplate(
+		ctx,
+		companyName,
+		companyURL,
+		report.CompanyIntelligenceData,
+		report.CompetitiveIntelligenceData,
+		report.MarketDynamicsData,
+		report.TrendAnalysisData,
+		template,
+	)
+	if err != nil {
+		return "", err
+	}
+
+	if err := models.UpdateFinalReport(ctx, conn, reportID, result); err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+type RunOptions struct {
+	CompanyName string
+	CompanyURL  string
+	Template    string
+	// MinConfidence is the identification confidence required before the
+	// research continues without a human picking the company.
+	MinConfidence float64
+}
+
+type RunResult struct {
+	ResearchBrief models.ResearchBrief
+	Candidate     models.CompanyCandidates
+	Candidates    []models.CompanyCandidates
+	Report        models.Report
+}
+
+// Run executes the whole research pipeline synchronously: preliminary
+// research, the four domain agents with validation, and report generation.
+// When the company cannot be identified the result holds the candidates and
+// ErrAmbiguousCompany is returned.
+func (p Pipeline) Run(
+	ctx context.Context,
+	conn *sql.DB,
+	opts RunOptions,
+) (RunResult, error) {
+	query := opts.CompanyName
+	if opts.CompanyURL != "" {
+		query = fmt.Sprintf("%s (%s)", opts.CompanyName, opts.CompanyURL)
+	}
+
+	brief, err := p.preliminary.Research(ctx, query)
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	// Headless runs are not tied to a user account or workspace.
+	researchBrief, err := SaveResearchBrief(ctx, conn, brief, "", "")
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	candidate, candidates, ok, err := identifyCandidate(
+		ctx,
+		conn,
+		researchBrief,
+		brief,
+		opts.CompanyURL,
+		opts.MinConfidence,
+	)
+	if err != nil {
+		return RunResult{}, err
+	}
+
+	result := RunResult{ResearchBrief: researchBrief, Candidates: candidates}
+	if !ok {
+		return result, ErrAmbiguousCompany
+	}
+	result.Candidate = candidate
+
+	report, err := CreateReport(ctx, conn, candidate)
+	if err != nil {
+		return result, err
+	}
+
+	if err := models.UpdateReportProgressToStarted(ctx, conn, report.ID); err != nil {
+		return result, err
+	}
+
+	companyURL := candidate.Domain
+	if companyURL == "" {
+		companyURL = opts.CompanyURL
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	for jobName := range p.domains {
+		eg.Go(func() error {
+			if err := p.ResearchDomain(egCtx, conn, jobName, report.ID, candidate.Name, companyURL); err != nil {
+				return fmt.Errorf("%s: %w", strings.TrimSuffix(jobName, "_job"), err)
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return result, err
+	}
+
+	if _, err := p.GenerateReport(ctx, conn, report.ID, candidate.Name, companyURL, opts.Template); err != nil {
+		return result, err
+	}
+
+	result.Report, err = models.FindReportByID(ctx, conn, report.ID)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
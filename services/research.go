@@ -0,0 +1,404 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"maragu.dev/goqite"
+	"maragu.dev/goqite/jobs"
+)
+
+// ErrReportCanceled is the cause of a report context canceled because a user
+// canceled the report, see WithReportCancellation.
+var ErrReportCanceled = errors.New("report canceled")
+
+// reportCancellationInterval is how often a running report job checks whether
+// its report was canceled.
+const reportCancellationInterval = 2 * time.Second
+
+// CreateReport creates a pending report for a company candidate, owned by the
+// user who requested the candidate's research brief and placed in the brief's
+// workspace. Research is not started until StartResearch is called for it.
+func CreateReport(
+	ctx context.Context,
+	conn *sql.DB,
+	candidate models.CompanyCandidates,
+) (models.Report, error) {
+	brief, err := models.FindResearchBriefByID(ctx, conn, uuid.MustParse(candidate.ResearchBriefID))
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	return models.CreateReport(ctx, conn, models.CreateReportData{
+		CompanyCandidateID:               candidate.ID.String(),
+		CompanyName:                      candidate.Name,
+		Status:                           "pending",
+		ProgressPercentage:               0,
+		PreliminaryResearchCompleted:     true, // Since we already have the preliminary research
+		CompanyIntelligenceCompleted:     false,
+		CompetitiveIntelligenceCompleted: false,
+		MarketDynamicsCompleted:          false,
+		TrendAnalysisCompleted:           false,
+		CompanyIntelligenceData:          "",
+		CompetitiveIntelligenceData:      "",
+		MarketDynamicsData:               "",
+		TrendAnalysisData:                "",
+		FinalReport:                      "",
+		CompletedAt:                      time.Time{},
+		UserID:                           brief.UserID,
+		WorkspaceID:                      brief.WorkspaceID,
+		CompanyID:                        candidate.CompanyID,
+	})
+}
+
+// StartResearch enqueues the four domain research jobs for a report and marks
+// it as started.
+func StartResearch(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	report models.Report,
+	companyURL string,
+) error {
+	if err := models.UpdateReportProgress(ctx, conn, report.ID); err != nil {
+		return err
+	}
+
+	researchJobs := []struct {
+		name   string
+		params any
+	}{
+		{
+			agents.CompanyIntelligenceJobName,
+			agents.CompanyIntelligenceJobParams{
+				ReportID:      report.ID,
+				CandidateName: report.CompanyName,
+				CompanyURL:    companyURL,
+			},
+		},
+		{
+			agents.CompetitiveIntelligenceJobName,
+			agents.CompetitiveIntelligenceJobParams{
+				ReportID:      report.ID,
+				CandidateName: report.CompanyName,
+				CompanyURL:    companyURL,
+			},
+		},
+		{
+			agents.TrendAnalysisJobName,
+			agents.TrendAnalysisJobParams{
+				ReportID:      report.ID,
+				CandidateName: report.CompanyName,
+				CompanyURL:    companyURL,
+			},
+		},
+		{
+			agents.MarketDynamicsJobName,
+			agents.MarketDynamicsJobParams{
+				ReportID:      report.ID,
+				CandidateName: report.CompanyName,
+				CompanyURL:    companyURL,
+			},
+		},
+	}
+
+	for _, job := range researchJobs {
+		if err := enqueue(ctx, q, job.name, job.params); err != nil {
+			return err
+		}
+	}
+
+	if err := models.UpdateReportProgressToStarted(ctx, conn, report.ID); err != nil {
+		return err
+	}
+
+	if err := EmitReportEvent(ctx, conn, q, models.WebhookEventReportStarted, report.ID, "", nil); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to emit report started webhook",
+			"error", err,
+			"report_id", report.ID,
+		)
+	}
+
+	return nil
+}
+
+// CompleteResearchSection announces a finished domain section and queues the
+// report generation once it was the last one. Sections finishing after the
+// report was canceled are not announced.
+func CompleteResearchSection(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	jobName string,
+	reportID uuid.UUID,
+) error {
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status == models.ReportStatusCanceled {
+		return nil
+	}
+
+	if err := EmitReportEvent(
+		ctx,
+		conn,
+		q,
+		models.WebhookEventReportSectionCompleted,
+		reportID,
+		reportSections[jobName],
+		nil,
+	); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to emit section completed webhook",
+			"error", err,
+			"report_id", reportID,
+		)
+	}
+
+	return EnqueueReportGeneration(ctx, conn, q, reportID)
+}
+
+// CompleteReport announces a generated report and hands it on to the
+// watchlist and batch bookkeeping. A report canceled while it was generated
+// keeps no final report and is left alone.
+func CompleteReport(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+	batchConcurrency int64,
+) error {
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return err
+	}
+	if report.Status == models.ReportStatusCanceled {
+		return nil
+	}
+
+	if err := EmitReportEvent(ctx, conn, q, models.WebhookEventReportCompleted, reportID, "", nil); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to emit report completed webhook",
+			"error", err,
+			"report_id", reportID,
+		)
+	}
+
+	if err := EnqueueChangeDetection(ctx, conn, q, reportID); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to enqueue change detection",
+			"error", err,
+			"report_id", reportID,
+		)
+	}
+
+	return CompleteBatchRow(ctx, conn, q, reportID, batchConcurrency)
+}
+
+// FailReport marks a report failed after one of its jobs gave up. The
+// report.failed event is only emitted by the first failing job, and a batch
+// row waiting on the report is failed so the next queued row can start.
+func FailReport(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+	cause error,
+	batchConcurrency int64,
+) error {
+	marked, err := models.MarkReportFailed(ctx, conn, reportID)
+	if err != nil || !marked {
+		return err
+	}
+
+	if err := EmitReportEvent(ctx, conn, q, models.WebhookEventReportFailed, reportID, "", cause); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to emit report failed webhook",
+			"error", err,
+			"report_id", reportID,
+		)
+	}
+
+	return FailBatchRow(ctx, conn, q, reportID, cause, batchConcurrency)
+}
+
+// CancelReport stops the research of a report that has not finished yet. Its
+// running jobs see the canceled state through WithReportCancellation, and a
+// batch row waiting on the report is failed so the next queued row can start.
+// It returns false when the report had already completed, failed or been
+// canceled.
+func CancelReport(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+	batchConcurrency int64,
+) (bool, error) {
+	marked, err := models.MarkReportCanceled(ctx, conn, reportID)
+	if err != nil || !marked {
+		return false, err
+	}
+
+	if err := EmitReportEvent(ctx, conn, q, models.WebhookEventReportCanceled, reportID, "", nil); err != nil {
+		slog.ErrorContext(
+			ctx,
+			"failed to emit report canceled webhook",
+			"error", err,
+			"report_id", reportID,
+		)
+	}
+
+	return true, FailBatchRow(ctx, conn, q, reportID, ErrReportCanceled, batchConcurrency)
+}
+
+// WithReportCancellation returns a context for running a job of the report
+// that is canceled with ErrReportCanceled as its cause once the report is
+// seen in the canceled state. The report is checked every
+// reportCancellationInterval until the returned cancel func is called.
+func WithReportCancellation(
+	ctx context.Context,
+	conn *sql.DB,
+	reportID uuid.UUID,
+) (context.Context, context.CancelFunc) {
+	reportCtx, cancel := context.WithCancelCause(ctx)
+
+	go func() {
+		ticker := time.NewTicker(reportCancellationInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-reportCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			report, err := models.FindReportByID(reportCtx, conn, reportID)
+			if err != nil {
+				if reportCtx.Err() == nil {
+					slog.ErrorContext(
+						ctx,
+						"failed to check report for cancellation",
+						"error", err,
+						"report_id", reportID,
+					)
+				}
+				continue
+			}
+			if report.Status == models.ReportStatusCanceled {
+				cancel(ErrReportCanceled)
+				return
+			}
+		}
+	}()
+
+	return reportCtx, func() { cancel(context.Canceled) }
+}
+
+// ReportCanceled reports whether ctx, returned by WithReportCancellation,
+// was canceled because its report was.
+func ReportCanceled(ctx context.Context) bool {
+	return errors.Is(context.Cause(ctx), ErrReportCanceled)
+}
+
+// EnqueueReportGeneration queues the final report generation once every
+// domain agent has finished. It is safe to call repeatedly; the generator is
+// only enqueued the first time.
+func EnqueueReportGeneration(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+) error {
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return err
+	}
+
+	if !AllAgentsCompleted(report) {
+		return nil
+	}
+
+	marked, err := models.MarkReportGenerating(ctx, conn, report.ID)
+	if err != nil || !marked {
+		return err
+	}
+
+	company, err := models.FindCompanyCandidatesByID(
+		ctx,
+		conn,
+		uuid.MustParse(report.CompanyCandidateID),
+	)
+	if err != nil {
+		return err
+	}
+
+	return enqueue(ctx, q, agents.ReportGeneratorJobName, agents.ReportGeneratorJobParams{
+		ReportID:      report.ID,
+		CandidateName: report.CompanyName,
+		CompanyURL:    company.Domain,
+	})
+}
+
+func enqueue(ctx context.Context, q *goqite.Queue, name string, params any) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	return jobs.Create(ctx, q, name, data)
+}
+
+// jobMessage mirrors the envelope goqite/jobs gob-encodes around job params,
+// so the jobs runner picks up messages sent by enqueueAfter.
+type jobMessage struct {
+	Name    string
+	Message []byte
+}
+
+// enqueueAfter queues a job that becomes visible after delay. jobs.Create has
+// no delay option, so the message is sent to the queue directly.
+func enqueueAfter(
+	ctx context.Context,
+	q *goqite.Queue,
+	name string,
+	params any,
+	delay time.Duration,
+) error {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+
+	var body bytes.Buffer
+	if err := gob.NewEncoder(&body).Encode(jobMessage{Name: name, Message: data}); err != nil {
+		return err
+	}
+
+	return q.Send(ctx, goqite.Message{Body: body.Bytes(), Delay: delay})
+}
+
+// AllAgentsCompleted reports whether every domain agent has finished its
+// section of the report.
+func AllAgentsCompleted(report models.Report) bool {
+	return report.CompanyIntelligenceCompleted &&
+		report.CompetitiveIntelligenceCompleted &&
+		report.MarketDynamicsCompleted &&
+		report.TrendAnalysisCompleted
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/tools"
+	"maragu.dev/goqite"
+)
+
+// SaveResearchBrief stores a preliminary research result together with its
+// company candidates, special considerations, sources and agent guidance.
+// Failing to store one of the related records is logged but does not fail
+// the brief. The brief is owned by userID and belongs to workspaceID, or to
+// no one when they are empty. Candidates are linked to the canonical company
+// of their domain in the workspace.
+func SaveResearchBrief(
+	ctx context.Context,
+	conn *sql.DB,
+	result agents.ResearchBrief,
+	userID string,
+	workspaceID string,
+) (models.ResearchBrief, error) {
+	researchbrief, err := models.CreateResearchBrief(ctx, conn, models.CreateResearchBriefData{
+		IdentificationStatus: result.IdentificationStatus,
+		CompanyName:          result.CompanyName,
+		OfficialDomain:       result.OfficialDomain,
+		Headquarters:         result.Headquarters,
+		Industry:             result.Industry,
+		CompanyType:          result.CompanyType,
+		Status:               result.Status,
+		GeographicScope:      result.GeographicScope,
+		ResearchDepth:        result.ResearchDepth,
+		ConfidenceScore:      result.ConfidenceScore,
+		LastUpdated:          time.Now(),
+		UserID:               userID,
+		WorkspaceID:          workspaceID,
+	})
+	if err != nil {
+		return models.ResearchBrief{}, err
+	}
+
+	saveResearchFindings(ctx, conn, researchbrief, result)
+
+	return researchbrief, nil
+}
+
+// QueueResearchBrief stores a pending brief for query and queues its
+// preliminary research, so the caller does not wait for the LLM and search
+// calls. Until ResearchQueuedBrief has run, the brief's company name is the
+// query.
+func QueueResearchBrief(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	query string,
+	userID string,
+	workspaceID string,
+) (models.ResearchBrief, error) {
+	researchbrief, err := models.CreateResearchBrief(ctx, conn, models.CreateResearchBriefData{
+		CompanyName:    query,
+		LastUpdated:    time.Now(),
+		UserID:         userID,
+		WorkspaceID:    workspaceID,
+		ResearchStatus: models.ResearchBriefResearchPending,
+		Query:          query,
+	})
+	if err != nil {
+		return models.ResearchBrief{}, err
+	}
+
+	if err := enqueue(ctx, q, agents.PreliminaryResearchJobName, agents.PreliminaryResearchJobParams{
+		ResearchBriefID: researchbrief.ID,
+	}); err != nil {
+		return models.ResearchBrief{}, err
+	}
+
+	return researchbrief, nil
+}
+
+// ResearchQueuedBrief runs the preliminary research of a brief queued by
+// QueueResearchBrief and stores its result. A failed research is recorded on
+// the brief rather than retried, like a failed batch row.
+func ResearchQueuedBrief(
+	ctx context.Context,
+	conn *sql.DB,
+	agent agents.PreliminaryResearch,
+	researchBriefID uuid.UUID,
+) error {
+	researchbrief, err := models.FindResearchBriefByID(ctx, conn, researchBriefID)
+	if err != nil {
+		return err
+	}
+
+	if researchbrief.ResearchStatus != models.ResearchBriefResearchPending {
+		return nil
+	}
+
+	result, err := agent.Research(
+		tools.WithCallScope(ctx, researchbrief.WorkspaceID, ""),
+		researchbrief.Query,
+	)
+	if ctx.Err() != nil {
+		// Stopped by a shutdown; the job runs again after the restart.
+		return ctx.Err()
+	}
+	if err != nil {
+		return models.UpdateResearchBriefResearchStatus(
+			ctx,
+			conn,
+			researchbrief.ID,
+			models.ResearchBriefResearchFailed,
+			err.Error(),
+		)
+	}
+
+	researchbrief, err = models.UpdateResearchBrief(ctx, conn, models.UpdateResearchBriefData{
+		ID:                   researchbrief.ID,
+		IdentificationStatus: result.IdentificationStatus,
+		CompanyName:          result.CompanyName,
+		OfficialDomain:       result.OfficialDomain,
+		Headquarters:         result.Headquarters,
+		Industry:             result.Industry,
+		CompanyType:          result.CompanyType,
+		Status:               result.Status,
+		GeographicScope:      result.GeographicScope,
+		ResearchDepth:        result.ResearchDepth,
+		ConfidenceScore:      result.ConfidenceScore,
+		LastUpdated:          time.Now(),
+	})
+	if err != nil {
+		return models.UpdateResearchBriefResearchStatus(
+			ctx,
+			conn,
+			researchBriefID,
+			models.ResearchBriefResearchFailed,
+			err.Error(),
+		)
+	}
+
+	saveResearchFindings(ctx, conn, researchbrief, result)
+
+	return models.UpdateResearchBriefResearchStatus(
+		ctx,
+		conn,
+		researchbrief.ID,
+		models.ResearchBriefResearchCompleted,
+		"",
+	)
+}
+
+// saveResearchFindings stores the company candidates, special
+// considerations, sources and agent guidance of a research result with its
+// brief, logging the records that fail to store.
+func saveResearchFindings(
+	ctx context.Context,
+	conn *sql.DB,
+	researchbrief models.ResearchBrief,
+	result agents.ResearchBrief,
+) {
+	for _, candidate := range result.CompanyCandidates {
+		companyID, err := resolveCompanyID(ctx, conn, researchbrief.WorkspaceID, candidate.Name, candidate.Domain)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to resolve company of candidate",
+				"error", err,
+				"research_brief_id", researchbrief.ID,
+				"domain", candidate.Domain,
+			)
+		}
+
+		_, err = models.CreateCompanyCandidates(
+			ctx,
+			conn,
+			models.CreateCompanyCandidatesData{
+				ResearchBriefID: researchbrief.ID.String(),
+				Name:            candidate.Name,
+				Domain:          candidate.Domain,
+				Description:     candidate.Description,
+				Industry:        candidate.Industry,
+				Location:        candidate.Location,
+				CompanyID:       companyID,
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to create company candidate",
+				"error", err,
+				"research_brief_id", researchbrief.ID,
+			)
+		}
+	}
+
+	for _, consideration := range result.SpecialConsiderations {
+		_, err := models.CreateSpecialConsiderations(
+			ctx,
+			conn,
+			models.CreateSpecialConsiderationsData{
+				ResearchBriefID: researchbrief.ID.String(),
+				Consideration:   consideration,
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to create special consideration",
+				"error", err,
+				"research_brief_id", researchbrief.ID,
+			)
+		}
+	}
+
+	for _, source := range result.Sources {
+		_, err := models.CreateSources(
+			ctx,
+			conn,
+			models.CreateSourcesData{
+				ResearchBriefID: researchbrief.ID.String(),
+				SourceUrl:       source,
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to create source",
+				"error", err,
+				"research_brief_id", researchbrief.ID,
+			)
+		}
+	}
+
+	for key, value := range result.AgentGuidance {
+		_, err := models.CreateAgentGuidance(
+			ctx,
+			conn,
+			models.CreateAgentGuidanceData{
+				ResearchBriefID: researchbrief.ID.String(),
+				GuidanceKey:     key,
+				GuidanceValue:   value,
+			},
+		)
+		if err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to create agent guidance",
+				"error", err,
+				"research_brief_id", researchbrief.ID,
+			)
+		}
+	}
+}
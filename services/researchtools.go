@@ -0,0 +1,144 @@
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/tools"
+)
+
+// ResearchTools holds the toolset of each research agent: the tools the
+// agent allows itself, with their quotas. They share one scrape chain and
+// site crawler, and differ in the search backends they search with.
+type ResearchTools struct {
+	PreliminaryResearch     tools.Toolset
+	CompanyIntelligence     tools.Toolset
+	CompetitiveIntelligence tools.Toolset
+	MarketDynamics          tools.Toolset
+	TrendAnalysis           tools.Toolset
+	DataValidation          tools.Toolset
+}
+
+// NewResearchTools returns the tools the research agents get from the
+// configuration: web search through each agent's search backends, crawling
+// company websites, and reading pages through the scrape chain with the
+// backends in the configured order. All are cached in conn, unless it is
+// nil; bypassCache refetches everything and refreshes the cache. Every
+// tool call is logged in conn too. The search and scraping APIs share one
+// HTTP client.
+func NewResearchTools(conn *sql.DB, bypassCache bool) (ResearchTools, *tools.Cache, error) {
+	var cache *tools.Cache
+	var callLog *tools.CallLog
+	if conn != nil {
+		callLog = tools.NewCallLog(conn)
+		cache = tools.NewCache(conn, map[string]time.Duration{
+			tools.SearchToolName:      config.App.SearchCacheTTL,
+			tools.ScrapeChainToolName: config.App.ScrapeCacheTTL,
+			tools.SiteCrawlToolName:   config.App.ScrapeCacheTTL,
+		}, bypassCache)
+	}
+
+	client, err := tools.NewHTTPClient(tools.HTTPClientConfig{
+		Timeout:    config.App.ToolsHTTPTimeout,
+		ProxyURL:   config.App.ToolsHTTPProxy,
+		MaxRetries: config.App.ToolsHTTPRetries,
+	})
+	if err != nil {
+		return ResearchTools{}, nil, err
+	}
+
+	webFetch := tools.NewWebFetch(config.App.GetWebFetchUserAgent(), config.App.ScrapeMaxTokens)
+	serperScrape := tools.NewSerperScrape(config.App.SerperAPIkey, config.App.ScrapeMaxTokens, client)
+	scrapingBee := tools.NewScrapingBee(config.App.ScrapingBeeAPIKey, config.App.ScrapeMaxTokens, client)
+
+	scrapeBackends, err := selectBackends("scrape", config.App.ScrapeBackends, map[string]tools.PageFetcher{
+		webFetch.GetName():     &webFetch,
+		serperScrape.GetName(): &serperScrape,
+		scrapingBee.GetName():  &scrapingBee,
+	})
+	if err != nil {
+		return ResearchTools{}, nil, err
+	}
+
+	scrapeChain := tools.NewScrapeChain(
+		scrapeBackends,
+		config.App.ScrapeBreakerErrorRate,
+		config.App.ScrapeBreakerCooldown,
+		cache,
+	)
+	siteCrawl := tools.NewSiteCrawl(&webFetch, config.App.SiteCrawlMaxPages, config.App.ScrapeMaxTokens, cache)
+
+	serper := tools.NewSerper(config.App.SerperAPIkey, client)
+	searchBackends := map[string]tools.SearchBackend{
+		serper.GetName(): &serper,
+	}
+	if config.App.SearXNGURL != "" {
+		searxng := tools.NewSearXNG(config.App.SearXNGURL, client)
+		searchBackends[searxng.GetName()] = &searxng
+	}
+
+	agentTools := func(agent string, names []string, allowances []tools.Allowance) (tools.Toolset, error) {
+		// An agent without backends of its own uses the default ones.
+		if !slices.ContainsFunc(names, func(name string) bool { return strings.TrimSpace(name) != "" }) {
+			names = config.App.SearchBackends
+		}
+
+		backends, err := selectBackends("search", names, searchBackends)
+		if err != nil {
+			return tools.Toolset{}, err
+		}
+
+		search := tools.NewWebSearch(backends, config.App.SearchMergeResults, cache)
+		return tools.NewRegistry(callLog, &search, &scrapeChain, &siteCrawl).Toolset(agent, allowances)
+	}
+
+	var researchTools ResearchTools
+	for _, agent := range []struct {
+		name       string
+		tools      *tools.Toolset
+		backends   []string
+		allowances []tools.Allowance
+	}{
+		{"preliminary_research", &researchTools.PreliminaryResearch, config.App.PreliminaryResearchSearchBackends, agents.PreliminaryResearchTools},
+		{"company_intelligence", &researchTools.CompanyIntelligence, config.App.CompanyIntelligenceSearchBackends, agents.CompanyIntelligenceTools},
+		{"competitive_intelligence", &researchTools.CompetitiveIntelligence, config.App.CompetitiveIntelligenceSearchBackends, agents.CompetitiveIntelligenceTools},
+		{"market_dynamics", &researchTools.MarketDynamics, config.App.MarketDynamicsSearchBackends, agents.MarketDynamicsTools},
+		{"trend_analysis", &researchTools.TrendAnalysis, config.App.TrendAnalysisSearchBackends, agents.TrendAnalysisTools},
+		{"data_validation", &researchTools.DataValidation, config.App.DataValidationSearchBackends, agents.DataValidationTools},
+	} {
+		if *agent.tools, err = agentTools(agent.name, agent.backends, agent.allowances); err != nil {
+			return ResearchTools{}, nil, fmt.Errorf("%s tools: %w", agent.name, err)
+		}
+	}
+
+	return researchTools, cache, nil
+}
+
+// selectBackends returns the named backends in order.
+func selectBackends[T any](kind string, names []string, available map[string]T) ([]T, error) {
+	var backends []T
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		backend, ok := available[name]
+		if !ok {
+			if name == tools.SearXNGBackendName {
+				return nil, fmt.Errorf("%s backend %q needs SEARXNG_URL", kind, name)
+			}
+			return nil, fmt.Errorf("unknown %s backend %q", kind, name)
+		}
+		backends = append(backends, backend)
+	}
+	if len(backends) == 0 {
+		return nil, fmt.Errorf("no %s backends configured", kind)
+	}
+
+	return backends, nil
+}
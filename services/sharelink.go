@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+)
+
+// MaxReportShareLinkTTL caps how long a share link can stay valid.
+const MaxReportShareLinkTTL = 90 * 24 * time.Hour
+
+// reportShareUserAgentLimit caps the user agent stored per access; anything
+// longer is not useful in the access log.
+const reportShareUserAgentLimit = 500
+
+var (
+	// ErrShareLinkInvalid is returned for tokens that are malformed, carry a
+	// bad signature or point at a link that does not exist.
+	ErrShareLinkInvalid = errors.New("the share link is invalid")
+	ErrShareLinkExpired = errors.New("the share link has expired")
+	ErrShareLinkRevoked = errors.New("the share link has been revoked")
+)
+
+// ShareVisitor identifies who opened a share link, for the access log.
+type ShareVisitor struct {
+	IPAddress string
+	UserAgent string
+}
+
+// ShareReport creates a share link for a report that stays valid for ttl.
+// Only reports with a final report can be shared.
+func ShareReport(
+	ctx context.Context,
+	conn *sql.DB,
+	report models.Report,
+	label string,
+	ttl time.Duration,
+) (models.ReportShareLink, error) {
+	if report.FinalReport == "" {
+		return models.ReportShareLink{}, ErrReportNotReady
+	}
+
+	if ttl <= 0 || ttl > MaxReportShareLinkTTL {
+		return models.ReportShareLink{}, models.ErrDomainValidation
+	}
+
+	// The expiry is signed as unix seconds, so it is stored without the
+	// sub-second part to compare equal when the link is opened.
+	return models.CreateReportShareLink(ctx, conn, models.CreateReportShareLinkData{
+		ReportID:  report.ID,
+		Label:     strings.TrimSpace(label),
+		ExpiresAt: time.Now().Add(ttl).Truncate(time.Second),
+	})
+}
+
+// ReportShareToken returns the signed token of a share link. The token holds
+// the link id and its expiry, signed with the token signing key, so it can
+// be derived again at any time instead of being stored.
+func ReportShareToken(link models.ReportShareLink) string {
+	payload := make([]byte, 0, 24)
+	payload = append(payload, link.ID[:]...)
+	payload = binary.BigEndian.AppendUint64(payload, uint64(link.ExpiresAt.Unix()))
+
+	return base64.RawURLEncoding.EncodeToString(payload) + "." +
+		base64.RawURLEncoding.EncodeToString(signShareToken(payload))
+}
+
+// ReportShareURL returns the absolute public URL of a share link.
+func ReportShareURL(link models.ReportShareLink) string {
+	return config.App.GetFullDomain() + routes.SharedReportShow.GetPath(ReportShareToken(link))
+}
+
+// OpenSharedReport verifies a share token and returns the report it grants
+// access to. Every request with a correctly signed token is recorded in the
+// access log, including those for expired and revoked links.
+func OpenSharedReport(
+	ctx context.Context,
+	conn *sql.DB,
+	token string,
+	visitor ShareVisitor,
+) (models.ReportShareLink, models.Report, error) {
+	linkID, expiresAt, ok := parseShareToken(token)
+	if !ok {
+		return models.ReportShareLink{}, models.Report{}, ErrShareLinkInvalid
+	}
+
+	link, err := models.FindReportShareLink(ctx, conn, linkID)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return models.ReportShareLink{}, models.Report{}, ErrShareLinkInvalid
+	case err != nil:
+		return models.ReportShareLink{}, models.Report{}, err
+	}
+
+	if link.ExpiresAt.Unix() != expiresAt {
+		return models.ReportShareLink{}, models.Report{}, ErrShareLinkInvalid
+	}
+
+	outcome, accessErr := shareLinkOutcome(link, time.Now())
+
+	userAgent := visitor.UserAgent
+	if len(userAgent) > reportShareUserAgentLimit {
+		userAgent = userAgent[:reportShareUserAgentLimit]
+	}
+
+	if _, err := models.CreateReportShareAccess(ctx, conn, models.CreateReportShareAccessData{
+		ReportShareLinkID: link.ID,
+		Outcome:           outcome,
+		IPAddress:         visitor.IPAddress,
+		UserAgent:         userAgent,
+	}); err != nil {
+		return models.ReportShareLink{}, models.Report{}, err
+	}
+
+	if accessErr != nil {
+		return link, models.Report{}, accessErr
+	}
+
+	reportID, err := uuid.Parse(link.ReportID)
+	if err != nil {
+		return models.ReportShareLink{}, models.Report{}, err
+	}
+
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return models.ReportShareLink{}, models.Report{}, err
+	}
+
+	return link, report, nil
+}
+
+// shareLinkOutcome decides what opening a link at now results in, as
+// recorded in the access log, and the error to refuse access with.
+func shareLinkOutcome(link models.ReportShareLink, now time.Time) (string, error) {
+	switch {
+	case link.Revoked():
+		return models.ReportShareAccessRevoked, ErrShareLinkRevoked
+	case link.Expired(now):
+		return models.ReportShareAccessExpired, ErrShareLinkExpired
+	}
+
+	return models.ReportShareAccessGranted, nil
+}
+
+func parseShareToken(token string) (uuid.UUID, int64, bool) {
+	encodedPayload, encodedSignature, ok := strings.Cut(token, ".")
+	if !ok {
+		return uuid.UUID{}, 0, false
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encodedPayload)
+	if err != nil || len(payload) != 24 {
+		return uuid.UUID{}, 0, false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, signShareToken(payload)) {
+		return uuid.UUID{}, 0, false
+	}
+
+	linkID, err := uuid.FromBytes(payload[:16])
+	if err != nil {
+		return uuid.UUID{}, 0, false
+	}
+
+	return linkID, int64(binary.BigEndian.Uint64(payload[16:])), true
+}
+
+func signShareToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(config.Auth.TokenSigningKey))
+	mac.Write([]byte("report-share:"))
+	mac.Write(payload)
+
+	return mac.Sum(nil)
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+func TestParseShareToken(t *testing.T) {
+	link := models.ReportShareLink{
+		ID:        uuid.New(),
+		ExpiresAt: time.Now().Add(time.Hour).Truncate(time.Second),
+	}
+	token := ReportShareToken(link)
+	encodedPayload, encodedSignature, _ := strings.Cut(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(encodedPayload)
+
+	// withPayload keeps the signature of token but swaps its payload.
+	withPayload := func(change func(payload []byte)) string {
+		changed := append([]byte(nil), payload...)
+		change(changed)
+		return base64.RawURLEncoding.EncodeToString(changed) + "." + encodedSignature
+	}
+
+	otherKey := hmac.New(sha256.New, []byte("not the token signing key"))
+	otherKey.Write([]byte("report-share:"))
+	otherKey.Write(payload)
+
+	tests := []struct {
+		name  string
+		token string
+		valid bool
+	}{
+		{name: "valid token", token: token, valid: true},
+		{
+			name: "expiry extended",
+			token: withPayload(func(payload []byte) {
+				binary.BigEndian.PutUint64(payload[16:], uint64(link.ExpiresAt.Add(24*time.Hour).Unix()))
+			}),
+		},
+		{
+			name: "another link",
+			token: withPayload(func(payload []byte) {
+				other := uuid.New()
+				copy(payload[:16], other[:])
+			}),
+		},
+		{
+			name:  "signature flipped",
+			token: encodedPayload + "." + base64.RawURLEncoding.EncodeToString(flipFirstBit(signShareToken(payload))),
+		},
+		{
+			name:  "signed with another key",
+			token: encodedPayload + "." + base64.RawURLEncoding.EncodeToString(otherKey.Sum(nil)),
+		},
+		{
+			name:  "signature truncated",
+			token: encodedPayload + "." + base64.RawURLEncoding.EncodeToString(signShareToken(payload)[:16]),
+		},
+		{name: "signature missing", token: encodedPayload + "."},
+		{name: "no separator", token: encodedPayload + encodedSignature},
+		{name: "payload not base64", token: "!!!." + encodedSignature},
+		{
+			name:  "payload too short",
+			token: base64.RawURLEncoding.EncodeToString(payload[:16]) + "." + base64.RawURLEncoding.EncodeToString(signShareToken(payload[:16])),
+		},
+		{name: "empty", token: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linkID, expiresAt, ok := parseShareToken(tt.token)
+			if ok != tt.valid {
+				t.Fatalf("parseShareToken() ok = %v, want %v", ok, tt.valid)
+			}
+			if !tt.valid {
+				return
+			}
+			if linkID != link.ID || expiresAt != link.ExpiresAt.Unix() {
+				t.Fatalf("parseShareToken() = %v, %d, want %v, %d", linkID, expiresAt, link.ID, link.ExpiresAt.Unix())
+			}
+		})
+	}
+}
+
+func flipFirstBit(b []byte) []byte {
+	flipped := append([]byte(nil), b...)
+	flipped[0] ^= 1
+	return flipped
+}
+
+func TestShareLinkOutcome(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+
+	tests := []struct {
+		name        string
+		link        models.ReportShareLink
+		wantOutcome string
+		wantErr     error
+	}{
+		{
+			name:        "active",
+			link:        models.ReportShareLink{ExpiresAt: now.Add(time.Minute)},
+			wantOutcome: models.ReportShareAccessGranted,
+		},
+		{
+			name:        "expired",
+			link:        models.ReportShareLink{ExpiresAt: now.Add(-time.Minute)},
+			wantOutcome: models.ReportShareAccessExpired,
+			wantErr:     ErrShareLinkExpired,
+		},
+		{
+			name:        "expires this second",
+			link:        models.ReportShareLink{ExpiresAt: now},
+			wantOutcome: models.ReportShareAccessExpired,
+			wantErr:     ErrShareLinkExpired,
+		},
+		{
+			name:        "revoked",
+			link:        models.ReportShareLink{ExpiresAt: now.Add(time.Minute), RevokedAt: now.Add(-time.Minute)},
+			wantOutcome: models.ReportShareAccessRevoked,
+			wantErr:     ErrShareLinkRevoked,
+		},
+		{
+			name:        "revoked and expired",
+			link:        models.ReportShareLink{ExpiresAt: now.Add(-time.Minute), RevokedAt: now.Add(-time.Hour)},
+			wantOutcome: models.ReportShareAccessRevoked,
+			wantErr:     ErrShareLinkRevoked,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, err := shareLinkOutcome(tt.link, now)
+			if outcome != tt.wantOutcome || !errors.Is(err, tt.wantErr) {
+				t.Fatalf("shareLinkOutcome() = %q, %v, want %q, %v", outcome, err, tt.wantOutcome, tt.wantErr)
+			}
+		})
+	}
+}
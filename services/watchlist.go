@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"maragu.dev/goqite"
+)
+
+const noPreviousReportSummary = "First report for this company; there is no previous version to compare against."
+
+// RunWatchlistScheduler re-researches due watchlist entries every interval
+// until the context is cancelled.
+func RunWatchlistScheduler(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	interval time.Duration,
+) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := ScheduleDueWatchlists(ctx, conn, q, time.Now()); err != nil {
+			slog.ErrorContext(ctx, "failed to schedule watchlists", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ScheduleDueWatchlists starts a re-research run for every watchlist entry
+// whose next run is at or before now.
+func ScheduleDueWatchlists(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	now time.Time,
+) error {
+	due, err := models.DueWatchlists(ctx, conn, now)
+	if err != nil {
+		return err
+	}
+
+	for _, watchlist := range due {
+		if _, err := ReResearchWatchlist(ctx, conn, q, watchlist, now); err != nil {
+			slog.ErrorContext(
+				ctx,
+				"failed to re-research watchlist entry",
+				"error", err,
+				"watchlist_id", watchlist.ID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// ReResearchWatchlist creates a new report for a watchlist entry, records it
+// as a pending change against the last completed report and starts the
+// research. The new report only becomes the baseline of later runs once it
+// completes, so a failed run is never compared against. When the research
+// cannot be started, the report and its change are marked failed and the
+// next run stays due, so the scheduler tries again.
+func ReResearchWatchlist(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	watchlist models.Watchlist,
+	now time.Time,
+) (models.Report, error) {
+	candidate, err := models.FindCompanyCandidatesByID(
+		ctx,
+		conn,
+		uuid.MustParse(watchlist.CompanyCandidateID),
+	)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	report, err := CreateReport(ctx, conn, candidate)
+	if err != nil {
+		return models.Report{}, err
+	}
+
+	change, err := models.CreateWatchlistChange(ctx, conn, models.CreateWatchlistChangeData{
+		WatchlistID:      watchlist.ID.String(),
+		ReportID:         report.ID.String(),
+		PreviousReportID: watchlist.LastReportID,
+	})
+	if err != nil {
+		return models.Report{}, abandonWatchlistRun(ctx, conn, report.ID, uuid.Nil, err)
+	}
+
+	if err := StartResearch(ctx, conn, q, report, candidate.Domain); err != nil {
+		return models.Report{}, abandonWatchlistRun(ctx, conn, report.ID, change.ID, err)
+	}
+
+	if err := models.RecordWatchlistRun(ctx, conn, watchlist, now); err != nil {
+		return models.Report{}, err
+	}
+
+	return report, nil
+}
+
+// abandonWatchlistRun marks the report of a watchlist run that could not be
+// started as failed, together with its change when one was created, so
+// neither is left pending.
+func abandonWatchlistRun(
+	ctx context.Context,
+	conn *sql.DB,
+	reportID uuid.UUID,
+	changeID uuid.UUID,
+	cause error,
+) error {
+	errs := []error{cause}
+	if _, err := models.MarkReportFailed(ctx, conn, reportID); err != nil {
+		errs = append(errs, err)
+	}
+	if changeID != uuid.Nil {
+		if err := models.FailWatchlistChange(ctx, conn, changeID, cause.Error()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// EnqueueChangeDetection makes a freshly generated report the baseline of its
+// watchlist and queues its comparison against the previous version, when the
+// report was created by a watchlist run. Reports outside a watchlist are
+// ignored.
+func EnqueueChangeDetection(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	reportID uuid.UUID,
+) error {
+	change, err := models.FindWatchlistChangeByReportID(ctx, conn, reportID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := models.RecordWatchlistReport(
+		ctx,
+		conn,
+		uuid.MustParse(change.WatchlistID),
+		reportID,
+	); err != nil {
+		return err
+	}
+
+	if change.PreviousReportID == "" {
+		return models.CompleteWatchlistChange(ctx, conn, change.ID, false, noPreviousReportSummary)
+	}
+
+	return enqueue(ctx, q, agents.ChangeDetectionJobName, agents.ChangeDetectionJobParams{
+		WatchlistChangeID: change.ID,
+	})
+}
+
+// DetectChanges compares the report behind a watchlist change with the
+// previous version and stores the resulting summary.
+func DetectChanges(
+	ctx context.Context,
+	conn *sql.DB,
+	detector agents.ChangeDetection,
+	changeID uuid.UUID,
+) error {
+	change, err := models.FindWatchlistChange(ctx, conn, changeID)
+	if err != nil {
+		return err
+	}
+
+	current, err := models.FindReportByID(ctx, conn, uuid.MustParse(change.ReportID))
+	if err != nil {
+		return err
+	}
+
+	previous, err := models.FindReportByID(ctx, conn, uuid.MustParse(change.PreviousReportID))
+	if err != nil {
+		return err
+	}
+
+	if previous.FinalReport == "" {
+		return models.CompleteWatchlistChange(ctx, conn, change.ID, false, noPreviousReportSummary)
+	}
+
+	summary, err := detector.Compare(
+		ctx,
+		current.CompanyName,
+		previous.FinalReport,
+		current.FinalReport,
+	)
+	if err != nil {
+		if failErr := models.FailWatchlistChange(ctx, conn, change.ID, err.Error()); failErr != nil {
+			return errors.Join(err, failErr)
+		}
+		return err
+	}
+
+	return models.CompleteWatchlistChange(
+		ctx,
+		conn,
+		change.ID,
+		summary.HasMaterialChanges,
+		summary.Markdown(),
+	)
+}
@@ -0,0 +1,342 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"maragu.dev/goqite"
+
+	"github.com/mbvlabs/plyo-hackathon/agents"
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+	"github.com/mbvlabs/plyo-hackathon/tools"
+)
+
+const WebhookDeliveryJobName = "webhook_delivery_job"
+
+type WebhookDeliveryJobParams struct {
+	DeliveryID uuid.UUID `json:"delivery_id"`
+}
+
+const (
+	// MaxWebhookAttempts is the number of times a delivery is tried before it
+	// is marked failed. With the backoff below the last attempt happens
+	// roughly an hour after the first.
+	MaxWebhookAttempts     = 8
+	webhookRetryBaseDelay  = 30 * time.Second
+	webhookRetryMaxDelay   = time.Hour
+	webhookRequestTimeout  = 10 * time.Second
+	webhookMaxErrorBodyLen = 512
+)
+
+const (
+	WebhookEventHeader     = "X-Plyo-Event"
+	WebhookDeliveryHeader  = "X-Plyo-Delivery"
+	WebhookSignatureHeader = "X-Plyo-Signature"
+)
+
+var ErrWebhookURLNotPublic = errors.New("webhook URLs must point to a public internet host")
+
+// webhookClient only connects to public addresses, so an endpoint cannot be
+// used to reach internal services. Redirects are not followed, since the
+// response to a delivery is only checked for its status.
+var webhookClient = &http.Client{
+	Timeout: webhookRequestTimeout,
+	Transport: &http.Transport{
+		// Proxies from the environment would be dialed instead of the
+		// endpoint and defeat the address check.
+		Proxy: nil,
+		DialContext: (&net.Dialer{
+			Timeout: webhookRequestTimeout,
+			Control: tools.PublicAddressControl,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   webhookRequestTimeout,
+		ResponseHeaderTimeout: webhookRequestTimeout,
+	},
+	CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// reportSections names the report sections produced by each domain job, as
+// used in webhook payloads and the JSON API.
+var reportSections = map[string]string{
+	agents.CompanyIntelligenceJobName:     "company_intelligence",
+	agents.CompetitiveIntelligenceJobName: "competitive_intelligence",
+	agents.MarketDynamicsJobName:          "market_dynamics",
+	agents.TrendAnalysisJobName:           "trend_analysis",
+}
+
+type webhookPayload struct {
+	// ID identifies the event and stays the same across retries and
+	// endpoints, so receivers can use it to drop duplicates.
+	ID        uuid.UUID `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+type webhookReportData struct {
+	ReportID           uuid.UUID         `json:"report_id"`
+	CompanyName        string            `json:"company_name"`
+	Status             string            `json:"status"`
+	ProgressPercentage int64             `json:"progress_percentage"`
+	Section            string            `json:"section,omitempty"`
+	Error              string            `json:"error,omitempty"`
+	Links              map[string]string `json:"links"`
+}
+
+// CreateWebhookEndpoint stores a new endpoint after checking that its host
+// is on the public internet. Deliveries are checked again when connecting,
+// in case the host later resolves elsewhere.
+func CreateWebhookEndpoint(
+	ctx context.Context,
+	conn *sql.DB,
+	data models.CreateWebhookEndpointData,
+) (models.WebhookEndpoint, error) {
+	endpointURL, err := url.Parse(data.URL)
+	if err != nil || endpointURL.Hostname() == "" {
+		return models.WebhookEndpoint{}, errors.Join(models.ErrDomainValidation, err)
+	}
+	if err := tools.ResolvePublicHost(ctx, endpointURL.Hostname()); err != nil {
+		return models.WebhookEndpoint{}, errors.Join(ErrWebhookURLNotPublic, err)
+	}
+
+	return models.CreateWebhookEndpoint(ctx, conn, data)
+}
+
+// EmitReportEvent sends a report lifecycle event to every active endpoint of
+// the report's workspace subscribed to it. section is set for report.section_completed and cause
+// for report.failed.
+func EmitReportEvent(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	event string,
+	reportID uuid.UUID,
+	section string,
+	cause error,
+) error {
+	report, err := models.FindReportByID(ctx, conn, reportID)
+	if err != nil {
+		return err
+	}
+
+	// Headless runs are not part of a workspace, so nobody is subscribed.
+	workspaceID, err := uuid.Parse(report.WorkspaceID)
+	if err != nil {
+		return nil
+	}
+
+	data := webhookReportData{
+		ReportID:           report.ID,
+		CompanyName:        report.CompanyName,
+		Status:             report.Status,
+		ProgressPercentage: models.CalculateProgress(report),
+		Section:            section,
+		Links: map[string]string{
+			"report": config.App.GetFullDomain() + routes.ReportShow.GetPath(report.ID),
+			"api":    config.App.GetFullDomain() + routes.APIReportShow.GetPath(report.ID),
+		},
+	}
+	if cause != nil {
+		data.Error = cause.Error()
+	}
+
+	return EmitWebhookEvent(ctx, conn, q, workspaceID, event, data)
+}
+
+// EmitWebhookEvent stores a pending delivery for every active endpoint of the
+// workspace subscribed to the event and queues them for sending.
+func EmitWebhookEvent(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	workspaceID uuid.UUID,
+	event string,
+	data any,
+) error {
+	endpoints, err := models.ActiveWebhookEndpoints(ctx, conn, workspaceID)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	for _, endpoint := range endpoints {
+		if !endpoint.Subscribes(event) {
+			continue
+		}
+
+		if payload == nil {
+			payload, err = json.Marshal(webhookPayload{
+				ID:        uuid.New(),
+				Event:     event,
+				CreatedAt: time.Now().UTC(),
+				Data:      data,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		delivery, err := models.CreateWebhookDelivery(ctx, conn, endpoint.ID, event, string(payload))
+		if err != nil {
+			return err
+		}
+
+		if err := enqueue(ctx, q, WebhookDeliveryJobName, WebhookDeliveryJobParams{
+			DeliveryID: delivery.ID,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// DeliverWebhook makes one attempt at sending a delivery. Failed attempts are
+// retried with exponential backoff through the queue until
+// MaxWebhookAttempts is reached; the outcome of every attempt is recorded on
+// the delivery.
+func DeliverWebhook(
+	ctx context.Context,
+	conn *sql.DB,
+	q *goqite.Queue,
+	deliveryID uuid.UUID,
+) error {
+	delivery, err := models.FindWebhookDelivery(ctx, conn, deliveryID)
+	if errors.Is(err, sql.ErrNoRows) {
+		// The endpoint was removed together with its deliveries.
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if delivery.Status != models.WebhookDeliveryPending {
+		return nil
+	}
+
+	endpoint, err := models.FindWebhookEndpointByID(ctx, conn, uuid.MustParse(delivery.WebhookEndpointID))
+	if err != nil {
+		return err
+	}
+
+	attempt := models.RecordWebhookDeliveryAttemptData{
+		ID:       delivery.ID,
+		Attempts: delivery.Attempts + 1,
+	}
+
+	if !endpoint.Active {
+		attempt.Status = models.WebhookDeliveryFailed
+		attempt.Error = "endpoint is disabled"
+		return models.RecordWebhookDeliveryAttempt(ctx, conn, attempt)
+	}
+
+	attempt.StatusCode, err = sendWebhook(ctx, endpoint, delivery, time.Now())
+	if err == nil {
+		attempt.Status = models.WebhookDeliverySucceeded
+		attempt.DeliveredAt = time.Now()
+		return models.RecordWebhookDeliveryAttempt(ctx, conn, attempt)
+	}
+
+	attempt.Error = err.Error()
+	if attempt.Attempts >= MaxWebhookAttempts {
+		attempt.Status = models.WebhookDeliveryFailed
+		return models.RecordWebhookDeliveryAttempt(ctx, conn, attempt)
+	}
+
+	delay := webhookBackoff(attempt.Attempts)
+	attempt.Status = models.WebhookDeliveryPending
+	attempt.NextAttemptAt = time.Now().Add(delay)
+	if err := models.RecordWebhookDeliveryAttempt(ctx, conn, attempt); err != nil {
+		return err
+	}
+
+	return enqueueAfter(ctx, q, WebhookDeliveryJobName, WebhookDeliveryJobParams{
+		DeliveryID: delivery.ID,
+	}, delay)
+}
+
+// SignWebhookPayload returns the signature header value for a payload sent
+// at the given time: the hex encoded HMAC-SHA256 of "<unix time>.<body>"
+// keyed with the endpoint secret. Including the time lets receivers reject
+// replayed requests.
+func SignWebhookPayload(secret string, sentAt time.Time, body []byte) string {
+	timestamp := strconv.FormatInt(sentAt.Unix(), 10)
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return fmt.Sprintf("t=%s,v1=%s", timestamp, hex.EncodeToString(mac.Sum(nil)))
+}
+
+func sendWebhook(
+	ctx context.Context,
+	endpoint models.WebhookEndpoint,
+	delivery models.WebhookDelivery,
+	sentAt time.Time,
+) (int64, error) {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "plyo-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.String())
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(endpoint.Secret, sentAt, body))
+
+	resp, err := webhookClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return int64(resp.StatusCode), nil
+	}
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookMaxErrorBodyLen))
+	if len(snippet) == 0 {
+		return int64(resp.StatusCode), fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+
+	return int64(resp.StatusCode), fmt.Errorf("endpoint responded with %s: %s", resp.Status, snippet)
+}
+
+// webhookBackoff returns the delay before the attempt following the given
+// one: 30s, 1m, 2m, ... capped at an hour.
+func webhookBackoff(attempts int64) time.Duration {
+	delay := webhookRetryBaseDelay
+	for i := int64(1); i < attempts; i++ {
+		delay *= 2
+		if delay >= webhookRetryMaxDelay {
+			return webhookRetryMaxDelay
+		}
+	}
+
+	return delay
+}
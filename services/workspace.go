@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/mbvlabs/plyo-hackathon/config"
+	"github.com/mbvlabs/plyo-hackathon/models"
+	"github.com/mbvlabs/plyo-hackathon/router/routes"
+)
+
+// WorkspaceInvitationTTL is how long an invitation can be accepted.
+const WorkspaceInvitationTTL = 7 * 24 * time.Hour
+
+var (
+	ErrInvitationInvalid       = errors.New("this invitation is no longer valid")
+	ErrInvitationExpired       = errors.New("this invitation has expired")
+	ErrInvitationEmailMismatch = errors.New("this invitation was sent to a different email address")
+	// ErrLastOwner is returned when a change would leave a workspace without
+	// anyone able to manage it.
+	ErrLastOwner = errors.New("a workspace needs at least one owner")
+)
+
+// CreateWorkspaceWithOwner creates a workspace and makes userID its owner.
+func CreateWorkspaceWithOwner(
+	ctx context.Context,
+	conn *sql.DB,
+	name string,
+	userID uuid.UUID,
+) (models.Workspace, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.Workspace{}, err
+	}
+	defer tx.Rollback()
+
+	workspace, err := models.CreateWorkspace(ctx, tx, models.CreateWorkspaceData{
+		Name: strings.TrimSpace(name),
+	})
+	if err != nil {
+		return models.Workspace{}, err
+	}
+
+	if _, err := models.CreateWorkspaceMembership(ctx, tx, models.CreateWorkspaceMembershipData{
+		WorkspaceID: workspace.ID,
+		UserID:      userID,
+		Role:        models.WorkspaceRoleOwner,
+	}); err != nil {
+		return models.Workspace{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.Workspace{}, err
+	}
+
+	return workspace, nil
+}
+
+// InviteWorkspaceMember creates an invitation for email to join a workspace
+// with role and returns it with its plaintext token. There is no mailer, so
+// the inviting owner passes the link on themselves.
+func InviteWorkspaceMember(
+	ctx context.Context,
+	conn *sql.DB,
+	workspaceID uuid.UUID,
+	invitedBy uuid.UUID,
+	email string,
+	role string,
+	now time.Time,
+) (models.WorkspaceInvitation, string, error) {
+	return models.CreateWorkspaceInvitation(ctx, conn, models.CreateWorkspaceInvitationData{
+		WorkspaceID:     workspaceID,
+		Email:           strings.TrimSpace(email),
+		Role:            role,
+		InvitedByUserID: invitedBy.String(),
+		ExpiresAt:       now.Add(WorkspaceInvitationTTL),
+	})
+}
+
+// WorkspaceInvitationURL returns the absolute URL accepting an invitation.
+func WorkspaceInvitationURL(token string) string {
+	return config.App.GetFullDomain() + routes.InvitationShow.GetPath(token)
+}
+
+// AcceptWorkspaceInvitation adds user to the workspace of the invitation
+// behind token. The user must be signed in with the invited email address.
+// Accepting an invitation to a workspace the user already belongs to keeps
+// their current role.
+func AcceptWorkspaceInvitation(
+	ctx context.Context,
+	conn *sql.DB,
+	token string,
+	user models.User,
+	now time.Time,
+) (models.WorkspaceMembership, error) {
+	invitation, err := models.FindWorkspaceInvitationByToken(ctx, conn, token)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.WorkspaceMembership{}, ErrInvitationInvalid
+	}
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+
+	switch {
+	case invitation.Accepted(), invitation.Revoked():
+		return models.WorkspaceMembership{}, ErrInvitationInvalid
+	case invitation.Expired(now):
+		return models.WorkspaceMembership{}, ErrInvitationExpired
+	case !invitation.MatchesEmail(user.Email):
+		return models.WorkspaceMembership{}, ErrInvitationEmailMismatch
+	}
+
+	workspaceID := uuid.MustParse(invitation.WorkspaceID)
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+	defer tx.Rollback()
+
+	accepted, err := models.AcceptWorkspaceInvitation(ctx, tx, invitation.ID, now)
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+	if !accepted {
+		return models.WorkspaceMembership{}, ErrInvitationInvalid
+	}
+
+	membership, err := models.FindWorkspaceMembershipByUserID(ctx, tx, workspaceID, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		membership, err = models.CreateWorkspaceMembership(ctx, tx, models.CreateWorkspaceMembershipData{
+			WorkspaceID: workspaceID,
+			UserID:      user.ID,
+			Role:        invitation.Role,
+		})
+	}
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+
+	return membership, nil
+}
+
+// ChangeWorkspaceMemberRole gives a member a new role. The last owner of a
+// workspace cannot be demoted.
+func ChangeWorkspaceMemberRole(
+	ctx context.Context,
+	conn *sql.DB,
+	workspaceID uuid.UUID,
+	membershipID uuid.UUID,
+	role string,
+) (models.WorkspaceMembership, error) {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+	defer tx.Rollback()
+
+	membership, err := models.FindWorkspaceMembership(ctx, tx, workspaceID, membershipID)
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+
+	if membership.Role == models.WorkspaceRoleOwner && role != models.WorkspaceRoleOwner {
+		if err := ensureAnotherOwner(ctx, tx, workspaceID); err != nil {
+			return models.WorkspaceMembership{}, err
+		}
+	}
+
+	membership, err = models.UpdateWorkspaceMembershipRole(ctx, tx, models.UpdateWorkspaceMembershipRoleData{
+		ID:          membershipID,
+		WorkspaceID: workspaceID,
+		Role:        role,
+	})
+	if err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return models.WorkspaceMembership{}, err
+	}
+
+	return membership, nil
+}
+
+// RemoveWorkspaceMember removes a member from a workspace. The last owner of
+// a workspace cannot be removed.
+func RemoveWorkspaceMember(
+	ctx context.Context,
+	conn *sql.DB,
+	workspaceID uuid.UUID,
+	membershipID uuid.UUID,
+) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	membership, err := models.FindWorkspaceMembership(ctx, tx, workspaceID, membershipID)
+	if err != nil {
+		return err
+	}
+
+	if membership.Role == models.WorkspaceRoleOwner {
+		if err := ensureAnotherOwner(ctx, tx, workspaceID); err != nil {
+			return err
+		}
+	}
+
+	if err := models.DestroyWorkspaceMembership(ctx, tx, workspaceID, membershipID); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func ensureAnotherOwner(ctx context.Context, tx *sql.Tx, workspaceID uuid.UUID) error {
+	owners, err := models.CountWorkspaceOwners(ctx, tx, workspaceID)
+	if err != nil {
+		return err
+	}
+	if owners <= 1 {
+		return ErrLastOwner
+	}
+
+	return nil
+}
@@ -0,0 +1,193 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// trackingParams are query parameters that only tell a site where a visitor
+// came from, and are dropped so links shared from different places share a
+// cache entry.
+var trackingParams = []string{"utm_", "gclid", "fbclid", "msclkid", "mc_cid", "mc_eid"}
+
+// CacheStats counts the lookups of one tool's cache since the process
+// started.
+type CacheStats struct {
+	Hits   int64
+	Misses int64
+}
+
+// Cache keeps tool results in the database, so the agents of a report, and
+// of later reports, reuse pages and searches instead of paying for them
+// again. Every tool has its own TTL; a tool without one is not cached.
+//
+// A nil Cache caches nothing.
+type Cache struct {
+	conn *sql.DB
+	ttls map[string]time.Duration
+	// bypass skips lookups, so every call is made again and refreshes its
+	// entry.
+	bypass bool
+
+	mu    sync.Mutex
+	stats map[string]*CacheStats
+}
+
+func NewCache(conn *sql.DB, ttls map[string]time.Duration, bypass bool) *Cache {
+	return &Cache{
+		conn:   conn,
+		ttls:   ttls,
+		bypass: bypass,
+		stats:  map[string]*CacheStats{},
+	}
+}
+
+// Get returns the tool's unexpired result for the key.
+func (c *Cache) Get(ctx context.Context, tool, key string) (string, bool) {
+	if c.ttl(tool) <= 0 {
+		return "", false
+	}
+
+	if !c.bypass {
+		entry, err := models.FindToolCacheEntry(ctx, c.conn, tool, key, time.Now().UTC())
+		if err == nil {
+			c.count(tool, true)
+			slog.Debug("tool cache hit", "tool", tool, "key", key, "fetched_at", entry.UpdatedAt)
+			return entry.Value, true
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			slog.Warn("failed to read tool cache", "tool", tool, "key", key, "error", err)
+		}
+	}
+
+	c.count(tool, false)
+	slog.Debug("tool cache miss", "tool", tool, "key", key, "bypass", c.bypass)
+	return "", false
+}
+
+// Put stores the tool's result for the key for the tool's TTL, and clears
+// out expired entries.
+func (c *Cache) Put(ctx context.Context, tool, key, value string) {
+	ttl := c.ttl(tool)
+	if ttl <= 0 || value == "" {
+		return
+	}
+
+	now := time.Now().UTC()
+	if _, err := models.StoreToolCacheEntry(ctx, c.conn, models.StoreToolCacheEntryData{
+		Tool:      tool,
+		CacheKey:  key,
+		Value:     value,
+		ExpiresAt: now.Add(ttl),
+	}); err != nil {
+		slog.Warn("failed to write tool cache", "tool", tool, "key", key, "error", err)
+		return
+	}
+
+	if err := models.DestroyExpiredToolCacheEntries(ctx, c.conn, now); err != nil {
+		slog.Warn("failed to clear expired tool cache entries", "error", err)
+	}
+}
+
+// Stats returns the hits and misses of each tool's cache.
+func (c *Cache) Stats() map[string]CacheStats {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make(map[string]CacheStats, len(c.stats))
+	for tool, s := range c.stats {
+		stats[tool] = *s
+	}
+
+	return stats
+}
+
+// LogStats logs the hits and misses of each tool's cache.
+func (c *Cache) LogStats(ctx context.Context) {
+	for tool, s := range c.Stats() {
+		slog.InfoContext(ctx, "tool cache stats", "tool", tool, "hits", s.Hits, "misses", s.Misses)
+	}
+}
+
+func (c *Cache) ttl(tool string) time.Duration {
+	if c == nil {
+		return 0
+	}
+
+	return c.ttls[tool]
+}
+
+func (c *Cache) count(tool string, hit bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.stats[tool]
+	if !ok {
+		s = &CacheStats{}
+		c.stats[tool] = s
+	}
+	if hit {
+		s.Hits++
+	} else {
+		s.Misses++
+	}
+}
+
+// NormalizeURL returns the cache key of a page URL: scheme and host lower
+// cased, default ports, credentials, fragments and tracking parameters
+// dropped, and the remaining query sorted.
+func NormalizeURL(rawURL string) string {
+	rawURL = strings.TrimSpace(rawURL)
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]"
+	}
+	port := u.Port()
+	if port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	query := u.Query()
+	for name := range query {
+		for _, tracking := range trackingParams {
+			if strings.HasPrefix(strings.ToLower(name), tracking) {
+				query.Del(name)
+				break
+			}
+		}
+	}
+	u.RawQuery = query.Encode()
+
+	return u.String()
+}
+
+// NormalizeQuery returns the cache key of a search query: lower cased, with
+// whitespace collapsed.
+func NormalizeQuery(query string) string {
+	return strings.Join(strings.Fields(strings.ToLower(query)), " ")
+}
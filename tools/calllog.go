@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"github.com/mbvlabs/plyo-hackathon/models"
+)
+
+// Failure is a tool call that failed in a way the model is told about and
+// can work around, such as a page that could not be fetched, rather than
+// one that ends the agent's run. Message is the result the model gets.
+type Failure struct {
+	Message string
+	Err     error
+}
+
+func (f *Failure) Error() string {
+	if f.Err != nil {
+		return f.Err.Error()
+	}
+
+	return f.Message
+}
+
+func (f *Failure) Unwrap() error {
+	return f.Err
+}
+
+type callScopeKey struct{}
+
+// callScope is the workspace and report tool calls are made for.
+type callScope struct {
+	workspaceID string
+	reportID    string
+}
+
+// WithCallScope returns a context whose tool calls are logged as made for
+// the workspace and report. Either may be empty, as the preliminary research
+// runs before there is a report.
+func WithCallScope(ctx context.Context, workspaceID, reportID string) context.Context {
+	return context.WithValue(ctx, callScopeKey{}, callScope{workspaceID, reportID})
+}
+
+type servingKey struct{}
+
+// Serving is how a tool served a call: the backend that answered it, such
+// as the free direct fetch or a paid API, and whether the result came from
+// the cache instead. Tools report it with SetServing.
+type Serving struct {
+	// Backend names the backends that answered, comma-separated when
+	// several did. It is empty when the call reached none.
+	Backend string
+	Cached  bool
+}
+
+// WithServing returns a context a tool called with can report its Serving
+// in, and the Serving it reports into.
+func WithServing(ctx context.Context) (context.Context, *Serving) {
+	serving := &Serving{}
+	return context.WithValue(ctx, servingKey{}, serving), serving
+}
+
+// SetServing reports how the call made with ctx was served. Outside a
+// context from WithServing it does nothing.
+func SetServing(ctx context.Context, backend string, cached bool) {
+	if serving, ok := ctx.Value(servingKey{}).(*Serving); ok {
+		serving.Backend, serving.Cached = backend, cached
+	}
+}
+
+// Call is one tool call of an agent, as the provider's tool loop saw it.
+type Call struct {
+	Agent     string
+	Tool      string
+	Arguments string
+	// Status is one of models.ToolCallStatuses.
+	Status       string
+	Latency      time.Duration
+	ResponseSize int
+	Error        string
+	Serving      Serving
+}
+
+// CallLog keeps a record of every tool call in the database, so what was
+// searched and scraped for a report can be checked against vendor invoices
+// and agent behaviour.
+//
+// A nil CallLog records nothing.
+type CallLog struct {
+	conn *sql.DB
+}
+
+func NewCallLog(conn *sql.DB) *CallLog {
+	return &CallLog{conn}
+}
+
+// Record stores the call for the workspace and report of ctx. Failing to
+// store it must not fail the research, so it is only logged.
+func (l *CallLog) Record(ctx context.Context, call Call) {
+	if l == nil {
+		return
+	}
+
+	scope, _ := ctx.Value(callScopeKey{}).(callScope)
+	// Calls stopped by a cancellation are recorded all the same.
+	ctx = context.WithoutCancel(ctx)
+
+	if _, err := models.CreateToolCall(ctx, l.conn, models.CreateToolCallData{
+		WorkspaceID:  scope.workspaceID,
+		ReportID:     scope.reportID,
+		Agent:        call.Agent,
+		Tool:         call.Tool,
+		Arguments:    call.Arguments,
+		Status:       call.Status,
+		Latency:      call.Latency,
+		ResponseSize: int64(call.ResponseSize),
+		ErrorMessage: call.Error,
+		Backend:      call.Serving.Backend,
+		Cached:       call.Serving.Cached,
+	}); err != nil {
+		slog.WarnContext(ctx, "failed to record tool call", "agent", call.Agent, "tool", call.Tool, "error", err)
+	}
+}
@@ -0,0 +1,596 @@
+package tools
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// charsPerToken approximates how many bytes of English prose make up one
+// model token. Pages only need to land close to their budget, so no
+// tokenizer is involved.
+const charsPerToken = 4
+
+// ExtractContent turns a fetched page into what scraping tools hand to the
+// model: for HTML pages the main content as markdown, without scripts,
+// styles, navigation and other boilerplate, with headings and links kept.
+// Other pages are passed on as they are. The result is truncated to
+// maxTokens, ending with a note on what was cut; zero means no limit.
+func ExtractContent(page []byte, pageURL string, maxTokens int) string {
+	text := string(page)
+	if looksLikeHTML(page) {
+		text = htmlToMarkdown(page, pageURL)
+	}
+
+	return truncateToTokens(text, maxTokens)
+}
+
+func looksLikeHTML(page []byte) bool {
+	if strings.HasPrefix(http.DetectContentType(page), "text/html") {
+		return true
+	}
+
+	head := bytes.ToLower(page[:min(len(page), 1024)])
+	return bytes.Contains(head, []byte("<html")) || bytes.Contains(head, []byte("<body"))
+}
+
+func htmlToMarkdown(page []byte, pageURL string) string {
+	doc, err := html.Parse(bytes.NewReader(page))
+	if err != nil {
+		return string(page)
+	}
+
+	base, _ := url.Parse(pageURL)
+	if href := baseHref(doc); href != "" && base != nil {
+		if resolved, err := base.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+
+	root := mainContent(doc)
+	w := markdownWriter{base: base, inContent: root.DataAtom != atom.Body && root.Type == html.ElementNode}
+	w.render(root)
+
+	var out strings.Builder
+	if title := strings.Join(strings.Fields(nodeText(findFirst(doc, atom.Title))), " "); title != "" {
+		fmt.Fprintf(&out, "Title: %s\n", title)
+	}
+	if pageURL != "" {
+		fmt.Fprintf(&out, "URL: %s\n", pageURL)
+	}
+	if out.Len() > 0 {
+		out.WriteString("\n")
+	}
+	out.WriteString(cleanMarkdown(w.b.String()))
+
+	return out.String()
+}
+
+// mainContent returns the element holding the page's main content: a main
+// or article element holding most of the page's text, else the element the
+// most paragraph text sits in, else the body.
+func mainContent(doc *html.Node) *html.Node {
+	body := findFirst(doc, atom.Body)
+	if body == nil {
+		return doc
+	}
+
+	bodyText := contentLen(body)
+	if bodyText == 0 {
+		return body
+	}
+
+	var best *html.Node
+	bestLen := 0
+	walk(body, func(n *html.Node) bool {
+		if isBoilerplate(n, false) {
+			return false
+		}
+		if n.DataAtom == atom.Main || n.DataAtom == atom.Article || attr(n, "role") == "main" {
+			if l := contentLen(n); l > bestLen {
+				best, bestLen = n, l
+			}
+		}
+		return true
+	})
+	if best != nil && bestLen*4 >= bodyText {
+		return best
+	}
+
+	// Without semantic markup, credit each paragraph's text to its parent
+	// and half of it to its grandparent, as readability tools do.
+	scores := map[*html.Node]int{}
+	paragraphText := 0
+	walk(body, func(n *html.Node) bool {
+		if isBoilerplate(n, false) {
+			return false
+		}
+		if n.DataAtom != atom.P && n.DataAtom != atom.Pre && n.DataAtom != atom.Blockquote {
+			return true
+		}
+
+		l := contentLen(n)
+		paragraphText += l
+		if n.Parent != nil {
+			scores[n.Parent] += l
+			if n.Parent.Parent != nil {
+				scores[n.Parent.Parent] += l / 2
+			}
+		}
+		return false
+	})
+
+	var candidate *html.Node
+	for n, score := range scores {
+		if candidate == nil || score > scores[candidate] {
+			candidate = n
+		}
+	}
+	if candidate != nil && candidate != body && paragraphLen(candidate)*2 >= paragraphText {
+		return candidate
+	}
+
+	return body
+}
+
+var boilerplateNames = regexp.MustCompile(
+	`(?i)(^|[\s_-])(cookies?|consent|gdpr|banner|navbar|nav|navigation|menu|breadcrumbs?|footer|sidebar|share|social|popup|modal|newsletter|subscribe|advert|ads|promo|skip-link)($|[\s_-])`,
+)
+
+// isBoilerplate reports whether n never holds content worth sending to the
+// model. Site headers are boilerplate, but an article's header is not,
+// which is what inContent tells apart.
+func isBoilerplate(n *html.Node, inContent bool) bool {
+	if n.Type == html.CommentNode {
+		return true
+	}
+	if n.Type != html.ElementNode {
+		return false
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Svg, atom.Math, atom.Iframe,
+		atom.Template, atom.Canvas, atom.Object, atom.Embed, atom.Head, atom.Form,
+		atom.Button, atom.Select, atom.Input, atom.Textarea, atom.Dialog,
+		atom.Nav, atom.Footer, atom.Aside:
+		return true
+	case atom.Header:
+		return !inContent
+	case atom.Html, atom.Body, atom.Main, atom.Article:
+		return false
+	}
+
+	if _, hidden := attrValue(n, "hidden"); hidden || attr(n, "aria-hidden") == "true" {
+		return true
+	}
+	if style := strings.ReplaceAll(strings.ToLower(attr(n, "style")), " ", ""); strings.Contains(style, "display:none") ||
+		strings.Contains(style, "visibility:hidden") {
+		return true
+	}
+
+	switch attr(n, "role") {
+	case "navigation", "banner", "contentinfo", "complementary", "dialog", "search", "menu", "menubar":
+		return true
+	}
+
+	// Class names are a weaker signal: wrappers named after an open menu
+	// or similar sometimes hold the whole page.
+	if boilerplateNames.MatchString(attr(n, "class")) || boilerplateNames.MatchString(attr(n, "id")) {
+		return findFirst(n, atom.Main) == nil && findFirst(n, atom.Article) == nil && findFirst(n, atom.H1) == nil
+	}
+
+	return false
+}
+
+type markdownWriter struct {
+	b         strings.Builder
+	base      *url.URL
+	inContent bool
+}
+
+func (w *markdownWriter) render(n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		w.text(n.Data)
+		return
+	case html.DocumentNode:
+		w.children(n)
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	if isBoilerplate(n, w.inContent) {
+		return
+	}
+
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		if text := w.inline(n); text != "" {
+			level, _ := strconv.Atoi(n.Data[1:])
+			w.block()
+			w.b.WriteString(strings.Repeat("#", level) + " " + text)
+			w.block()
+		}
+	case atom.P, atom.Div, atom.Section, atom.Figure, atom.Figcaption, atom.Dl, atom.Dt,
+		atom.Dd, atom.Address, atom.Details, atom.Summary, atom.Header:
+		w.block()
+		w.children(n)
+		w.block()
+	case atom.Main, atom.Article:
+		inContent := w.inContent
+		w.inContent = true
+		w.block()
+		w.children(n)
+		w.block()
+		w.inContent = inContent
+	case atom.Br:
+		w.b.WriteString("\n")
+	case atom.Hr:
+		w.block()
+		w.b.WriteString("---")
+		w.block()
+	case atom.Ul, atom.Ol:
+		w.list(n)
+	case atom.A:
+		w.link(n)
+	case atom.Pre:
+		w.block()
+		w.b.WriteString("```\n" + strings.Trim(nodeText(n), "\n") + "\n```")
+		w.block()
+	case atom.Code:
+		if text := strings.TrimSpace(nodeText(n)); text != "" {
+			w.b.WriteString("`" + text + "`")
+		}
+	case atom.Blockquote:
+		inner := w.sub()
+		inner.children(n)
+		if text := cleanMarkdown(inner.b.String()); text != "" {
+			w.block()
+			w.b.WriteString("> " + strings.ReplaceAll(text, "\n", "\n> "))
+			w.block()
+		}
+	case atom.Table:
+		w.table(n)
+	case atom.Img:
+		// Images carry no text for the model beyond their alt text, which
+		// mostly repeats the surrounding copy.
+	default:
+		w.children(n)
+	}
+}
+
+func (w *markdownWriter) children(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.render(c)
+	}
+}
+
+// text writes a text node with its whitespace collapsed the way browsers
+// render it.
+func (w *markdownWriter) text(data string) {
+	collapsed := strings.Join(strings.Fields(data), " ")
+	if collapsed == "" {
+		if data != "" && !w.atLineStart() && !strings.HasSuffix(w.b.String(), " ") {
+			w.b.WriteString(" ")
+		}
+		return
+	}
+
+	if startsWithSpace(data) && !w.atLineStart() && !strings.HasSuffix(w.b.String(), " ") {
+		w.b.WriteString(" ")
+	}
+	w.b.WriteString(collapsed)
+	if endsWithSpace(data) {
+		w.b.WriteString(" ")
+	}
+}
+
+func (w *markdownWriter) atLineStart() bool {
+	s := w.b.String()
+	return s == "" || strings.HasSuffix(s, "\n")
+}
+
+// block starts a new paragraph.
+func (w *markdownWriter) block() {
+	if w.b.Len() > 0 {
+		w.b.WriteString("\n\n")
+	}
+}
+
+// sub returns a writer for rendering part of the page on its own, such as
+// a link's text or a table cell.
+func (w *markdownWriter) sub() *markdownWriter {
+	return &markdownWriter{base: w.base, inContent: w.inContent}
+}
+
+// inline renders n's children as a single line.
+func (w *markdownWriter) inline(n *html.Node) string {
+	inner := w.sub()
+	inner.children(n)
+	return strings.Join(strings.Fields(inner.b.String()), " ")
+}
+
+func (w *markdownWriter) link(n *html.Node) {
+	text := w.inline(n)
+	if text == "" {
+		return
+	}
+
+	href := strings.TrimSpace(attr(n, "href"))
+	lower := strings.ToLower(href)
+	if href == "" || strings.HasPrefix(href, "#") || strings.HasPrefix(lower, "javascript:") {
+		w.b.WriteString(text)
+		return
+	}
+
+	if w.base != nil {
+		if resolved, err := w.base.Parse(href); err == nil {
+			href = resolved.String()
+		}
+	}
+
+	fmt.Fprintf(&w.b, "[%s](%s)", text, href)
+}
+
+func (w *markdownWriter) list(n *html.Node) {
+	w.block()
+
+	number := 1
+	for item := n.FirstChild; item != nil; item = item.NextSibling {
+		if item.Type != html.ElementNode || item.DataAtom != atom.Li || isBoilerplate(item, w.inContent) {
+			continue
+		}
+
+		inner := w.sub()
+		inner.children(item)
+		// Paragraphs and nested lists inside an item stay on its lines,
+		// indented below its marker.
+		text := strings.ReplaceAll(cleanMarkdown(inner.b.String()), "\n\n", "\n")
+		if text == "" {
+			continue
+		}
+
+		marker := "- "
+		if n.DataAtom == atom.Ol {
+			marker = strconv.Itoa(number) + ". "
+			number++
+		}
+
+		if !w.atLineStart() {
+			w.b.WriteString("\n")
+		}
+		w.b.WriteString(marker + strings.ReplaceAll(text, "\n", "\n  ") + "\n")
+	}
+
+	w.block()
+}
+
+func (w *markdownWriter) table(n *html.Node) {
+	var rows [][]string
+	header := false
+	walk(n, func(c *html.Node) bool {
+		if c.Type != html.ElementNode || c.DataAtom != atom.Tr {
+			// Nested tables are flattened into their cell.
+			return c == n || c.DataAtom != atom.Table
+		}
+
+		var cells []string
+		for cell := c.FirstChild; cell != nil; cell = cell.NextSibling {
+			if cell.DataAtom != atom.Td && cell.DataAtom != atom.Th {
+				continue
+			}
+			if cell.DataAtom == atom.Th && len(rows) == 0 {
+				header = true
+			}
+			cells = append(cells, strings.ReplaceAll(w.inline(cell), "|", "\\|"))
+		}
+		if strings.TrimSpace(strings.Join(cells, "")) != "" {
+			rows = append(rows, cells)
+		}
+		return false
+	})
+	if len(rows) == 0 {
+		return
+	}
+
+	w.block()
+	for i, cells := range rows {
+		w.b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		if i == 0 && header {
+			w.b.WriteString(strings.Repeat("| --- ", len(cells)) + "|\n")
+		}
+	}
+	w.block()
+}
+
+var blankLines = regexp.MustCompile(`\n{3,}`)
+
+// cleanMarkdown trims trailing spaces and collapses runs of blank lines
+// outside code blocks.
+func cleanMarkdown(text string) string {
+	lines := strings.Split(text, "\n")
+	inFence := false
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			lines[i] = strings.TrimRight(line, " \t")
+		}
+	}
+
+	return strings.TrimSpace(blankLines.ReplaceAllString(strings.Join(lines, "\n"), "\n\n"))
+}
+
+// truncateToTokens cuts text down to about maxTokens, at a paragraph break
+// where possible, and notes how much was cut and which sections it held.
+func truncateToTokens(text string, maxTokens int) string {
+	limit := maxTokens * charsPerToken
+	if maxTokens <= 0 || len(text) <= limit {
+		return text
+	}
+
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	if i := strings.LastIndex(text[:cut], "\n\n"); i > limit/2 {
+		cut = i
+	} else if i := strings.LastIndex(text[:cut], "\n"); i > limit/2 {
+		cut = i
+	}
+
+	// A heading whose section was cut is listed with the cut sections.
+	for {
+		i := strings.LastIndex(strings.TrimRight(text[:cut], "\n "), "\n")
+		if i <= 0 || !strings.HasPrefix(text[i+1:], "#") {
+			break
+		}
+		cut = i
+	}
+
+	kept := strings.TrimRight(text[:cut], "\n ")
+	if strings.Count(kept, "```")%2 == 1 {
+		kept += "\n```"
+	}
+
+	var headings []string
+	for line := range strings.SplitSeq(text[cut:], "\n") {
+		if heading, ok := strings.CutPrefix(line, "#"); ok {
+			headings = append(headings, strings.TrimSpace(strings.TrimLeft(heading, "#")))
+		}
+	}
+
+	note := fmt.Sprintf(
+		"[Truncated: kept about %d of %d tokens.",
+		len(kept)/charsPerToken,
+		len(text)/charsPerToken,
+	)
+	switch {
+	case len(headings) > 10:
+		note += fmt.Sprintf(" Cut sections: %s and %d more.", strings.Join(headings[:10], "; "), len(headings)-10)
+	case len(headings) > 0:
+		note += fmt.Sprintf(" Cut sections: %s.", strings.Join(headings, "; "))
+	}
+	note += "]"
+
+	return kept + "\n\n" + note
+}
+
+func walk(n *html.Node, visit func(*html.Node) bool) {
+	if !visit(n) {
+		return
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		walk(c, visit)
+	}
+}
+
+func findFirst(n *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(n, func(c *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if c.Type == html.ElementNode && c.DataAtom == a {
+			found = c
+			return false
+		}
+		return true
+	})
+
+	return found
+}
+
+func baseHref(doc *html.Node) string {
+	if base := findFirst(doc, atom.Base); base != nil {
+		return attr(base, "href")
+	}
+
+	return ""
+}
+
+// nodeText returns the raw text below n, boilerplate included.
+func nodeText(n *html.Node) string {
+	if n == nil {
+		return ""
+	}
+
+	var b strings.Builder
+	walk(n, func(c *html.Node) bool {
+		if c.Type == html.TextNode {
+			b.WriteString(c.Data)
+		}
+		return true
+	})
+
+	return b.String()
+}
+
+// contentLen is the length of the text below n outside boilerplate.
+func contentLen(n *html.Node) int {
+	total := 0
+	walk(n, func(c *html.Node) bool {
+		if c != n && isBoilerplate(c, true) {
+			return false
+		}
+		if c.Type == html.TextNode {
+			total += len(strings.TrimSpace(c.Data))
+		}
+		return true
+	})
+
+	return total
+}
+
+// paragraphLen is the length of the paragraph text below n.
+func paragraphLen(n *html.Node) int {
+	total := 0
+	walk(n, func(c *html.Node) bool {
+		if c != n && isBoilerplate(c, true) {
+			return false
+		}
+		if c.DataAtom == atom.P || c.DataAtom == atom.Pre || c.DataAtom == atom.Blockquote {
+			total += contentLen(c)
+			return false
+		}
+		return true
+	})
+
+	return total
+}
+
+func attr(n *html.Node, key string) string {
+	value, _ := attrValue(n, key)
+	return value
+}
+
+func attrValue(n *html.Node, key string) (string, bool) {
+	for _, a := range n.Attr {
+		if a.Namespace == "" && a.Key == key {
+			return a.Val, true
+		}
+	}
+
+	return "", false
+}
+
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
+
+func endsWithSpace(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return r == ' ' || r == '\n' || r == '\t' || r == '\r'
+}
@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+const (
+	httpRetryBaseDelay = 500 * time.Millisecond
+	// httpRetryMaxDelay caps the wait between two attempts, however long a
+	// Retry-After header asks for.
+	httpRetryMaxDelay = 10 * time.Second
+)
+
+// HTTPClientConfig configures the HTTP client the tools call search and
+// scraping APIs with.
+type HTTPClientConfig struct {
+	// Timeout bounds a request, retries included.
+	Timeout time.Duration
+	// ProxyURL is the proxy requests go through. Empty uses the proxy of
+	// the environment, if any.
+	ProxyURL string
+	// MaxRetries is how often an idempotent request is retried after a
+	// network error or a 429, 502, 503 or 504 response.
+	MaxRetries int
+}
+
+// NewHTTPClient returns a client with the configured timeout, proxy and
+// retries, which logs every request it makes.
+func NewHTTPClient(cfg HTTPClientConfig) (*http.Client, error) {
+	proxy := http.ProxyFromEnvironment
+	if cfg.ProxyURL != "" {
+		proxyURL, err := url.Parse(cfg.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		proxy = http.ProxyURL(proxyURL)
+	}
+
+	transport := &http.Transport{
+		Proxy: proxy,
+		DialContext: (&net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          50,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+
+	return &http.Client{
+		Transport: newRetryTransport(transport, cfg.MaxRetries),
+		Timeout:   cfg.Timeout,
+	}, nil
+}
+
+// retryTransport retries idempotent requests that failed in a way a retry
+// may fix, and logs every attempt.
+type retryTransport struct {
+	next       http.RoundTripper
+	maxRetries int
+}
+
+func newRetryTransport(next http.RoundTripper, maxRetries int) *retryTransport {
+	return &retryTransport{next: next, maxRetries: maxRetries}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := idempotent(req) && (req.Body == nil || req.GetBody != nil)
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.Body != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		start := time.Now()
+		resp, err := t.next.RoundTrip(attemptReq)
+		logAttrs := []any{
+			"method", req.Method,
+			"host", req.URL.Host,
+			"attempt", attempt + 1,
+			"duration", time.Since(start),
+		}
+		if err != nil {
+			logAttrs = append(logAttrs, "error", err)
+		} else {
+			logAttrs = append(logAttrs, "status", resp.StatusCode)
+		}
+		slog.DebugContext(req.Context(), "tool http request", logAttrs...)
+
+		if !retryable || attempt >= t.maxRetries || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := retryDelay(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		slog.WarnContext(req.Context(), "retrying tool http request", append(logAttrs, "delay", delay)...)
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// idempotent reports whether the request can be sent again without
+// repeating a side effect. A POST that only reads, such as a search, says
+// so with an Idempotency-Key header, as net/http's own retries expect; a
+// nil value marks it without sending the header.
+func idempotent(req *http.Request) bool {
+	switch req.Method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace, http.MethodPut, http.MethodDelete:
+		return true
+	}
+
+	_, ok := req.Header["Idempotency-Key"]
+	return ok
+}
+
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryDelay waits what the server asked for in Retry-After, else backs off
+// exponentially with jitter.
+func retryDelay(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds >= 0 {
+			return min(time.Duration(seconds)*time.Second, httpRetryMaxDelay)
+		}
+	}
+
+	delay := httpRetryBaseDelay << attempt
+	delay += rand.N(delay / 2)
+
+	return min(delay, httpRetryMaxDelay)
+}
@@ -0,0 +1,90 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+)
+
+// Allowance lets an agent use a tool, at most Quota times a run; zero
+// leaves the tool unlimited.
+type Allowance struct {
+	Tool  string
+	Quota int
+}
+
+// Registry holds the tools agents are given their toolsets from, by name,
+// and the log their calls are recorded in.
+type Registry struct {
+	tools   map[string]Tooler
+	callLog *CallLog
+}
+
+func NewRegistry(callLog *CallLog, tools ...Tooler) Registry {
+	registry := Registry{
+		tools:   map[string]Tooler{},
+		callLog: callLog,
+	}
+	for _, tool := range tools {
+		registry.tools[tool.GetName()] = tool
+	}
+
+	return registry
+}
+
+// Toolset returns the agent's toolset of the tools the allowances name.
+// Naming a tool the registry does not hold is an error, so a misspelled
+// allowance does not leave an agent without the tool unnoticed.
+func (r Registry) Toolset(agent string, allowances []Allowance) (Toolset, error) {
+	toolset := Toolset{
+		agent:   agent,
+		tools:   map[string]Tooler{},
+		quotas:  map[string]int{},
+		callLog: r.callLog,
+	}
+	for _, allowance := range allowances {
+		tool, ok := r.tools[allowance.Tool]
+		if !ok {
+			return Toolset{}, fmt.Errorf("unknown tool %q", allowance.Tool)
+		}
+		if allowance.Quota < 0 {
+			return Toolset{}, fmt.Errorf("quota of tool %q is negative", allowance.Tool)
+		}
+		toolset.tools[allowance.Tool] = tool
+		toolset.quotas[allowance.Tool] = allowance.Quota
+	}
+
+	return toolset, nil
+}
+
+// Toolset is the tools an agent may use, with how often it may call each in
+// a run. The zero Toolset has no tools.
+type Toolset struct {
+	agent   string
+	tools   map[string]Tooler
+	quotas  map[string]int
+	callLog *CallLog
+}
+
+// Names returns the names of the tools, sorted.
+func (t Toolset) Names() []string {
+	return slices.Sorted(maps.Keys(t.tools))
+}
+
+func (t Toolset) Tool(name string) (Tooler, bool) {
+	tool, ok := t.tools[name]
+	return tool, ok
+}
+
+// Quota returns how often the tool may be called in a run, zero for
+// unlimited.
+func (t Toolset) Quota(name string) int {
+	return t.quotas[name]
+}
+
+// Record logs a call of the agent's tools.
+func (t Toolset) Record(ctx context.Context, call Call) {
+	call.Agent = t.agent
+	t.callLog.Record(ctx, call)
+}
@@ -0,0 +1,236 @@
+package tools
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	robotsMaxBytes = 512 << 10
+	robotsCacheTTL = time.Hour
+	// robotsRetryTTL is how long a site whose robots.txt could not be
+	// fetched stays off limits before it is tried again.
+	robotsRetryTTL = 5 * time.Minute
+)
+
+// robotsRules are the rules of a robots.txt that apply to one crawler, as
+// specified by RFC 9309.
+type robotsRules struct {
+	rules []robotsRule
+	// disallowAll is set when robots.txt could not be fetched, which the
+	// RFC asks crawlers to treat as a complete disallow.
+	disallowAll bool
+}
+
+type robotsRule struct {
+	allow   bool
+	pattern string
+}
+
+// allows reports whether the path, with its query, may be fetched: the
+// longest matching rule decides, and allow wins a tie.
+func (r robotsRules) allows(path string) bool {
+	if path == "/robots.txt" {
+		return true
+	}
+	if r.disallowAll {
+		return false
+	}
+
+	allowed, longest := true, -1
+	for _, rule := range r.rules {
+		if !robotsMatch(rule.pattern, path) {
+			continue
+		}
+		if len(rule.pattern) > longest || (len(rule.pattern) == longest && rule.allow) {
+			allowed, longest = rule.allow, len(rule.pattern)
+		}
+	}
+
+	return allowed
+}
+
+// parseRobots returns the rules of the groups naming the crawler's product
+// token, or of the "*" group when none does.
+func parseRobots(body io.Reader, productToken string) robotsRules {
+	type group struct {
+		agents []string
+		rules  []robotsRule
+	}
+
+	var groups []*group
+	var current *group
+	lastWasRule := false
+
+	scanner := bufio.NewScanner(body)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if current == nil || lastWasRule {
+				current = &group{}
+				groups = append(groups, current)
+			}
+			current.agents = append(current.agents, strings.ToLower(value))
+			lastWasRule = false
+		case "allow", "disallow":
+			if current == nil {
+				continue
+			}
+			lastWasRule = true
+			// An empty disallow allows everything, which is the default.
+			if value == "" {
+				continue
+			}
+			current.rules = append(current.rules, robotsRule{allow: key == "allow", pattern: value})
+		}
+	}
+
+	productToken = strings.ToLower(productToken)
+
+	// A group naming the crawler applies even when it has no rules, as with
+	// an empty disallow that allows everything.
+	var matched, wildcard []robotsRule
+	named := false
+	for _, g := range groups {
+		for _, agent := range g.agents {
+			switch agent {
+			case productToken:
+				matched = append(matched, g.rules...)
+				named = true
+			case "*":
+				wildcard = append(wildcard, g.rules...)
+			}
+		}
+	}
+	if named {
+		return robotsRules{rules: matched}
+	}
+
+	return robotsRules{rules: wildcard}
+}
+
+// robotsMatch matches a path against a robots.txt pattern, where "*"
+// matches any characters and a trailing "$" anchors the end.
+func robotsMatch(pattern, path string) bool {
+	anchored := strings.HasSuffix(pattern, "$")
+	pattern = strings.TrimSuffix(pattern, "$")
+
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(path, parts[0]) {
+		return false
+	}
+
+	pos := len(parts[0])
+	for i, part := range parts[1:] {
+		if anchored && i == len(parts)-2 {
+			return strings.HasSuffix(path[pos:], part)
+		}
+
+		index := strings.Index(path[pos:], part)
+		if index < 0 {
+			return false
+		}
+		pos += index + len(part)
+	}
+
+	return !anchored || pos == len(path)
+}
+
+type robotsEntry struct {
+	rules     robotsRules
+	expiresAt time.Time
+}
+
+// robotsCache keeps the robots.txt rules of each site fetched from.
+type robotsCache struct {
+	mu      sync.Mutex
+	entries map[string]robotsEntry
+}
+
+func newRobotsCache() *robotsCache {
+	return &robotsCache{entries: map[string]robotsEntry{}}
+}
+
+// allowed reports whether the crawler may fetch target according to its
+// site's robots.txt.
+func (c *robotsCache) allowed(
+	ctx context.Context,
+	client *http.Client,
+	userAgent string,
+	target *url.URL,
+) bool {
+	site := target.Scheme + "://" + target.Host
+
+	c.mu.Lock()
+	entry, ok := c.entries[site]
+	c.mu.Unlock()
+
+	if !ok || time.Now().After(entry.expiresAt) {
+		rules, ttl := fetchRobots(ctx, client, userAgent, site)
+		entry = robotsEntry{rules: rules, expiresAt: time.Now().Add(ttl)}
+
+		c.mu.Lock()
+		c.entries[site] = entry
+		c.mu.Unlock()
+	}
+
+	path := target.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if target.RawQuery != "" {
+		path += "?" + target.RawQuery
+	}
+
+	return entry.rules.allows(path)
+}
+
+func fetchRobots(
+	ctx context.Context,
+	client *http.Client,
+	userAgent string,
+	site string,
+) (robotsRules, time.Duration) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, site+"/robots.txt", nil)
+	if err != nil {
+		return robotsRules{disallowAll: true}, robotsRetryTTL
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return robotsRules{disallowAll: true}, robotsRetryTTL
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return parseRobots(io.LimitReader(resp.Body, robotsMaxBytes), productToken(userAgent)), robotsCacheTTL
+	case resp.StatusCode >= 400 && resp.StatusCode < 500:
+		// A site without robots.txt allows everything.
+		return robotsRules{}, robotsCacheTTL
+	default:
+		return robotsRules{disallowAll: true}, robotsRetryTTL
+	}
+}
+
+// productToken is the name robots.txt addresses a crawler by: its user
+// agent up to the version.
+func productToken(userAgent string) string {
+	token, _, _ := strings.Cut(userAgent, "/")
+	return strings.TrimSpace(token)
+}
@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRobotsMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"/", "/anything", true},
+		{"/private", "/private", true},
+		{"/private", "/private/page", true},
+		{"/private", "/privately", true},
+		{"/private", "/public", false},
+		{"/private/", "/private", false},
+		{"/*.php", "/index.php", true},
+		{"/*.php", "/dir/index.php?x=1", true},
+		{"/*.php", "/index.html", false},
+		{"/*.php$", "/index.php", true},
+		{"/*.php$", "/index.php?x=1", false},
+		{"/*.php$", "/index.phpx", false},
+		{"/fish$", "/fish", true},
+		{"/fish$", "/fish/", false},
+		{"/fish*", "/fish", true},
+		{"/fish*", "/fishheads", true},
+		{"/a*b*c", "/axxbyyc", true},
+		{"/a*b*c", "/axxcyyb", false},
+		{"/a*b$", "/ab/b", true},
+		{"/a*b$", "/ab/c", false},
+		{"/*?sessionid=", "/page?sessionid=1", true},
+		{"/*?sessionid=", "/page?id=1", false},
+		{"*", "/", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			if got := robotsMatch(tt.pattern, tt.path); got != tt.want {
+				t.Fatalf("robotsMatch(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotsRulesAllows(t *testing.T) {
+	tests := []struct {
+		name   string
+		robots string
+		path   string
+		want   bool
+	}{
+		{
+			name:   "no rules",
+			robots: "",
+			path:   "/page",
+			want:   true,
+		},
+		{
+			name:   "disallow all",
+			robots: "User-agent: *\nDisallow: /",
+			path:   "/page",
+			want:   false,
+		},
+		{
+			name:   "robots.txt itself is always allowed",
+			robots: "User-agent: *\nDisallow: /",
+			path:   "/robots.txt",
+			want:   true,
+		},
+		{
+			name:   "empty disallow allows everything",
+			robots: "User-agent: *\nDisallow:",
+			path:   "/page",
+			want:   true,
+		},
+		{
+			name:   "longer allow wins over disallow",
+			robots: "User-agent: *\nDisallow: /docs\nAllow: /docs/public",
+			path:   "/docs/public/page",
+			want:   true,
+		},
+		{
+			name:   "longer disallow wins over allow",
+			robots: "User-agent: *\nAllow: /docs\nDisallow: /docs/private",
+			path:   "/docs/private/page",
+			want:   false,
+		},
+		{
+			name:   "allow wins a tie",
+			robots: "User-agent: *\nDisallow: /page\nAllow: /page",
+			path:   "/page",
+			want:   true,
+		},
+		{
+			name:   "rule order does not matter",
+			robots: "User-agent: *\nAllow: /docs/public\nDisallow: /docs",
+			path:   "/docs/public/page",
+			want:   true,
+		},
+		{
+			name:   "wildcard allow longer than disallow",
+			robots: "User-agent: *\nDisallow: /\nAllow: /*.html$",
+			path:   "/about.html",
+			want:   true,
+		},
+		{
+			name:   "wildcard allow that does not match",
+			robots: "User-agent: *\nDisallow: /\nAllow: /*.html$",
+			path:   "/about.html?print=1",
+			want:   false,
+		},
+		{
+			name:   "wildcard disallow longer than allow",
+			robots: "User-agent: *\nAllow: /shop\nDisallow: /shop/*?sort=",
+			path:   "/shop/shoes?sort=price",
+			want:   false,
+		},
+		{
+			name:   "wildcard disallow that does not match",
+			robots: "User-agent: *\nAllow: /shop\nDisallow: /shop/*?sort=",
+			path:   "/shop/shoes",
+			want:   true,
+		},
+		{
+			name:   "equally long wildcard rules tie to allow",
+			robots: "User-agent: *\nDisallow: /*.pdf\nAllow: /*.pdf",
+			path:   "/file.pdf",
+			want:   true,
+		},
+		{
+			name:   "own group replaces the wildcard group",
+			robots: "User-agent: *\nDisallow: /\n\nUser-agent: PlyoBot\nDisallow: /private",
+			path:   "/page",
+			want:   true,
+		},
+		{
+			name:   "own group that allows everything",
+			robots: "User-agent: *\nDisallow: /\n\nUser-agent: PlyoBot\nDisallow:",
+			path:   "/page",
+			want:   true,
+		},
+		{
+			name:   "product token matches case-insensitively",
+			robots: "User-agent: plyobot\nDisallow: /",
+			path:   "/page",
+			want:   false,
+		},
+		{
+			name:   "other crawler's group does not apply",
+			robots: "User-agent: OtherBot\nDisallow: /",
+			path:   "/page",
+			want:   true,
+		},
+		{
+			name:   "agents sharing a group",
+			robots: "User-agent: OtherBot\nUser-agent: PlyoBot\nDisallow: /private",
+			path:   "/private/page",
+			want:   false,
+		},
+		{
+			name:   "comments are ignored",
+			robots: "User-agent: * # everyone\nDisallow: /private # keep out",
+			path:   "/private",
+			want:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rules := parseRobots(strings.NewReader(tt.robots), "PlyoBot")
+			if got := rules.allows(tt.path); got != tt.want {
+				t.Fatalf("allows(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRobotsRulesDisallowAll(t *testing.T) {
+	rules := robotsRules{disallowAll: true}
+	if rules.allows("/page") {
+		t.Fatal("allows() = true for an unreachable robots.txt, want false")
+	}
+	if !rules.allows("/robots.txt") {
+		t.Fatal("allows(/robots.txt) = false, want true")
+	}
+}
@@ -0,0 +1,299 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/openai/openai-go/v2"
+)
+
+const ScrapeChainToolName = "scrape_page"
+
+const (
+	// scrapeAttemptTimeout bounds a single backend's attempt at a page, so
+	// a hanging backend leaves time for the next one.
+	scrapeAttemptTimeout = 30 * time.Second
+	// breakerWindow is how many recent attempts a backend's error rate is
+	// computed over, and breakerMinAttempts how many it needs before its
+	// breaker may open.
+	breakerWindow      = 20
+	breakerMinAttempts = 5
+)
+
+// PageFetcher is a scraping backend the ScrapeChain can fall back between.
+type PageFetcher interface {
+	GetName() string
+	Fetch(ctx context.Context, url string) (string, error)
+}
+
+// attemptOutcome is how an attempt counts towards a backend's error rate.
+type attemptOutcome int
+
+const (
+	attemptSucceeded attemptOutcome = iota
+	attemptFailed
+	// attemptNeutral is an attempt that says nothing about the backend's
+	// health, such as a site refusing the request.
+	attemptNeutral
+)
+
+// circuitBreaker tracks a backend's recent error rate. Once it reaches the
+// threshold the breaker opens and the backend is skipped for the cool-down;
+// after it a single attempt is let through, which closes the breaker again
+// when it succeeds and reopens it when it fails.
+type circuitBreaker struct {
+	mu        sync.Mutex
+	errorRate float64
+	cooldown  time.Duration
+	// failures holds the outcome of the recent attempts, oldest first.
+	failures  []bool
+	openUntil time.Time
+	probing   bool
+}
+
+// allow reports whether the backend may be tried now.
+func (b *circuitBreaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.openUntil.IsZero() {
+		return true
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+
+	b.probing = true
+	return true
+}
+
+// record counts an attempt the breaker allowed, and reports whether it
+// opened the breaker.
+func (b *circuitBreaker) record(now time.Time, outcome attemptOutcome) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.probing {
+		b.probing = false
+		switch outcome {
+		case attemptSucceeded:
+			b.openUntil = time.Time{}
+			b.failures = nil
+		case attemptFailed:
+			b.openUntil = now.Add(b.cooldown)
+			return true
+		}
+		return false
+	}
+
+	if outcome == attemptNeutral {
+		return false
+	}
+
+	b.failures = append(b.failures, outcome == attemptFailed)
+	if len(b.failures) > breakerWindow {
+		b.failures = b.failures[len(b.failures)-breakerWindow:]
+	}
+	if len(b.failures) < breakerMinAttempts {
+		return false
+	}
+
+	failed := 0
+	for _, f := range b.failures {
+		if f {
+			failed++
+		}
+	}
+	if float64(failed)/float64(len(b.failures)) < b.errorRate {
+		return false
+	}
+
+	b.openUntil = now.Add(b.cooldown)
+	b.failures = nil
+	return true
+}
+
+type scrapeBackend struct {
+	fetcher PageFetcher
+	breaker *circuitBreaker
+}
+
+// ScrapeChain scrapes pages through several backends, trying them in order
+// until one serves the page. A backend failing too often is skipped for a
+// cool-down, so a broken or rate limited service does not slow every page
+// down.
+type ScrapeChain struct {
+	backends []scrapeBackend
+	cache    *Cache
+}
+
+// NewScrapeChain creates a chain trying the fetchers in the given order. A
+// fetcher's breaker opens once errorRate of its recent attempts failed, and
+// stays open for cooldown. Pages served are kept in cache.
+func NewScrapeChain(
+	fetchers []PageFetcher,
+	errorRate float64,
+	cooldown time.Duration,
+	cache *Cache,
+) ScrapeChain {
+	backends := make([]scrapeBackend, 0, len(fetchers))
+	for _, fetcher := range fetchers {
+		backends = append(backends, scrapeBackend{
+			fetcher: fetcher,
+			breaker: &circuitBreaker{errorRate: errorRate, cooldown: cooldown},
+		})
+	}
+
+	return ScrapeChain{backends: backends, cache: cache}
+}
+
+type ScrapeChainRequest struct {
+	URL string `json:"url"`
+}
+
+func (c *ScrapeChain) GetName() string {
+	return ScrapeChainToolName
+}
+
+func (c *ScrapeChain) GetFunctionStructure() openai.ChatCompletionToolUnionParam {
+	param := openai.ChatCompletionToolUnionParam{
+		OfFunction: &openai.ChatCompletionFunctionToolParam{
+			Function: openai.FunctionDefinitionParam{
+				Name: ScrapeChainToolName,
+				Description: openai.String(
+					"Read a web page and return its main content as markdown, with headings and links kept. The page is fetched directly where possible and through a scraping service otherwise; the result says which one served it.",
+				),
+				Parameters: openai.FunctionParameters{
+					"type": "object",
+					"properties": map[string]any{
+						"url": map[string]string{
+							"type":        "string",
+							"description": "The full URL to read (must include http:// or https://)",
+						},
+					},
+					"required": []string{"url"},
+				},
+			},
+		},
+	}
+
+	return param
+}
+
+// Scrape tries the backends in order and returns the page from the first
+// that serves it, with the name of that backend. Pages that may not be
+// fetched, or that do not exist, are not tried on the remaining backends.
+func (c *ScrapeChain) Scrape(ctx context.Context, url string) (string, string, error) {
+	var failures []string
+	for _, backend := range c.backends {
+		name := backend.fetcher.GetName()
+		if !backend.breaker.allow(time.Now()) {
+			failures = append(failures, name+": skipped while cooling down")
+			continue
+		}
+
+		attemptCtx, cancel := context.WithTimeout(ctx, scrapeAttemptTimeout)
+		result, err := backend.fetcher.Fetch(attemptCtx, url)
+		cancel()
+
+		// A scrape that was called off says nothing about the backend.
+		if ctx.Err() != nil {
+			return "", name, ctx.Err()
+		}
+
+		outcome, final := classifyScrapeError(err)
+		if backend.breaker.record(time.Now(), outcome) {
+			slog.Warn("scrape backend failing, skipping it while cooling down",
+				"backend", name, "cooldown", backend.breaker.cooldown)
+		}
+
+		if err == nil {
+			slog.Info("scraped page", "url", url, "backend", name)
+			return result, name, nil
+		}
+		if final {
+			return "", name, err
+		}
+
+		slog.Warn("scrape backend failed, trying the next", "url", url, "backend", name, "error", err)
+		failures = append(failures, fmt.Sprintf("%s: %v", name, err))
+	}
+
+	if len(failures) == 0 {
+		return "", "", errors.New("no scrape backends configured")
+	}
+
+	return "", "", errors.New(strings.Join(failures, "; "))
+}
+
+// classifyScrapeError returns how a backend's error counts towards its
+// breaker, and whether it is final for the page. Refusals and missing pages
+// are final; a site rejecting a direct fetch is left to the paid scrapers
+// without holding it against the direct fetch.
+func classifyScrapeError(err error) (attemptOutcome, bool) {
+	var statusErr *StatusError
+	switch {
+	case err == nil:
+		return attemptSucceeded, false
+	case errors.Is(err, ErrRobotsDisallowed), errors.Is(err, ErrAddressNotPublic),
+		errors.Is(err, ErrUnsupportedScheme), errors.Is(err, ErrUnsupportedContent):
+		return attemptNeutral, true
+	case errors.As(err, &statusErr):
+		if statusErr.StatusCode == http.StatusNotFound || statusErr.StatusCode == http.StatusGone {
+			return attemptNeutral, true
+		}
+		return attemptNeutral, false
+	default:
+		return attemptFailed, false
+	}
+}
+
+// Execute scrapes the page for the model. Pages that cannot or may not be
+// scraped are explained to the model as a Failure rather than returned as
+// errors, which would end the agent's whole research, so the model can pick
+// another page.
+func (c *ScrapeChain) Execute(ctx context.Context, input json.RawMessage) (string, error) {
+	var req ScrapeChainRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if req.URL == "" {
+		return "", fmt.Errorf("url parameter is required")
+	}
+
+	key := NormalizeURL(req.URL)
+	if cached, ok := c.cache.Get(ctx, ScrapeChainToolName, key); ok {
+		// Entries are the serving backend's name followed by the page.
+		backend, page, _ := strings.Cut(cached, "\n")
+		SetServing(ctx, backend, true)
+		return "Fetched with: " + backend + " (cached)\n" + page, nil
+	}
+
+	result, backend, err := c.Scrape(ctx, req.URL)
+	SetServing(ctx, backend, false)
+	switch {
+	case err == nil:
+		c.cache.Put(ctx, ScrapeChainToolName, key, backend+"\n"+result)
+		return "Fetched with: " + backend + "\n" + result, nil
+	case ctx.Err() != nil:
+		return "", ctx.Err()
+	case errors.Is(err, ErrRobotsDisallowed):
+		return "", &Failure{Message: fmt.Sprintf(
+			"Not fetched: the robots.txt of the site disallows fetching %s. Use search results or other pages instead.",
+			req.URL,
+		), Err: err}
+	case errors.Is(err, ErrAddressNotPublic), errors.Is(err, ErrUnsupportedScheme):
+		slog.Warn("refused to scrape url", "url", req.URL, "error", err)
+		return "", &Failure{Message: fmt.Sprintf("Not fetched: %s is not a public web page (%v).", req.URL, err), Err: err}
+	default:
+		return "", &Failure{Message: fmt.Sprintf("Fetching %s failed: %v.", req.URL, err), Err: err}
+	}
+}
@@ -1,37 +1,32 @@
 package tools
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/http"
 	"net/url"
-
-	"github.com/openai/openai-go/v2"
 )
 
 const ScrapingBeeToolName = "scrapingbee_scraper"
 
 type ScrapingBee struct {
 	apiKey string
+	// maxTokens is the budget a scraped page is truncated to, see
+	// ExtractContent.
+	maxTokens int
+	client    *http.Client
 }
 
-func NewScrapingBee(apiKey string) ScrapingBee {
-	return ScrapingBee{apiKey}
-}
-
-type ScrapingBeeRequest struct {
-	URL      string `json:"url"`
-	RenderJS bool   `json:"render_js,omitempty"`
+func NewScrapingBee(apiKey string, maxTokens int, client *http.Client) ScrapingBee {
+	return ScrapingBee{apiKey, maxTokens, client}
 }
 
 func (s *ScrapingBee) GetName() string {
 	return ScrapingBeeToolName
 }
 
-func (s *ScrapingBee) Scrape(targetURL string) ([]byte, error) {
-	slog.Info("#################### SCRAPING BEE ####################")
+func (s *ScrapingBee) Scrape(ctx context.Context, targetURL string) ([]byte, error) {
 	baseURL := "https://app.scrapingbee.com/api/v1"
 
 	params := url.Values{}
@@ -41,13 +36,12 @@ func (s *ScrapingBee) Scrape(targetURL string) ([]byte, error) {
 
 	fullURL := fmt.Sprintf("%s?%s", baseURL, params.Encode())
 
-	httpReq, err := http.NewRequest("GET", fullURL, nil)
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", fullURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := s.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -69,48 +63,13 @@ func (s *ScrapingBee) Scrape(targetURL string) ([]byte, error) {
 	return body, nil
 }
 
-func (s *ScrapingBee) GetFunctionStructure() openai.ChatCompletionToolUnionParam {
-	param := openai.ChatCompletionToolUnionParam{
-		OfFunction: &openai.ChatCompletionFunctionToolParam{
-			Function: openai.FunctionDefinitionParam{
-				Name: ScrapingBeeToolName,
-				Description: openai.String(
-					"Scrape web pages to extract HTML content. Use this when you need to get the content of a specific webpage. Uses the most cost-effective option (no JavaScript rendering).",
-				),
-				Parameters: openai.FunctionParameters{
-					"type": "object",
-					"properties": map[string]any{
-						"url": map[string]string{
-							"type":        "string",
-							"description": "The full URL to scrape (must include http:// or https://)",
-						},
-					},
-					"required": []string{"url"},
-				},
-			},
-		},
-	}
-
-	return param
-}
-
-func (s *ScrapingBee) Execute(input json.RawMessage) (string, error) {
-	var req struct {
-		URL string `json:"url"`
-	}
-
-	if err := json.Unmarshal(input, &req); err != nil {
-		return "", fmt.Errorf("failed to parse input: %w", err)
-	}
-
-	if req.URL == "" {
-		return "", fmt.Errorf("url parameter is required")
-	}
-
-	result, err := s.Scrape(req.URL)
+// Fetch scrapes a page and returns it the way ExtractContent prepares pages
+// for the model.
+func (s *ScrapingBee) Fetch(ctx context.Context, targetURL string) (string, error) {
+	result, err := s.Scrape(ctx, targetURL)
 	if err != nil {
 		return "", err
 	}
 
-	return string(result), nil
+	return ExtractContent(result, targetURL, s.maxTokens), nil
 }
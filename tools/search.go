@@ -0,0 +1,417 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go/v2"
+)
+
+const SearchToolName = "web_search"
+
+const searchMaxPage = 10
+
+// searchTimeRanges are the time ranges a search can be limited to.
+var searchTimeRanges = []string{"any", "hour", "day", "week", "month", "year"}
+
+var (
+	countryCode  = regexp.MustCompile(`^[a-z]{2}$`)
+	languageCode = regexp.MustCompile(`^[a-z]{2}(-[a-z]{2,4})?$`)
+)
+
+// SearchBackend is a search engine the search tool queries.
+type SearchBackend interface {
+	GetName() string
+	Search(ctx context.Context, req SearchRequest) (SearchResults, error)
+}
+
+// SearchRequest is a search the model asks for.
+type SearchRequest struct {
+	Query string `json:"query"`
+	// Type is "web" or "news".
+	Type string `json:"type,omitempty"`
+	// TimeRange is one of searchTimeRanges.
+	TimeRange string `json:"time_range,omitempty"`
+	Country   string `json:"country,omitempty"`
+	Language  string `json:"language,omitempty"`
+	Page      int    `json:"page,omitempty"`
+}
+
+// normalize fills in the defaults and reports options no backend would
+// understand.
+func (r *SearchRequest) normalize() error {
+	r.Type = strings.ToLower(strings.TrimSpace(r.Type))
+	if r.Type == "" {
+		r.Type = "web"
+	}
+	if r.Type != "web" && r.Type != "news" {
+		return fmt.Errorf("type must be web or news, not %q", r.Type)
+	}
+
+	r.TimeRange = strings.ToLower(strings.TrimSpace(r.TimeRange))
+	if r.TimeRange == "" {
+		r.TimeRange = "any"
+	}
+	if !slices.Contains(searchTimeRanges, r.TimeRange) {
+		return fmt.Errorf("time_range must be one of any, hour, day, week, month or year, not %q", r.TimeRange)
+	}
+
+	r.Country = strings.ToLower(strings.TrimSpace(r.Country))
+	if r.Country != "" && !countryCode.MatchString(r.Country) {
+		return fmt.Errorf("country must be a two-letter country code such as de, not %q", r.Country)
+	}
+
+	r.Language = strings.ToLower(strings.TrimSpace(r.Language))
+	if r.Language != "" && !languageCode.MatchString(r.Language) {
+		return fmt.Errorf("language must be a language code such as de or pt-br, not %q", r.Language)
+	}
+
+	if r.Page <= 0 {
+		r.Page = 1
+	}
+	if r.Page > searchMaxPage {
+		return fmt.Errorf("page must be at most %d", searchMaxPage)
+	}
+
+	return nil
+}
+
+// cacheKey identifies the search's results: the normalized query and every
+// option that changes them.
+func (r SearchRequest) cacheKey() string {
+	return strings.Join([]string{
+		NormalizeQuery(r.Query),
+		r.Type,
+		r.TimeRange,
+		r.Country,
+		r.Language,
+		fmt.Sprint(r.Page),
+	}, "|")
+}
+
+// SearchResults are a backend's answer to a search, in the same shape for
+// every backend.
+type SearchResults struct {
+	// Answer is a direct answer to the query, when the backend has one.
+	Answer         string
+	KnowledgeGraph *KnowledgeGraph
+	Results        []SearchResult
+}
+
+// KnowledgeGraph is what the backend knows about the entity a query is
+// about.
+type KnowledgeGraph struct {
+	Title       string
+	Type        string
+	Website     string
+	Description string
+	Attributes  map[string]string
+}
+
+type SearchResult struct {
+	Title   string
+	Link    string
+	Snippet string
+	Date    string
+	// Source is the publisher of a news result.
+	Source string
+}
+
+// WebSearch searches the web through one or more backends, so research does
+// not depend on a single vendor. Backends are asked in order, the next only
+// when one fails, or all at once with their results merged.
+type WebSearch struct {
+	backends []SearchBackend
+	merge    bool
+	cache    *Cache
+}
+
+func NewWebSearch(backends []SearchBackend, merge bool, cache *Cache) WebSearch {
+	return WebSearch{backends, merge, cache}
+}
+
+func (w *WebSearch) GetName() string {
+	return SearchToolName
+}
+
+func (w *WebSearch) GetFunctionStructure() openai.ChatCompletionToolUnionParam {
+	param := openai.ChatCompletionToolUnionParam{
+		OfFunction: &openai.ChatCompletionFunctionToolParam{
+			Function: openai.FunctionDefinitionParam{
+				Name: SearchToolName,
+				Description: openai.String(
+					"Search the web or the news for current information, news, facts, and data. Searches cover any time unless a time range is given, so historical facts such as founding or early funding are found too. Set country and language for companies outside the US.",
+				),
+				Parameters: openai.FunctionParameters{
+					"type": "object",
+					"properties": map[string]any{
+						"query": map[string]string{
+							"type":        "string",
+							"description": "The search query to execute",
+						},
+						"type": map[string]any{
+							"type":        "string",
+							"enum":        []string{"web", "news"},
+							"description": "web for web pages (default), news for news articles",
+						},
+						"time_range": map[string]any{
+							"type":        "string",
+							"enum":        searchTimeRanges,
+							"description": "Only return results from the past hour, day, week, month or year; any (default) for all of them",
+						},
+						"country": map[string]string{
+							"type":        "string",
+							"description": "Two-letter country code to search from, for example de or gb",
+						},
+						"language": map[string]string{
+							"type":        "string",
+							"description": "Language code of the results, for example de or pt-br",
+						},
+						"page": map[string]any{
+							"type":        "integer",
+							"description": fmt.Sprintf("Page of results, from 1 (default) to %d", searchMaxPage),
+						},
+					},
+					"required": []string{"query"},
+				},
+			},
+		},
+	}
+
+	return param
+}
+
+func (w *WebSearch) Execute(ctx context.Context, input json.RawMessage) (string, error) {
+	var req SearchRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if req.Query == "" {
+		return "", fmt.Errorf("query parameter is required")
+	}
+
+	// A bad option is the model's to fix, and an error would end its
+	// research.
+	if err := req.normalize(); err != nil {
+		return "", &Failure{Message: "Invalid search: " + err.Error() + ".", Err: err}
+	}
+
+	key := fmt.Sprintf("%s|merge=%t|%s", strings.Join(w.backendNames(), ","), w.merge, req.cacheKey())
+	if cached, ok := w.cache.Get(ctx, SearchToolName, key); ok {
+		SetServing(ctx, strings.Join(searchBackendsOf(cached), ","), true)
+		return cached, nil
+	}
+
+	results, backends, err := w.Search(ctx, req)
+	SetServing(ctx, strings.Join(backends, ","), false)
+	if err != nil {
+		return "", err
+	}
+
+	formatted := formatSearchResults(req, results, backends)
+	w.cache.Put(ctx, SearchToolName, key, formatted)
+
+	return formatted, nil
+}
+
+// Search runs the search and returns its results with the names of the
+// backends they came from.
+func (w *WebSearch) Search(ctx context.Context, req SearchRequest) (SearchResults, []string, error) {
+	if w.merge {
+		return w.searchAll(ctx, req)
+	}
+
+	var errs []error
+	for _, backend := range w.backends {
+		results, err := backend.Search(ctx, req)
+		if err == nil {
+			return results, []string{backend.GetName()}, nil
+		}
+
+		slog.Warn("search backend failed, trying the next", "backend", backend.GetName(), "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", backend.GetName(), err))
+	}
+
+	if len(errs) == 0 {
+		return SearchResults{}, nil, errors.New("no search backends configured")
+	}
+
+	return SearchResults{}, nil, errors.Join(errs...)
+}
+
+// searchAll asks every backend at once and merges the results of those
+// that answered.
+func (w *WebSearch) searchAll(ctx context.Context, req SearchRequest) (SearchResults, []string, error) {
+	all := make([]SearchResults, len(w.backends))
+	errs := make([]error, len(w.backends))
+
+	var wg sync.WaitGroup
+	for i, backend := range w.backends {
+		wg.Go(func() {
+			all[i], errs[i] = backend.Search(ctx, req)
+		})
+	}
+	wg.Wait()
+
+	var answered []SearchResults
+	var names []string
+	for i, backend := range w.backends {
+		if errs[i] != nil {
+			slog.Warn("search backend failed", "backend", backend.GetName(), "error", errs[i])
+			errs[i] = fmt.Errorf("%s: %w", backend.GetName(), errs[i])
+			continue
+		}
+		answered = append(answered, all[i])
+		names = append(names, backend.GetName())
+	}
+
+	if len(answered) == 0 {
+		if len(w.backends) == 0 {
+			return SearchResults{}, nil, errors.New("no search backends configured")
+		}
+		return SearchResults{}, nil, errors.Join(errs...)
+	}
+
+	return mergeSearchResults(answered), names, nil
+}
+
+func (w *WebSearch) backendNames() []string {
+	names := make([]string, len(w.backends))
+	for i, backend := range w.backends {
+		names[i] = backend.GetName()
+	}
+
+	return names
+}
+
+// mergeSearchResults interleaves the results of several backends by rank,
+// keeping the first of results linking to the same page. The answer and
+// knowledge graph are taken from the first backend that has one.
+func mergeSearchResults(all []SearchResults) SearchResults {
+	var merged SearchResults
+	seen := map[string]bool{}
+
+	for _, results := range all {
+		if merged.Answer == "" {
+			merged.Answer = results.Answer
+		}
+		if merged.KnowledgeGraph == nil {
+			merged.KnowledgeGraph = results.KnowledgeGraph
+		}
+	}
+
+	for rank := 0; ; rank++ {
+		more := false
+		for _, results := range all {
+			if rank >= len(results.Results) {
+				continue
+			}
+			more = true
+
+			result := results.Results[rank]
+			key := NormalizeURL(result.Link)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			merged.Results = append(merged.Results, result)
+		}
+		if !more {
+			break
+		}
+	}
+
+	return merged
+}
+
+// searchBackendsOf returns the backends the results formatted by
+// formatSearchResults came from, as its first line names them.
+func searchBackendsOf(formatted string) []string {
+	header, _, _ := strings.Cut(formatted, "\n")
+	// The query comes earlier in the line and may hold ", from " itself.
+	i := strings.LastIndex(header, ", from ")
+	if i < 0 {
+		return nil
+	}
+
+	return strings.Split(strings.TrimSuffix(header[i+len(", from "):], ")"), " and ")
+}
+
+// formatSearchResults turns search results into a compact list the model
+// reads more easily than a backend's raw JSON.
+func formatSearchResults(req SearchRequest, results SearchResults, backends []string) string {
+	var out strings.Builder
+	when := "any time"
+	if req.TimeRange != "any" {
+		when = "past " + req.TimeRange
+	}
+	label := map[string]string{"web": "Web", "news": "News"}[req.Type]
+	fmt.Fprintf(&out, "%s results for %q (%s", label, req.Query, when)
+	if req.Country != "" {
+		fmt.Fprintf(&out, ", country %s", req.Country)
+	}
+	if req.Language != "" {
+		fmt.Fprintf(&out, ", language %s", req.Language)
+	}
+	fmt.Fprintf(&out, ", page %d, from %s)\n", req.Page, strings.Join(backends, " and "))
+
+	if results.Answer != "" {
+		fmt.Fprintf(&out, "\nAnswer: %s\n", oneLine(results.Answer))
+	}
+
+	if graph := results.KnowledgeGraph; graph != nil && graph.Title != "" {
+		fmt.Fprintf(&out, "\nKnowledge graph: %s", graph.Title)
+		if graph.Type != "" {
+			fmt.Fprintf(&out, " (%s)", graph.Type)
+		}
+		out.WriteString("\n")
+		if graph.Website != "" {
+			fmt.Fprintf(&out, "Website: %s\n", graph.Website)
+		}
+		if graph.Description != "" {
+			fmt.Fprintf(&out, "%s\n", oneLine(graph.Description))
+		}
+		for _, name := range slices.Sorted(maps.Keys(graph.Attributes)) {
+			fmt.Fprintf(&out, "- %s: %s\n", name, oneLine(graph.Attributes[name]))
+		}
+	}
+
+	if len(results.Results) == 0 {
+		out.WriteString("\nNo results.\n")
+		return out.String()
+	}
+
+	out.WriteString("\n")
+	for i, result := range results.Results {
+		fmt.Fprintf(&out, "%d. %s\n", i+1, oneLine(result.Title))
+		fmt.Fprintf(&out, "   %s\n", result.Link)
+
+		var meta []string
+		for _, m := range []string{result.Source, result.Date} {
+			if m != "" {
+				meta = append(meta, m)
+			}
+		}
+		if len(meta) > 0 {
+			fmt.Fprintf(&out, "   %s\n", strings.Join(meta, ", "))
+		}
+		if result.Snippet != "" {
+			fmt.Fprintf(&out, "   %s\n", oneLine(result.Snippet))
+		}
+	}
+
+	return out.String()
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const SearXNGBackendName = "searxng"
+
+// searxngTimeRanges map the search time ranges to SearXNG's, which has none
+// shorter than a day.
+var searxngTimeRanges = map[string]string{
+	"any":   "",
+	"hour":  "day",
+	"day":   "day",
+	"week":  "week",
+	"month": "month",
+	"year":  "year",
+}
+
+// SearXNG searches through a SearXNG instance, or any service offering its
+// JSON API, which can be self-hosted. The instance must have the json
+// format enabled.
+type SearXNG struct {
+	baseURL string
+	client  *http.Client
+}
+
+func NewSearXNG(baseURL string, client *http.Client) SearXNG {
+	return SearXNG{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  client,
+	}
+}
+
+// searxngResponse is the part of SearXNG's JSON response the backend uses.
+type searxngResponse struct {
+	// Answers are strings in older versions of SearXNG and objects in
+	// newer ones.
+	Answers   []json.RawMessage `json:"answers"`
+	Infoboxes []struct {
+		Infobox string `json:"infobox"`
+		Content string `json:"content"`
+		URLs    []struct {
+			Title string `json:"title"`
+			URL   string `json:"url"`
+		} `json:"urls"`
+		Attributes []struct {
+			Label string `json:"label"`
+			Value any    `json:"value"`
+		} `json:"attributes"`
+	} `json:"infoboxes"`
+	Results []struct {
+		Title         string `json:"title"`
+		URL           string `json:"url"`
+		Content       string `json:"content"`
+		PublishedDate string `json:"publishedDate"`
+	} `json:"results"`
+}
+
+func (s *SearXNG) GetName() string {
+	return SearXNGBackendName
+}
+
+// Search runs the search on the SearXNG instance and returns its results.
+func (s *SearXNG) Search(ctx context.Context, req SearchRequest) (SearchResults, error) {
+	params := url.Values{}
+	params.Set("q", req.Query)
+	params.Set("format", "json")
+	params.Set("pageno", strconv.Itoa(req.Page))
+	if req.Type == "news" {
+		params.Set("categories", "news")
+	} else {
+		params.Set("categories", "general")
+	}
+	if timeRange := searxngTimeRanges[req.TimeRange]; timeRange != "" {
+		params.Set("time_range", timeRange)
+	}
+	// SearXNG has no country of its own; it is part of the language, as in
+	// de-CH.
+	switch {
+	case req.Language != "" && req.Country != "" && !strings.Contains(req.Language, "-"):
+		params.Set("language", req.Language+"-"+strings.ToUpper(req.Country))
+	case req.Language != "":
+		params.Set("language", req.Language)
+	}
+
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
+		"GET",
+		s.baseURL+"/search?"+params.Encode(),
+		nil,
+	)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := s.client.Do(httpReq)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return SearchResults{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return SearchResults{}, fmt.Errorf(
+			"API request failed with status %d: %s",
+			resp.StatusCode,
+			string(body),
+		)
+	}
+
+	var page searxngResponse
+	if err := json.Unmarshal(body, &page); err != nil {
+		return SearchResults{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	var results SearchResults
+	for _, raw := range page.Answers {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			var answer struct {
+				Answer string `json:"answer"`
+			}
+			if err := json.Unmarshal(raw, &answer); err == nil {
+				text = answer.Answer
+			}
+		}
+		if text != "" {
+			results.Answer = text
+			break
+		}
+	}
+
+	if len(page.Infoboxes) > 0 && page.Infoboxes[0].Infobox != "" {
+		box := page.Infoboxes[0]
+		graph := &KnowledgeGraph{
+			Title:       box.Infobox,
+			Description: box.Content,
+			Attributes:  map[string]string{},
+		}
+		for _, link := range box.URLs {
+			if strings.EqualFold(link.Title, "official website") {
+				graph.Website = link.URL
+			}
+		}
+		for _, attribute := range box.Attributes {
+			if value, ok := attribute.Value.(string); ok && attribute.Label != "" {
+				graph.Attributes[attribute.Label] = value
+			}
+		}
+		results.KnowledgeGraph = graph
+	}
+
+	for _, result := range page.Results {
+		found := SearchResult{
+			Title:   result.Title,
+			Link:    result.URL,
+			Snippet: result.Content,
+			Date:    searxngDate(result.PublishedDate),
+		}
+		// SearXNG names the engine a result came from, not its publisher.
+		if u, err := url.Parse(result.URL); err == nil && req.Type == "news" {
+			found.Source = strings.TrimPrefix(u.Hostname(), "www.")
+		}
+		results.Results = append(results.Results, found)
+	}
+
+	return results, nil
+}
+
+// searxngDate shortens SearXNG's timestamps to the day.
+func searxngDate(published string) string {
+	if t, err := time.Parse("2006-01-02T15:04:05", published); err == nil {
+		return t.Format("2006-01-02")
+	}
+	if t, err := time.Parse(time.RFC3339, published); err == nil {
+		return t.Format("2006-01-02")
+	}
+
+	return published
+}
@@ -2,41 +2,90 @@ package tools
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/http"
-
-	"github.com/openai/openai-go/v2"
 )
 
-const SerperToolName = "serper_search"
+const SerperBackendName = "serper"
+
+// serperEndpoints are Serper's endpoints for each search type.
+var serperEndpoints = map[string]string{
+	"web":  "https://google.serper.dev/search",
+	"news": "https://google.serper.dev/news",
+}
+
+// serperTimeRanges map the search time ranges to Google's tbs parameter;
+// "any" sends none.
+var serperTimeRanges = map[string]string{
+	"any":   "",
+	"hour":  "qdr:h",
+	"day":   "qdr:d",
+	"week":  "qdr:w",
+	"month": "qdr:m",
+	"year":  "qdr:y",
+}
 
+// Serper searches Google through the Serper API.
 type Serper struct {
 	apiKey string
+	client *http.Client
 }
 
-func NewSerper(apiKey string) Serper {
-	return Serper{apiKey}
+func NewSerper(apiKey string, client *http.Client) Serper {
+	return Serper{apiKey, client}
 }
 
 type SerperRequest struct {
 	Query       string `json:"q"`
 	Autocorrect bool   `json:"autocorrect,omitempty"`
 	Tbs         string `json:"tbs,omitempty"`
+	Gl          string `json:"gl,omitempty"`
+	Hl          string `json:"hl,omitempty"`
+	Page        int    `json:"page,omitempty"`
+}
+
+// serperResponse is the part of Serper's web and news responses the
+// backend uses.
+type serperResponse struct {
+	AnswerBox *struct {
+		Title   string `json:"title"`
+		Answer  string `json:"answer"`
+		Snippet string `json:"snippet"`
+	} `json:"answerBox"`
+	KnowledgeGraph *struct {
+		Title       string            `json:"title"`
+		Type        string            `json:"type"`
+		Website     string            `json:"website"`
+		Description string            `json:"description"`
+		Attributes  map[string]string `json:"attributes"`
+	} `json:"knowledgeGraph"`
+	Organic []serperResult `json:"organic"`
+	News    []serperResult `json:"news"`
+}
+
+type serperResult struct {
+	Title   string `json:"title"`
+	Link    string `json:"link"`
+	Snippet string `json:"snippet"`
+	Date    string `json:"date"`
+	Source  string `json:"source"`
 }
 
 func (s *Serper) GetName() string {
-	return SerperToolName
+	return SerperBackendName
 }
 
-func (s *Serper) Query(query string) ([]byte, error) {
-	slog.Info("#################### SERPER CALLED ####################")
+func (s *Serper) Query(ctx context.Context, search SearchRequest) ([]byte, error) {
 	req := SerperRequest{
-		Query:       query,
+		Query:       search.Query,
 		Autocorrect: false,
-		Tbs:         "qdr:y",
+		Tbs:         serperTimeRanges[search.TimeRange],
+		Gl:          search.Country,
+		Hl:          search.Language,
+		Page:        search.Page,
 	}
 
 	jsonData, err := json.Marshal(req)
@@ -44,9 +93,10 @@ func (s *Serper) Query(query string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest(
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
-		"https://google.serper.dev/search",
+		serperEndpoints[search.Type],
 		bytes.NewBuffer(jsonData),
 	)
 	if err != nil {
@@ -55,9 +105,10 @@ func (s *Serper) Query(query string) ([]byte, error) {
 
 	httpReq.Header.Set("X-API-KEY", s.apiKey)
 	httpReq.Header.Set("Content-Type", "application/json")
+	// A search changes nothing, so a failed one can be retried.
+	httpReq.Header["Idempotency-Key"] = nil
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := s.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -79,48 +130,42 @@ func (s *Serper) Query(query string) ([]byte, error) {
 	return body, nil
 }
 
-func (s *Serper) GetFunctionStructure() openai.ChatCompletionToolUnionParam {
-	param := openai.ChatCompletionToolUnionParam{
-		OfFunction: &openai.ChatCompletionFunctionToolParam{
-			Function: openai.FunctionDefinitionParam{
-				Name: SerperToolName,
-				Description: openai.String(
-					"Search the web for current information, news, facts, and data. Use this when you need recent information, current events, or want to verify information from the web.",
-				),
-				Parameters: openai.FunctionParameters{
-					"type": "object",
-					"properties": map[string]any{
-						"query": map[string]string{
-							"type":        "string",
-							"description": "The search query to execute",
-						},
-					},
-					"required": []string{"query"},
-				},
-			},
-		},
+// Search runs the search on Serper and returns its results.
+func (s *Serper) Search(ctx context.Context, req SearchRequest) (SearchResults, error) {
+	body, err := s.Query(ctx, req)
+	if err != nil {
+		return SearchResults{}, err
 	}
 
-	return param
-}
-
-func (s *Serper) Execute(input json.RawMessage) (string, error) {
-	var req struct {
-		Query string `json:"query"`
+	var resp serperResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return SearchResults{}, fmt.Errorf("failed to parse response: %w", err)
 	}
 
-	if err := json.Unmarshal(input, &req); err != nil {
-		return "", fmt.Errorf("failed to parse input: %w", err)
+	var results SearchResults
+	if box := resp.AnswerBox; box != nil {
+		results.Answer = box.Answer
+		if results.Answer == "" {
+			results.Answer = box.Snippet
+		}
 	}
-
-	if req.Query == "" {
-		return "", fmt.Errorf("query parameter is required")
+	if graph := resp.KnowledgeGraph; graph != nil && graph.Title != "" {
+		results.KnowledgeGraph = &KnowledgeGraph{
+			Title:       graph.Title,
+			Type:        graph.Type,
+			Website:     graph.Website,
+			Description: graph.Description,
+			Attributes:  graph.Attributes,
+		}
 	}
 
-	result, err := s.Query(req.Query)
-	if err != nil {
-		return "", err
+	found := resp.Organic
+	if req.Type == "news" {
+		found = resp.News
+	}
+	for _, result := range found {
+		results.Results = append(results.Results, SearchResult(result))
 	}
 
-	return string(result), nil
+	return results, nil
 }
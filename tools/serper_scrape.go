@@ -2,37 +2,49 @@ package tools
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log/slog"
 	"net/http"
-
-	"github.com/openai/openai-go/v2"
 )
 
 const SerperScrapeToolName = "serper_scrape"
 
 type SerperScrape struct {
 	apiKey string
+	// maxTokens is the budget a scraped page is truncated to, see
+	// ExtractContent.
+	maxTokens int
+	client    *http.Client
 }
 
-func NewSerperScrape(apiKey string) SerperScrape {
-	return SerperScrape{apiKey}
+func NewSerperScrape(apiKey string, maxTokens int, client *http.Client) SerperScrape {
+	return SerperScrape{apiKey, maxTokens, client}
 }
 
 type SerperScrapeRequest struct {
-	URL string `json:"url"`
+	URL             string `json:"url"`
+	IncludeMarkdown bool   `json:"includeMarkdown"`
+}
+
+// serperScrapeResponse is the part of Serper's scrape response the tool
+// uses. Serper extracts the page itself, as plain text and, when asked for
+// it, as markdown with headings and links.
+type serperScrapeResponse struct {
+	Text     string         `json:"text"`
+	Markdown string         `json:"markdown"`
+	Metadata map[string]any `json:"metadata"`
 }
 
 func (s *SerperScrape) GetName() string {
 	return SerperScrapeToolName
 }
 
-func (s *SerperScrape) Scrape(url string) ([]byte, error) {
-	slog.Info("#################### SERPER SCRAPE ####################")
+func (s *SerperScrape) Scrape(ctx context.Context, url string) ([]byte, error) {
 	req := SerperScrapeRequest{
-		URL: url,
+		URL:             url,
+		IncludeMarkdown: true,
 	}
 
 	jsonData, err := json.Marshal(req)
@@ -40,7 +52,8 @@ func (s *SerperScrape) Scrape(url string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	httpReq, err := http.NewRequest(
+	httpReq, err := http.NewRequestWithContext(
+		ctx,
 		"POST",
 		"https://scrape.serper.dev",
 		bytes.NewBuffer(jsonData),
@@ -51,9 +64,10 @@ func (s *SerperScrape) Scrape(url string) ([]byte, error) {
 
 	httpReq.Header.Set("X-API-KEY", s.apiKey)
 	httpReq.Header.Set("Content-Type", "application/json")
+	// Scraping changes nothing, so a failed scrape can be retried.
+	httpReq.Header["Idempotency-Key"] = nil
 
-	client := &http.Client{}
-	resp, err := client.Do(httpReq)
+	resp, err := s.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to make request: %w", err)
 	}
@@ -75,48 +89,28 @@ func (s *SerperScrape) Scrape(url string) ([]byte, error) {
 	return body, nil
 }
 
-func (s *SerperScrape) GetFunctionStructure() openai.ChatCompletionToolUnionParam {
-	param := openai.ChatCompletionToolUnionParam{
-		OfFunction: &openai.ChatCompletionFunctionToolParam{
-			Function: openai.FunctionDefinitionParam{
-				Name: SerperScrapeToolName,
-				Description: openai.String(
-					"Scrape content from any website URL. Use this to extract text, data, and information from web pages.",
-				),
-				Parameters: openai.FunctionParameters{
-					"type": "object",
-					"properties": map[string]any{
-						"url": map[string]string{
-							"type":        "string",
-							"description": "The URL of the website to scrape",
-						},
-					},
-					"required": []string{"url"},
-				},
-			},
-		},
-	}
-
-	return param
-}
-
-func (s *SerperScrape) Execute(input json.RawMessage) (string, error) {
-	var req struct {
-		URL string `json:"url"`
+// Fetch scrapes a page and returns Serper's markdown of it, falling back to
+// ExtractContent when Serper returned none.
+func (s *SerperScrape) Fetch(ctx context.Context, url string) (string, error) {
+	result, err := s.Scrape(ctx, url)
+	if err != nil {
+		return "", err
 	}
 
-	if err := json.Unmarshal(input, &req); err != nil {
-		return "", fmt.Errorf("failed to parse input: %w", err)
+	var page serperScrapeResponse
+	if err := json.Unmarshal(result, &page); err != nil || (page.Markdown == "" && page.Text == "") {
+		return ExtractContent(result, url, s.maxTokens), nil
 	}
 
-	if req.URL == "" {
-		return "", fmt.Errorf("url parameter is required")
+	content := page.Markdown
+	if content == "" {
+		content = page.Text
 	}
 
-	result, err := s.Scrape(req.URL)
-	if err != nil {
-		return "", err
+	header := "URL: " + url + "\n\n"
+	if title, ok := page.Metadata["title"].(string); ok && title != "" {
+		header = "Title: " + title + "\n" + header
 	}
 
-	return string(result), nil
+	return truncateToTokens(header+cleanMarkdown(content), s.maxTokens), nil
 }
@@ -0,0 +1,419 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/openai/openai-go/v2"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+const SiteCrawlToolName = "site_crawl"
+
+const (
+	// siteCrawlDelay is waited between two requests to the site.
+	siteCrawlDelay   = time.Second
+	siteCrawlTimeout = 3 * time.Minute
+	// siteCrawlPageLimit caps the pages a model may ask for.
+	siteCrawlPageLimit = 20
+	// siteCrawlMinPageTokens is the least a page's digest is cut down to,
+	// however many pages share the budget.
+	siteCrawlMinPageTokens = 250
+	sitemapMaxFiles        = 3
+	sitemapMaxURLs         = 500
+)
+
+// pageValues ranks the pages that tell most about a company, by the words
+// in their path. A path matching none is crawled only when pages are left.
+var pageValues = map[string]int{
+	"about": 100, "company": 95, "who": 90, "story": 90, "mission": 90,
+	"team": 85, "leadership": 85, "management": 85, "founders": 85, "people": 80,
+	"pricing": 75, "plans": 70, "price": 70,
+	"customers": 65, "clients": 65, "case": 60, "testimonials": 55, "success": 55,
+	"careers": 50, "jobs": 50, "hiring": 45, "join": 45,
+	"press": 40, "newsroom": 40, "news": 35, "media": 30, "investors": 40,
+	"product": 30, "products": 30, "solutions": 30, "platform": 30, "features": 25, "services": 30,
+	"legal": 20, "imprint": 20, "impressum": 20, "terms": 15, "privacy": 15,
+	"contact": 15,
+}
+
+// pageExtensions are the file extensions of paths that can be pages, next
+// to paths without one.
+var pageExtensions = map[string]bool{
+	".html": true, ".htm": true, ".php": true, ".asp": true, ".aspx": true, ".jsp": true,
+}
+
+// SiteCrawl reads the pages of a company's website most telling about the
+// company: it discovers them through the sitemap and the links of the pages
+// it reads, fetches the most valuable ones one at a time through web_fetch,
+// and returns a digest of each.
+type SiteCrawl struct {
+	fetch func(ctx context.Context, url string) (fetchedPage, error)
+	// maxPages is how many pages are read when the model does not say.
+	maxPages int
+	// maxTokens is the budget the whole digest is truncated to, shared by
+	// the pages read.
+	maxTokens int
+	cache     *Cache
+}
+
+func NewSiteCrawl(webFetch *WebFetch, maxPages int, maxTokens int, cache *Cache) SiteCrawl {
+	return SiteCrawl{
+		fetch:     webFetch.fetchPage,
+		maxPages:  maxPages,
+		maxTokens: maxTokens,
+		cache:     cache,
+	}
+}
+
+type SiteCrawlRequest struct {
+	URL      string `json:"url"`
+	MaxPages int    `json:"max_pages,omitempty"`
+}
+
+func (s *SiteCrawl) GetName() string {
+	return SiteCrawlToolName
+}
+
+func (s *SiteCrawl) GetFunctionStructure() openai.ChatCompletionToolUnionParam {
+	param := openai.ChatCompletionToolUnionParam{
+		OfFunction: &openai.ChatCompletionFunctionToolParam{
+			Function: openai.FunctionDefinitionParam{
+				Name: SiteCrawlToolName,
+				Description: openai.String(
+					"Crawl a company's website and return a condensed digest of its most informative pages: about, team, pricing, customers, careers, press and legal pages first. Use this once per company to get a complete picture of its website, then use scrape_page for any page you need in full.",
+				),
+				Parameters: openai.FunctionParameters{
+					"type": "object",
+					"properties": map[string]any{
+						"url": map[string]string{
+							"type":        "string",
+							"description": "The company's website, for example https://example.com",
+						},
+						"max_pages": map[string]any{
+							"type":        "integer",
+							"description": fmt.Sprintf("How many pages to read, at most %d", siteCrawlPageLimit),
+						},
+					},
+					"required": []string{"url"},
+				},
+			},
+		},
+	}
+
+	return param
+}
+
+// crawlCandidate is a page of the site the crawl knows of.
+type crawlCandidate struct {
+	url   string
+	value int
+}
+
+// crawledPage is the digest of a page read.
+type crawledPage struct {
+	url     string
+	content string
+}
+
+// Crawl reads up to maxPages pages of the site rawURL is on, starting with
+// rawURL itself, and returns their digest.
+func (s *SiteCrawl) Crawl(ctx context.Context, rawURL string, maxPages int) (string, error) {
+	start, err := s.fetch(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+	if !start.isHTML() {
+		return "", fmt.Errorf("%w %s", ErrUnsupportedContent, start.mediaType)
+	}
+
+	startURL, err := url.Parse(start.url)
+	if err != nil {
+		return "", fmt.Errorf("invalid url: %w", err)
+	}
+	site := siteHost(startURL.Host)
+
+	seen := map[string]bool{NormalizeURL(start.url): true, NormalizeURL(rawURL): true}
+	var candidates []crawlCandidate
+	discover := func(link string) {
+		u, err := url.Parse(link)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || siteHost(u.Host) != site {
+			return
+		}
+		if ext := strings.ToLower(path.Ext(u.Path)); ext != "" && !pageExtensions[ext] {
+			return
+		}
+		u.Fragment = ""
+		key := NormalizeURL(u.String())
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		candidates = append(candidates, crawlCandidate{url: u.String(), value: pageValue(u)})
+	}
+
+	pages := []crawledPage{{url: start.url, content: htmlToMarkdown(start.body, start.url)}}
+	for _, link := range pageLinks(start) {
+		discover(link)
+	}
+
+	sitemapURLs := s.sitemapURLs(ctx, startURL.Scheme+"://"+startURL.Host)
+	for _, link := range sitemapURLs {
+		discover(link)
+	}
+
+	var failures []string
+	for len(pages) < maxPages && len(candidates) > 0 && len(failures) < maxPages {
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].value != candidates[j].value {
+				return candidates[i].value > candidates[j].value
+			}
+			return len(candidates[i].url) < len(candidates[j].url)
+		})
+		next := candidates[0]
+		candidates = candidates[1:]
+
+		if err := crawlWait(ctx); err != nil {
+			break
+		}
+
+		page, err := s.fetch(ctx, next.url)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s (%v)", next.url, err))
+			continue
+		}
+		if !page.isHTML() {
+			continue
+		}
+
+		pages = append(pages, crawledPage{url: page.url, content: htmlToMarkdown(page.body, page.url)})
+		for _, link := range pageLinks(page) {
+			discover(link)
+		}
+	}
+
+	slog.Info("crawled site", "site", site, "pages", len(pages), "sitemap_urls", len(sitemapURLs), "failures", len(failures))
+
+	return s.digest(site, pages, len(seen), len(sitemapURLs) > 0, failures), nil
+}
+
+// digest lists the pages read, each cut down to an equal share of the
+// token budget.
+func (s *SiteCrawl) digest(
+	site string,
+	pages []crawledPage,
+	discovered int,
+	hasSitemap bool,
+	failures []string,
+) string {
+	perPage := 0
+	if s.maxTokens > 0 {
+		perPage = max(s.maxTokens/len(pages), siteCrawlMinPageTokens)
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "Crawled %d pages of %s, out of %d discovered", len(pages), site, discovered)
+	if hasSitemap {
+		out.WriteString(" through its sitemap and links.\n")
+	} else {
+		out.WriteString(" through its links; it has no sitemap.\n")
+	}
+
+	for _, page := range pages {
+		out.WriteString("\n---\n\n")
+		out.WriteString(truncateToTokens(page.content, perPage))
+		out.WriteString("\n")
+	}
+
+	if len(failures) > 0 {
+		out.WriteString("\n---\n\nNot fetched:\n")
+		for _, failure := range failures {
+			fmt.Fprintf(&out, "- %s\n", failure)
+		}
+	}
+
+	return out.String()
+}
+
+// sitemapURLs returns the page URLs the site's sitemap lists, following a
+// sitemap index to its first few sitemaps. A site without a sitemap has
+// none.
+func (s *SiteCrawl) sitemapURLs(ctx context.Context, root string) []string {
+	var urls []string
+	queue := []string{root + "/sitemap.xml"}
+	for fetched := 0; len(queue) > 0 && fetched < sitemapMaxFiles && len(urls) < sitemapMaxURLs; fetched++ {
+		sitemapURL := queue[0]
+		queue = queue[1:]
+
+		if err := crawlWait(ctx); err != nil {
+			break
+		}
+
+		page, err := s.fetch(ctx, sitemapURL)
+		if err != nil {
+			slog.Debug("failed to fetch sitemap", "url", sitemapURL, "error", err)
+			continue
+		}
+
+		var sitemap struct {
+			URLs []struct {
+				Loc string `xml:"loc"`
+			} `xml:"url"`
+			Sitemaps []struct {
+				Loc string `xml:"loc"`
+			} `xml:"sitemap"`
+		}
+		if err := xml.Unmarshal(page.body, &sitemap); err != nil {
+			slog.Debug("failed to parse sitemap", "url", sitemapURL, "error", err)
+			continue
+		}
+
+		for _, u := range sitemap.URLs {
+			if len(urls) == sitemapMaxURLs {
+				break
+			}
+			urls = append(urls, strings.TrimSpace(u.Loc))
+		}
+		for _, child := range sitemap.Sitemaps {
+			queue = append(queue, strings.TrimSpace(child.Loc))
+		}
+	}
+
+	return urls
+}
+
+// Execute crawls the site for the model. Like scrape_page it explains sites
+// that could not be crawled as a Failure, as an error would end the agent's
+// whole research.
+func (s *SiteCrawl) Execute(ctx context.Context, input json.RawMessage) (string, error) {
+	var req SiteCrawlRequest
+	if err := json.Unmarshal(input, &req); err != nil {
+		return "", fmt.Errorf("failed to parse input: %w", err)
+	}
+
+	if req.URL == "" {
+		return "", fmt.Errorf("url parameter is required")
+	}
+
+	maxPages := req.MaxPages
+	if maxPages <= 0 {
+		maxPages = s.maxPages
+	}
+	maxPages = min(maxPages, siteCrawlPageLimit)
+
+	key := fmt.Sprintf("%s pages=%d", NormalizeURL(req.URL), maxPages)
+	// The crawl only ever fetches pages directly.
+	if cached, ok := s.cache.Get(ctx, SiteCrawlToolName, key); ok {
+		SetServing(ctx, WebFetchToolName, true)
+		return cached, nil
+	}
+	SetServing(ctx, WebFetchToolName, false)
+
+	crawlCtx, cancel := context.WithTimeout(ctx, siteCrawlTimeout)
+	defer cancel()
+
+	result, err := s.Crawl(crawlCtx, req.URL, maxPages)
+	switch {
+	case err == nil:
+		s.cache.Put(ctx, SiteCrawlToolName, key, result)
+		return result, nil
+	case ctx.Err() != nil:
+		return "", ctx.Err()
+	case errors.Is(err, ErrRobotsDisallowed):
+		return "", &Failure{Message: fmt.Sprintf(
+			"Not crawled: the robots.txt of the site disallows fetching %s. Use search results instead.",
+			req.URL,
+		), Err: err}
+	case errors.Is(err, ErrAddressNotPublic), errors.Is(err, ErrUnsupportedScheme):
+		slog.Warn("refused to crawl url", "url", req.URL, "error", err)
+		return "", &Failure{Message: fmt.Sprintf("Not crawled: %s is not a public web page (%v).", req.URL, err), Err: err}
+	default:
+		return "", &Failure{Message: fmt.Sprintf(
+			"Crawling %s failed: %v. Try reading its pages with %s instead.",
+			req.URL,
+			err,
+			ScrapeChainToolName,
+		), Err: err}
+	}
+}
+
+// pageValue ranks a page by the most valuable word in its path, less a
+// little for every level it is nested below the site root.
+func pageValue(u *url.URL) int {
+	value, depth := 0, 0
+	for segment := range strings.SplitSeq(strings.ToLower(u.Path), "/") {
+		segment = strings.TrimSuffix(segment, path.Ext(segment))
+		// Language prefixes such as /en/ or /en-us/ are not a level.
+		if segment == "" || len(segment) == 2 || (len(segment) == 5 && segment[2] == '-') {
+			continue
+		}
+		depth++
+		for word := range strings.FieldsFuncSeq(segment, func(r rune) bool { return r == '-' || r == '_' }) {
+			value = max(value, pageValues[word])
+		}
+	}
+	if u.RawQuery != "" {
+		depth++
+	}
+
+	return value - 5*depth
+}
+
+// siteHost is the host a crawl stays on, without a leading www.
+func siteHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
+
+// pageLinks returns the links of an HTML page, resolved against its URL.
+func pageLinks(page fetchedPage) []string {
+	doc, err := html.Parse(bytes.NewReader(page.body))
+	if err != nil {
+		return nil
+	}
+
+	base, err := url.Parse(page.url)
+	if err != nil {
+		return nil
+	}
+	if href := baseHref(doc); href != "" {
+		if resolved, err := base.Parse(href); err == nil {
+			base = resolved
+		}
+	}
+
+	var links []string
+	walk(doc, func(n *html.Node) bool {
+		if n.Type == html.ElementNode && n.DataAtom == atom.A {
+			if href := strings.TrimSpace(attr(n, "href")); href != "" {
+				if resolved, err := base.Parse(href); err == nil {
+					links = append(links, resolved.String())
+				}
+			}
+		}
+		return true
+	})
+
+	return links
+}
+
+// crawlWait waits between two requests to the site, so a crawl does not
+// burden it.
+func crawlWait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(siteCrawlDelay):
+		return nil
+	}
+}
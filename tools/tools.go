@@ -2,13 +2,18 @@
 package tools
 
 import (
+	"context"
 	"encoding/json"
 
 	"github.com/openai/openai-go/v2"
 )
 
+// Tooler is a tool the model can call. Execute stops when ctx is done, and
+// returns the context's error then instead of a result. Failures the model
+// should hear about and work around are returned as a *Failure; any other
+// error ends the agent's run.
 type Tooler interface {
 	GetName() string
-	Execute(input json.RawMessage) (string, error)
+	Execute(ctx context.Context, input json.RawMessage) (string, error)
 	GetFunctionStructure() openai.ChatCompletionToolUnionParam
 }
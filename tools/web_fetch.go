@@ -0,0 +1,277 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const WebFetchToolName = "web_fetch"
+
+const (
+	webFetchTimeout      = 20 * time.Second
+	webFetchMaxRedirects = 5
+	webFetchMaxBytes     = 5 << 20
+)
+
+var (
+	ErrAddressNotPublic   = errors.New("address is not on the public internet")
+	ErrRobotsDisallowed   = errors.New("robots.txt disallows fetching the page")
+	ErrUnsupportedScheme  = errors.New("only http and https URLs can be fetched")
+	ErrTooManyRedirects   = errors.New("too many redirects")
+	ErrUnsupportedContent = errors.New("unsupported content type")
+)
+
+// StatusError is returned when the site answered a fetch with a non-2xx
+// status, which says something about the page rather than the fetcher.
+type StatusError struct {
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("request failed with status %d", e.StatusCode)
+}
+
+// nonPublicPrefixes are the ranges netip has no predicate for that must not
+// be reachable through a URL a model picked: shared, reserved and
+// documentation ranges, and the IPv6 prefixes that embed IPv4 addresses.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/96"),
+	netip.MustParsePrefix("::ffff:0:0:0/96"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/32"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("fec0::/10"),
+}
+
+// publicAddress reports whether ip is a public internet address, and not a
+// private, loopback, link-local or otherwise internal one.
+func publicAddress(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsValid() || ip.IsUnspecified() || ip.IsLoopback() || ip.IsPrivate() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// PublicAddressControl is a net.Dialer Control func refusing connections to
+// addresses that are not on the public internet. Checking when connecting
+// catches hosts that re-resolve to an internal address after a lookup.
+func PublicAddressControl(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	if !publicAddress(addrPort.Addr()) {
+		return fmt.Errorf("%s: %w", addrPort.Addr().Unmap(), ErrAddressNotPublic)
+	}
+
+	return nil
+}
+
+// ResolvePublicHost fails with ErrAddressNotPublic when host is, or resolves
+// to, an address that is not on the public internet.
+func ResolvePublicHost(ctx context.Context, host string) error {
+	addrs, err := net.DefaultResolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	for _, addr := range addrs {
+		if !publicAddress(addr) {
+			return fmt.Errorf("%s resolves to %s: %w", host, addr.Unmap(), ErrAddressNotPublic)
+		}
+	}
+
+	return nil
+}
+
+// WebFetch fetches pages directly, without a paid scraping service. It
+// honors robots.txt and only connects to public addresses, checked when
+// connecting so a host re-resolving to an internal address is caught too.
+type WebFetch struct {
+	client       *http.Client
+	robotsClient *http.Client
+	robots       *robotsCache
+	userAgent    string
+	// maxTokens is the budget a fetched page is truncated to, see
+	// ExtractContent.
+	maxTokens int
+}
+
+func NewWebFetch(userAgent string, maxTokens int) WebFetch {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: PublicAddressControl,
+	}
+
+	transport := &http.Transport{
+		// Proxies from the environment would be dialed instead of the
+		// target and defeat the address check.
+		Proxy:                  nil,
+		DialContext:            dialer.DialContext,
+		ForceAttemptHTTP2:      true,
+		MaxIdleConns:           20,
+		IdleConnTimeout:        90 * time.Second,
+		TLSHandshakeTimeout:    10 * time.Second,
+		ResponseHeaderTimeout:  15 * time.Second,
+		MaxResponseHeaderBytes: 64 << 10,
+	}
+	// Direct fetches are logged like the other tools' requests, but not
+	// retried: the scrape chain moves on to the paid scrapers instead.
+	logged := newRetryTransport(transport, 0)
+
+	w := WebFetch{
+		robotsClient: &http.Client{Transport: logged, Timeout: webFetchTimeout},
+		robots:       newRobotsCache(),
+		userAgent:    userAgent,
+		maxTokens:    maxTokens,
+	}
+	w.client = &http.Client{
+		Transport: logged,
+		Timeout:   webFetchTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= webFetchMaxRedirects {
+				return ErrTooManyRedirects
+			}
+			if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+				return ErrUnsupportedScheme
+			}
+			if !w.robots.allowed(req.Context(), w.robotsClient, w.userAgent, req.URL) {
+				return fmt.Errorf("redirect to %s: %w", req.URL, ErrRobotsDisallowed)
+			}
+			return nil
+		},
+	}
+
+	return w
+}
+
+func (w *WebFetch) GetName() string {
+	return WebFetchToolName
+}
+
+// fetchedPage is a page as the site served it.
+type fetchedPage struct {
+	// url is where the page was fetched from after redirects.
+	url       string
+	mediaType string
+	body      []byte
+}
+
+// Fetch fetches a page and returns it the way ExtractContent prepares pages
+// for the model. Pages that are neither HTML nor text are refused.
+func (w *WebFetch) Fetch(ctx context.Context, rawURL string) (string, error) {
+	page, err := w.fetchPage(ctx, rawURL)
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case page.isHTML():
+		return ExtractContent(page.body, page.url, w.maxTokens), nil
+	case page.isText():
+		return truncateToTokens("URL: "+page.url+"\n\n"+string(page.body), w.maxTokens), nil
+	default:
+		return "", fmt.Errorf("%w %s", ErrUnsupportedContent, page.mediaType)
+	}
+}
+
+func (p fetchedPage) isHTML() bool {
+	return p.mediaType == "text/html" || p.mediaType == "application/xhtml+xml"
+}
+
+func (p fetchedPage) isText() bool {
+	return strings.HasPrefix(p.mediaType, "text/") || p.mediaType == "application/json" ||
+		strings.HasSuffix(p.mediaType, "+json") || strings.HasSuffix(p.mediaType, "+xml") ||
+		p.mediaType == "application/xml"
+}
+
+// fetchPage fetches a page if it is on a public address and robots.txt
+// allows it, failing with a StatusError unless the site answered with 2xx.
+func (w *WebFetch) fetchPage(ctx context.Context, rawURL string) (fetchedPage, error) {
+	target, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("invalid url: %w", err)
+	}
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return fetchedPage{}, ErrUnsupportedScheme
+	}
+	if target.Hostname() == "" {
+		return fetchedPage{}, fmt.Errorf("invalid url: missing host")
+	}
+	target.User = nil
+	target.Fragment = ""
+
+	// Resolving up front refuses internal hosts before robots.txt is asked
+	// for; the dialer checks again on every connection.
+	if err := ResolvePublicHost(ctx, target.Hostname()); err != nil {
+		return fetchedPage{}, err
+	}
+
+	if !w.robots.allowed(ctx, w.robotsClient, w.userAgent, target) {
+		return fetchedPage{}, ErrRobotsDisallowed
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", w.userAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,text/plain;q=0.9,*/*;q=0.5")
+	req.Header.Set("Accept-Language", "en;q=1.0,*;q=0.5")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, webFetchMaxBytes))
+	if err != nil {
+		return fetchedPage{}, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fetchedPage{}, &StatusError{StatusCode: resp.StatusCode}
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = http.DetectContentType(body)
+	}
+
+	return fetchedPage{url: resp.Request.URL.String(), mediaType: mediaType, body: body}, nil
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestPublicAddress(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"1.1.1.1", true},
+		{"2606:4700:4700::1111", true},
+
+		// IPv4 internal ranges.
+		{"127.0.0.1", false},
+		{"10.0.0.1", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"0.0.0.0", false},
+		{"100.64.0.1", false},
+		{"192.0.0.170", false},
+		{"198.18.0.1", false},
+		{"192.0.2.1", false},
+		{"198.51.100.1", false},
+		{"203.0.113.1", false},
+		{"224.0.0.1", false},
+		{"240.0.0.1", false},
+		{"255.255.255.255", false},
+
+		// IPv6 internal ranges.
+		{"::", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fc00::1", false},
+		{"fd12:3456:789a::1", false},
+		{"fec0::1", false},
+		{"ff02::1", false},
+		{"100::1", false},
+		{"2001:db8::1", false},
+
+		// IPv4-mapped addresses are checked as the IPv4 address they hold.
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:169.254.169.254", false},
+		{"::ffff:10.0.0.1", false},
+		{"::ffff:93.184.216.34", true},
+
+		// IPv4-compatible and IPv4-translated addresses embed an IPv4
+		// address too.
+		{"::127.0.0.1", false},
+		{"::93.184.216.34", false},
+		{"::ffff:0:127.0.0.1", false},
+
+		// NAT64 prefixes reach IPv4 addresses through a translator.
+		{"64:ff9b::127.0.0.1", false},
+		{"64:ff9b::a9fe:a9fe", false},
+		{"64:ff9b::5db8:d822", false},
+		{"64:ff9b:1::a00:1", false},
+
+		// 6to4 and Teredo tunnel to IPv4 addresses.
+		{"2002:7f00:1::1", false},
+		{"2002:a9fe:a9fe::1", false},
+		{"2002:5db8:d822::1", false},
+		{"2001:0:4136:e378:8000:63bf:3fff:fdd2", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			if got := publicAddress(netip.MustParseAddr(tt.addr)); got != tt.want {
+				t.Fatalf("publicAddress(%s) = %v, want %v", tt.addr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPublicAddressZero(t *testing.T) {
+	if publicAddress(netip.Addr{}) {
+		t.Fatal("publicAddress() of the zero address = true, want false")
+	}
+}
+
+func TestResolvePublicHost(t *testing.T) {
+	tests := []struct {
+		host    string
+		wantErr bool
+	}{
+		{host: "93.184.216.34"},
+		{host: "127.0.0.1", wantErr: true},
+		{host: "localhost", wantErr: true},
+		{host: "169.254.169.254", wantErr: true},
+		{host: "::ffff:10.0.0.1", wantErr: true},
+		{host: "fd00::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.host, func(t *testing.T) {
+			err := ResolvePublicHost(context.Background(), tt.host)
+			if tt.wantErr != errors.Is(err, ErrAddressNotPublic) {
+				t.Fatalf("ResolvePublicHost(%q) error = %v, want not public %v", tt.host, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPublicAddressControl(t *testing.T) {
+	tests := []struct {
+		address string
+		wantErr bool
+	}{
+		{address: "93.184.216.34:443"},
+		{address: "127.0.0.1:8080", wantErr: true},
+		{address: "[::1]:443", wantErr: true},
+		{address: "[::ffff:192.168.1.1]:80", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.address, func(t *testing.T) {
+			err := PublicAddressControl("tcp", tt.address, nil)
+			if tt.wantErr != errors.Is(err, ErrAddressNotPublic) {
+				t.Fatalf("PublicAddressControl(%q) error = %v, want not public %v", tt.address, err, tt.wantErr)
+			}
+		})
+	}
+}